@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/actions"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/apiclient"
 	cexec "github.com/RHEcosystemAppEng/cluster-iq/internal/cloud_executors"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/config"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/credentials"
@@ -45,7 +46,7 @@ func NewExecutorAgentService(cfg *config.ExecutorAgentServiceConfig, actionsChan
 	}
 
 	// Creating HTTP Client
-	client := http.Client{Transport: tr}
+	client := http.Client{Transport: apiclient.WithBearerToken(tr, cfg.APIToken)}
 
 	// Creating DB client
 	sqlCli, err := sqlclient.NewSQLClient(cfg.DBURL, logger)
@@ -201,6 +202,13 @@ func (e *ExecutorAgentService) Start() error {
 		})
 
 		target := newAction.GetTarget()
+		if skipped := target.GetSkippedInstances(); len(skipped) > 0 {
+			e.logger.Info("Skipping protected instances",
+				zap.String("action_id", newAction.GetID()),
+				zap.String("cluster_id", target.GetClusterID()),
+				zap.Strings("instances", skipped),
+			)
+		}
 		cexec := *(e.GetExecutor(target.GetAccountName()))
 		if cexec == nil {
 			return fmt.Errorf("there's no Executor available for the requested account")
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/actions"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/apiclient"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/config"
 	cron "github.com/robfig/cron/v3"
 	"go.uber.org/zap"
@@ -57,7 +58,7 @@ func NewScheduleAgentService(cfg *config.ScheduleAgentServiceConfig, actionsChan
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
-	client := http.Client{Transport: tr}
+	client := http.Client{Transport: apiclient.WithBearerToken(tr, cfg.APIToken)}
 
 	return &ScheduleAgentService{
 		cfg: cfg,
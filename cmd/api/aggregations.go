@@ -0,0 +1,375 @@
+package main
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+)
+
+// clusterAccounts maps every cluster ID to the name of its account
+func clusterAccounts(clusters []inventory.Cluster) map[string]string {
+	accounts := make(map[string]string, len(clusters))
+	for _, cluster := range clusters {
+		accounts[cluster.ID] = cluster.AccountName
+	}
+	return accounts
+}
+
+// costByStatus sums the total cost of the instances on each status, grouped
+// by key (e.g. the cluster ID). Instances without cost are summed as zero, so
+// their status is still reported.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance.
+// - key: Returns the group of an instance.
+//
+// Returns:
+// - The cost of every status, indexed by group.
+func costByStatus(instances []inventory.Instance, key func(inventory.Instance) string) map[string]map[inventory.InstanceStatus]float64 {
+	costs := make(map[string]map[inventory.InstanceStatus]float64)
+	for _, instance := range instances {
+		group := key(instance)
+		if costs[group] == nil {
+			costs[group] = make(map[inventory.InstanceStatus]float64)
+		}
+
+		cost := instance.TotalCost
+		if math.IsNaN(cost) {
+			cost = 0
+		}
+		costs[group][inventory.ProviderState(instance.Status).Status()] += cost
+	}
+	return costs
+}
+
+// sumCostByStatus merges the costs by status of several resources
+func sumCostByStatus(costs ...map[inventory.InstanceStatus]float64) map[inventory.InstanceStatus]float64 {
+	total := make(map[inventory.InstanceStatus]float64)
+	for _, resourceCosts := range costs {
+		for status, cost := range resourceCosts {
+			total[status] += cost
+		}
+	}
+	return total
+}
+
+// instanceStateStats counts the instances and sums their total cost on each
+// status, in a single pass over the instances. Only the statuses with
+// instances are reported. Instances without cost are summed as zero.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance.
+//
+// Returns:
+// - The stats of every status found on the instances.
+// - The stats of all the instances.
+func instanceStateStats(instances []inventory.Instance) (map[inventory.InstanceStatus]InstanceStateStats, InstanceStateStats) {
+	states := make(map[inventory.InstanceStatus]InstanceStateStats)
+	var total InstanceStateStats
+	for _, instance := range instances {
+		cost := instance.TotalCost
+		if math.IsNaN(cost) {
+			cost = 0
+		}
+
+		status := inventory.ProviderState(instance.Status).Status()
+		stats := states[status]
+		stats.Count++
+		stats.TotalCost += cost
+		states[status] = stats
+
+		total.Count++
+		total.TotalCost += cost
+	}
+	return states, total
+}
+
+// findCostOutliers returns the instances whose total cost is more than
+// deviations standard deviations above the mean instance cost of their
+// account, sorted by deviation descending. Accounts where every instance costs
+// the same have no outliers.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance.
+// - accounts: Account name of every cluster, indexed by cluster ID.
+// - deviations: Number of standard deviations above the mean for considering an instance an outlier.
+//
+// Returns:
+// - A slice of CostOutlier.
+func findCostOutliers(instances []inventory.Instance, accounts map[string]string, deviations float64) []CostOutlier {
+	type accountCosts struct {
+		sum, sumSquares float64
+		count           int
+	}
+
+	// First pass: per account totals
+	costs := make(map[string]*accountCosts)
+	for _, instance := range instances {
+		account := accounts[instance.ClusterID]
+		if costs[account] == nil {
+			costs[account] = &accountCosts{}
+		}
+		costs[account].sum += instance.TotalCost
+		costs[account].sumSquares += instance.TotalCost * instance.TotalCost
+		costs[account].count++
+	}
+
+	// Second pass: instances above the threshold
+	outliers := make([]CostOutlier, 0)
+	for _, instance := range instances {
+		account := accounts[instance.ClusterID]
+		ac := costs[account]
+		mean := ac.sum / float64(ac.count)
+		stdDev := math.Sqrt(math.Max(ac.sumSquares/float64(ac.count)-mean*mean, 0))
+		if stdDev == 0 {
+			continue
+		}
+
+		if deviation := (instance.TotalCost - mean) / stdDev; deviation > deviations {
+			outliers = append(outliers, CostOutlier{
+				Instance:          instance,
+				AccountName:       account,
+				AccountMeanCost:   mean,
+				AccountCostStdDev: stdDev,
+				Deviation:         deviation,
+			})
+		}
+	}
+
+	slices.SortStableFunc(outliers, func(a, b CostOutlier) int {
+		return cmp.Compare(b.Deviation, a.Deviation)
+	})
+	return outliers
+}
+
+// costByDimension aggregates the instances total cost by the value of a cost
+// dimension, resolving its tag key for the provider of every instance.
+// Instances without the tag, or whose provider doesn't map the dimension, are
+// aggregated on the empty value. The result is sorted by cost descending.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance including their tags.
+// - dimensions: Cost dimensions mapping.
+// - dimension: Name of the dimension.
+//
+// Returns:
+// - A slice of DimensionCost.
+func costByDimension(instances []inventory.Instance, dimensions inventory.CostDimensions, dimension string) []DimensionCost {
+	index := make(map[string]int)
+	costs := make([]DimensionCost, 0)
+	for _, instance := range instances {
+		var value string
+		if key, ok := dimensions.TagKey(dimension, instance.Provider); ok {
+			if tag := inventory.LookForTagByKey(key, instance.Tags); tag != nil {
+				value = tag.Value
+			}
+		}
+
+		i, ok := index[value]
+		if !ok {
+			i = len(costs)
+			index[value] = i
+			costs = append(costs, DimensionCost{Value: value})
+		}
+		costs[i].TotalCost += instance.TotalCost
+		costs[i].Instances++
+	}
+
+	slices.SortStableFunc(costs, func(a, b DimensionCost) int {
+		if c := cmp.Compare(b.TotalCost, a.TotalCost); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return costs
+}
+
+// clusterLabels returns the labels of every cluster, indexed by cluster ID and
+// key. The labels of a cluster are the tags of its instances, keeping the
+// first value found for every key as the cluster tags endpoint does.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance including their tags.
+//
+// Returns:
+// - The labels of every cluster.
+func clusterLabels(instances []inventory.Instance) map[string]map[string]string {
+	labels := make(map[string]map[string]string)
+	for _, instance := range instances {
+		if labels[instance.ClusterID] == nil {
+			labels[instance.ClusterID] = make(map[string]string)
+		}
+		for _, tag := range instance.Tags {
+			if _, ok := labels[instance.ClusterID][tag.Key]; !ok {
+				labels[instance.ClusterID][tag.Key] = tag.Value
+			}
+		}
+	}
+	return labels
+}
+
+// embedClusterAccounts sets on every cluster the descriptor of its account.
+// Clusters whose account is not on the inventory are left without it.
+//
+// Parameters:
+// - clusters: Clusters updated in place.
+// - accounts: Accounts of the inventory.
+func embedClusterAccounts(clusters []inventory.Cluster, accounts []inventory.Account) {
+	descriptors := make(map[string]*inventory.ClusterAccount, len(accounts))
+	for _, account := range accounts {
+		descriptors[account.Name] = &inventory.ClusterAccount{Name: account.Name, Provider: account.Provider}
+	}
+	for i := range clusters {
+		clusters[i].Account = descriptors[clusters[i].AccountName]
+	}
+}
+
+// unknownRegion is the region bucket of the instances without availability zone
+const unknownRegion = "unknown"
+
+// regionSummaries computes the instances count, running count and total cost
+// of every region in a single pass. The region of an instance is derived from
+// its availability zone, and the instances without it are grouped under
+// emptyRegion. The regions of every provider are kept apart, unless
+// mergeProviders is set, which groups the instances by region only and
+// leaves the provider of the regions empty.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance.
+// - emptyRegion: Region of the instances without availability zone.
+// - mergeProviders: Groups the instances of every provider by region.
+// - compare: Sort order of the regions (e.g. compareRegionNames).
+//
+// Returns:
+// - A slice of RegionSummary.
+func regionSummaries(instances []inventory.Instance, emptyRegion string, mergeProviders bool, compare func(a, b RegionSummary) int) []RegionSummary {
+	type regionKey struct {
+		region   string
+		provider inventory.CloudProvider
+	}
+
+	index := make(map[regionKey]int)
+	summaries := make([]RegionSummary, 0)
+	for _, instance := range instances {
+		key := regionKey{region: instance.Region(), provider: instance.Provider}
+		if key.region == "" {
+			key.region = emptyRegion
+		}
+		if mergeProviders {
+			key.provider = ""
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(summaries)
+			index[key] = i
+			summaries = append(summaries, RegionSummary{Region: key.region, Provider: key.provider})
+		}
+		summaries[i].InstanceCount++
+		if instance.Status == inventory.Running {
+			summaries[i].RunningCount++
+		}
+		summaries[i].TotalCost += instance.TotalCost
+	}
+
+	slices.SortFunc(summaries, compare)
+	return summaries
+}
+
+// compareRegionNames sorts the regions by name and provider
+func compareRegionNames(a, b RegionSummary) int {
+	if c := cmp.Compare(a.Region, b.Region); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Provider, b.Provider)
+}
+
+// compareRegionCosts sorts the regions by cost descending, then by name and provider
+func compareRegionCosts(a, b RegionSummary) int {
+	if c := cmp.Compare(b.TotalCost, a.TotalCost); c != 0 {
+		return c
+	}
+	return compareRegionNames(a, b)
+}
+
+// regionStats computes the instances count, running count and total cost of
+// every region, sorted by cost descending (see regionSummaries). The
+// instances without availability zone are grouped on the empty region.
+// Instances are optionally scoped by provider and account.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance.
+// - clusters: A slice of inventory.Cluster.
+// - provider: Provider of the instances (case insensitive). Ignored if empty.
+// - accountName: Account of the instances. Ignored if empty.
+//
+// Returns:
+// - A slice of RegionStats.
+func regionStats(instances []inventory.Instance, clusters []inventory.Cluster, provider string, accountName string) []RegionStats {
+	accounts := clusterAccounts(clusters)
+	scoped := make([]inventory.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if provider != "" && !strings.EqualFold(string(instance.Provider), provider) {
+			continue
+		}
+		if accountName != "" && accounts[instance.ClusterID] != accountName {
+			continue
+		}
+		scoped = append(scoped, instance)
+	}
+
+	summaries := regionSummaries(scoped, "", true, compareRegionCosts)
+	stats := make([]RegionStats, 0, len(summaries))
+	for _, summary := range summaries {
+		stats = append(stats, RegionStats{
+			Region:           summary.Region,
+			Instances:        summary.InstanceCount,
+			RunningInstances: summary.RunningCount,
+			TotalCost:        summary.TotalCost,
+		})
+	}
+	return stats
+}
+
+// removeExcludedInstanceTotals returns the instances whose ID is not in
+// excluded, subtracting the cost and count of the excluded ones from the
+// totals of their clusters and accounts, which the DB computes over every
+// instance. The account of every instance is resolved from its cluster, so
+// only the accounts of the given clusters are updated. clusters and accounts
+// can be nil.
+func removeExcludedInstanceTotals(instances []inventory.Instance, excluded map[string]struct{}, clusters []inventory.Cluster, accounts []inventory.Account) []inventory.Instance {
+	clustersByID := make(map[string]*inventory.Cluster, len(clusters))
+	for i := range clusters {
+		clustersByID[clusters[i].ID] = &clusters[i]
+	}
+	accountsByName := make(map[string]*inventory.Account, len(accounts))
+	for i := range accounts {
+		accountsByName[accounts[i].Name] = &accounts[i]
+	}
+
+	kept := make([]inventory.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if _, ok := excluded[instance.ID]; !ok {
+			kept = append(kept, instance)
+			continue
+		}
+
+		cost := instance.TotalCost
+		if math.IsNaN(cost) {
+			cost = 0
+		}
+		cluster, ok := clustersByID[instance.ClusterID]
+		if !ok {
+			continue
+		}
+		cluster.TotalCost -= cost
+		cluster.InstanceCount--
+		if account, ok := accountsByName[cluster.AccountName]; ok {
+			account.TotalCost -= cost
+		}
+	}
+	return kept
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	sqlclient "github.com/RHEcosystemAppEng/cluster-iq/internal/sql_client"
+	"go.uber.org/zap"
+)
+
+// maxDBConnectDelay caps the wait between the DB connection attempts
+const maxDBConnectDelay = 30 * time.Second
+
+// connectDB creates the SQL client, retrying with exponential backoff while
+// the DB can't be reached. Every start waits for the DB to become available
+// (e.g. during a coordinated deploy), instead of serving every request with
+// errors until it does.
+//
+// Parameters:
+//   - dbURL: DB connection string
+//   - attempts: Maximum number of connection attempts. A single attempt is made if lower than 1
+//   - baseDelay: Wait after the first failed attempt. Doubled on every retry
+//   - logger: Logger for the failed attempts
+//
+// Returns:
+//   - The connected SQL client
+//   - The last connection error if every attempt failed
+func connectDB(dbURL string, attempts int, baseDelay time.Duration, logger *zap.Logger) (*sqlclient.SQLClient, error) {
+	attempts = max(attempts, 1)
+	delay := baseDelay
+	for attempt := 1; ; attempt++ {
+		sqlCli, err := sqlclient.NewSQLClient(dbURL, logger)
+		if err == nil {
+			return sqlCli, nil
+		}
+		if attempt >= attempts {
+			return nil, fmt.Errorf("DB not available after %d attempts: %w", attempts, err)
+		}
+
+		logger.Warn("DB not available, retrying",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", attempts),
+			zap.Duration("retry_in", delay),
+			zap.Error(err),
+		)
+		time.Sleep(delay)
+		delay = min(delay*2, maxDBConnectDelay)
+	}
+}
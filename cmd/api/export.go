@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+)
+
+// MIMEGraphviz is the content type for GraphViz DOT documents
+const MIMEGraphviz = "text/vnd.graphviz"
+
+// csvColumn is a column of a CSV document. fields are the JSON keys its value
+// comes from on the JSON version of the document, including the ones of its
+// parents, so the column is left out when any of them is hidden, as they are
+// removed from the JSON responses (see CIQ_HIDDEN_FIELDS)
+type csvColumn struct {
+	name   string
+	fields []string
+}
+
+// costCSVColumns are the columns of the CSV cost export, before the tag columns
+var costCSVColumns = []csvColumn{
+	{"account_name", []string{"accounts", "name"}},
+	{"account_provider", []string{"accounts", "provider"}},
+	{"account_total_cost", []string{"accounts", "total_cost"}},
+	{"account_current_month_so_far_cost", []string{"accounts", "current_month_so_far_cost"}},
+	{"account_last_month_cost", []string{"accounts", "last_month_cost"}},
+	{"cluster_id", []string{"accounts", "clusters", "id"}},
+	{"cluster_name", []string{"accounts", "clusters", "name"}},
+	{"cluster_total_cost", []string{"accounts", "clusters", "total_cost"}},
+	{"cluster_current_month_so_far_cost", []string{"accounts", "clusters", "current_month_so_far_cost"}},
+	{"cluster_last_month_cost", []string{"accounts", "clusters", "last_month_cost"}},
+	{"instance_id", []string{"accounts", "clusters", "instances", "id"}},
+	{"instance_name", []string{"accounts", "clusters", "instances", "name"}},
+	{"instance_type", []string{"accounts", "clusters", "instances", "instance_type"}},
+	{"instance_total_cost", []string{"accounts", "clusters", "instances", "total_cost"}},
+	{"instance_daily_cost", []string{"accounts", "clusters", "instances", "daily_cost"}},
+	{"currency", []string{"currency"}},
+}
+
+// instancesCSVColumns are the columns of the CSV instances list
+var instancesCSVColumns = []csvColumn{
+	{"id", []string{"instances", "id"}},
+	{"name", []string{"instances", "name"}},
+	{"provider", []string{"instances", "provider"}},
+	{"region", []string{"instances", "availabilityZone"}},
+	{"state", []string{"instances", "providerState", "status"}},
+	{"cluster", []string{"instances", "clusterID"}},
+	{"account", []string{"instances"}},
+	{"cost", []string{"instances", "totalCost"}},
+}
+
+// csvHeader returns the header row of the given columns
+func csvHeader(columns []csvColumn) []string {
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, column.name)
+	}
+	return header
+}
+
+// visibleCSVColumns returns the indexes of the columns without hidden fields
+func visibleCSVColumns(columns []csvColumn, hiddenFields []string) []int {
+	visible := make([]int, 0, len(columns))
+	for i, column := range columns {
+		if !slices.ContainsFunc(column.fields, func(field string) bool {
+			return slices.ContainsFunc(hiddenFields, func(hidden string) bool {
+				return strings.TrimSpace(hidden) == field
+			})
+		}) {
+			visible = append(visible, i)
+		}
+	}
+	return visible
+}
+
+// selectCSVColumns returns the values of the row on the given column indexes
+func selectCSVColumns(row []string, columns []int) []string {
+	selected := make([]string, 0, len(columns))
+	for _, i := range columns {
+		selected = append(selected, row[i])
+	}
+	return selected
+}
+
+// dotStatusColors maps the resources status to the fill color of their DOT nodes
+var dotStatusColors = map[inventory.InstanceStatus]string{
+	inventory.Running:    "palegreen",
+	inventory.Stopped:    "orange",
+	inventory.Terminated: "lightgrey",
+	inventory.Unknown:    "white",
+}
+
+// dotQuote escapes a string for being used as a DOT identifier or label
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// dotStatusColor returns the DOT fill color for a status
+func dotStatusColor(status inventory.InstanceStatus) string {
+	if color, ok := dotStatusColors[status]; ok {
+		return color
+	}
+	return dotStatusColors[inventory.Unknown]
+}
+
+// renderInventoryDOT builds a GraphViz DOT representation of the
+// account->cluster->instance hierarchy. Node labels include the resource name
+// and status, and clusters and instances are colored by their status.
+//
+// Parameters:
+// - accounts: Accounts to include as root nodes.
+// - clusters: Clusters linked to their account by AccountName.
+// - instances: Instances linked to their cluster by ClusterID.
+//
+// Returns:
+// - The DOT document as a string.
+func renderInventoryDOT(accounts []inventory.Account, clusters []inventory.Cluster, instances []inventory.Instance) string {
+	var sb strings.Builder
+	sb.WriteString("digraph inventory {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [style=filled];\n")
+
+	for _, account := range accounts {
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=folder, fillcolor=lightblue];\n",
+			dotQuote("account:"+account.Name),
+			dotQuote(fmt.Sprintf("%s\n%s", account.Name, account.Provider)),
+		)
+	}
+
+	for _, cluster := range clusters {
+		clusterNode := dotQuote("cluster:" + cluster.ID)
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=box, fillcolor=%s];\n",
+			clusterNode,
+			dotQuote(fmt.Sprintf("%s\n%s", cluster.Name, cluster.Status)),
+			dotStatusColor(cluster.Status),
+		)
+		fmt.Fprintf(&sb, "  %s -> %s;\n", dotQuote("account:"+cluster.AccountName), clusterNode)
+	}
+
+	for _, instance := range instances {
+		instanceNode := dotQuote("instance:" + instance.ID)
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=ellipse, fillcolor=%s];\n",
+			instanceNode,
+			dotQuote(fmt.Sprintf("%s\n%s", instance.DisplayName(), instance.Status)),
+			dotStatusColor(instance.Status),
+		)
+		fmt.Fprintf(&sb, "  %s -> %s;\n", dotQuote("cluster:"+instance.ClusterID), instanceNode)
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// formatCost formats a cost for the CSV export
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 2, 64)
+}
+
+// costCSVRow builds a row of the CSV cost export. nil clusters or instances
+// leave their columns empty, as well as the tags missing on the instance.
+func costCSVRow(account AccountCostExport, cluster *ClusterCostExport, instance *InstanceCostExport, currency string, tagColumns []string) []string {
+	row := make([]string, 0, len(costCSVColumns)+len(tagColumns))
+	row = append(row,
+		account.Name, account.Provider, formatCost(account.TotalCost),
+		formatCost(account.CurrentMonthSoFarCost), formatCost(account.LastMonthCost),
+	)
+
+	if cluster != nil {
+		row = append(row,
+			cluster.ID, cluster.Name, formatCost(cluster.TotalCost),
+			formatCost(cluster.CurrentMonthSoFarCost), formatCost(cluster.LastMonthCost),
+		)
+	} else {
+		row = append(row, "", "", "", "", "")
+	}
+
+	if instance != nil {
+		row = append(row,
+			instance.ID, instance.Name, instance.InstanceType,
+			formatCost(instance.TotalCost), formatCost(instance.DailyCost),
+		)
+	} else {
+		row = append(row, "", "", "", "", "")
+	}
+
+	row = append(row, currency)
+
+	for _, key := range tagColumns {
+		var value string
+		if instance != nil {
+			value = instance.Tags[key]
+		}
+		row = append(row, value)
+	}
+	return row
+}
+
+// writeCostCSV writes the cost export as CSV, with one row per instance
+// including the costs of its cluster and account, followed by a column for
+// every exported tag key. Accounts and clusters without instances are written
+// as a single row with empty instance columns, so their costs are not lost when
+// importing the document. The columns of the hidden fields are left out.
+//
+// Parameters:
+// - w: Destination of the CSV document.
+// - export: Cost export to write.
+// - hiddenFields: JSON fields removed from the responses.
+//
+// Returns:
+// - An error if the document can't be written.
+func writeCostCSV(w io.Writer, export *CostExportResponse, hiddenFields []string) error {
+	columns := slices.Clone(costCSVColumns)
+	for _, key := range export.TagColumns {
+		columns = append(columns, csvColumn{key, []string{"accounts", "clusters", "instances", "tags", key}})
+	}
+	visible := visibleCSVColumns(columns, hiddenFields)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(selectCSVColumns(csvHeader(columns), visible)); err != nil {
+		return err
+	}
+
+	for _, account := range export.Accounts {
+		if len(account.Clusters) == 0 {
+			if err := writer.Write(selectCSVColumns(costCSVRow(account, nil, nil, export.Currency, export.TagColumns), visible)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, cluster := range account.Clusters {
+			if len(cluster.Instances) == 0 {
+				if err := writer.Write(selectCSVColumns(costCSVRow(account, &cluster, nil, export.Currency, export.TagColumns), visible)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for _, instance := range cluster.Instances {
+				if err := writer.Write(selectCSVColumns(costCSVRow(account, &cluster, &instance, export.Currency, export.TagColumns), visible)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeInstancesCSV writes the instances list as CSV, with one row per
+// instance. Rows are written to w as they're rendered, so the document is
+// not kept in memory. The state is the one reported by the provider, or the
+// normalized status if unknown. The columns of the hidden fields are left out.
+//
+// Parameters:
+// - w: Destination of the CSV document.
+// - instances: Instances to write.
+// - accounts: Account name of every cluster, indexed by cluster ID.
+// - hiddenFields: JSON fields removed from the responses.
+//
+// Returns:
+// - An error if the document can't be written.
+func writeInstancesCSV(w io.Writer, instances []inventory.Instance, accounts map[string]string, hiddenFields []string) error {
+	visible := visibleCSVColumns(instancesCSVColumns, hiddenFields)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(selectCSVColumns(csvHeader(instancesCSVColumns), visible)); err != nil {
+		return err
+	}
+
+	for _, instance := range instances {
+		state := string(instance.ProviderState)
+		if state == "" {
+			state = string(instance.Status)
+		}
+		row := []string{
+			instance.ID, instance.DisplayName(), string(instance.Provider), instance.Region(), state,
+			instance.ClusterID, accounts[instance.ClusterID], formatCost(instance.TotalCost),
+		}
+		if err := writer.Write(selectCSVColumns(row, visible)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
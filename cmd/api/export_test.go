@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"testing"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+)
+
+// TestCSVHiddenFields verifies the columns of the hidden fields are left out of the CSV exports, as their fields are from the JSON responses
+func TestCSVHiddenFields(t *testing.T) {
+	instances := []inventory.Instance{{ID: "i-1", Name: "master", ClusterID: "c1", Status: inventory.Running, TotalCost: 10}}
+	export := &CostExportResponse{
+		Currency:   inventory.CostCurrency,
+		TagColumns: []string{"owner"},
+		Accounts: []AccountCostExport{{
+			Name:      "acc",
+			TotalCost: 10,
+			Clusters: []ClusterCostExport{{
+				ID:        "c1",
+				TotalCost: 10,
+				Instances: []InstanceCostExport{{ID: "i-1", TotalCost: 10, Tags: map[string]string{"owner": "me"}}},
+			}},
+		}},
+	}
+
+	tests := []struct {
+		name           string
+		write          func(*bytes.Buffer, []string) error
+		hiddenFields   []string
+		expectedHeader []string
+	}{
+		{
+			name: "Instances",
+			write: func(buf *bytes.Buffer, hidden []string) error {
+				return writeInstancesCSV(buf, instances, map[string]string{"c1": "acc"}, hidden)
+			},
+			expectedHeader: []string{"id", "name", "provider", "region", "state", "cluster", "account", "cost"},
+		},
+		{
+			name: "Instances without cost",
+			write: func(buf *bytes.Buffer, hidden []string) error {
+				return writeInstancesCSV(buf, instances, map[string]string{"c1": "acc"}, hidden)
+			},
+			hiddenFields:   []string{"totalCost", " status"},
+			expectedHeader: []string{"id", "name", "provider", "region", "cluster", "account"},
+		},
+		{
+			name: "Cost export without costs",
+			write: func(buf *bytes.Buffer, hidden []string) error {
+				return writeCostCSV(buf, export, hidden)
+			},
+			hiddenFields: []string{"total_cost", "daily_cost", "owner"},
+			expectedHeader: []string{
+				"account_name", "account_provider", "account_current_month_so_far_cost", "account_last_month_cost",
+				"cluster_id", "cluster_name", "cluster_current_month_so_far_cost", "cluster_last_month_cost",
+				"instance_id", "instance_name", "instance_type", "currency",
+			},
+		},
+		{
+			name: "Cost export without instances",
+			write: func(buf *bytes.Buffer, hidden []string) error {
+				return writeCostCSV(buf, export, hidden)
+			},
+			hiddenFields: []string{"instances"},
+			expectedHeader: []string{
+				"account_name", "account_provider", "account_total_cost", "account_current_month_so_far_cost", "account_last_month_cost",
+				"cluster_id", "cluster_name", "cluster_total_cost", "cluster_current_month_so_far_cost", "cluster_last_month_cost",
+				"currency",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf, tt.hiddenFields); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("failed to read the CSV document: %v", err)
+			}
+			if !slices.Equal(records[0], tt.expectedHeader) {
+				t.Errorf("expected header %v, got %v", tt.expectedHeader, records[0])
+			}
+			// csv.Reader fails on rows with a different number of fields than the header
+			if len(records) != 2 {
+				t.Errorf("expected 1 row, got %d", len(records)-1)
+			}
+		})
+	}
+}
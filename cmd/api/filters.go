@@ -0,0 +1,370 @@
+package main
+
+import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+)
+
+// filterItems returns the items for which keep returns true, preserving their order
+func filterItems[T any](items []T, keep func(T) bool) []T {
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if keep(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// instanceListFilters are the filters supported by the instance list endpoints.
+// Empty or nil filters are ignored
+type instanceListFilters struct {
+	modifiedSince *time.Time
+	createdAfter  *time.Time
+	createdBefore *time.Time
+	unnamed       *bool
+	role          string
+	rolePrefix    string
+	status        string
+	instanceType  string
+	// The instances matching any of the values of each list are kept
+	providers []string
+	regions   []string
+	states    []string
+	// The instances with every tag (same key and value) are kept
+	tags []inventory.Tag
+}
+
+// apply returns the instances matching every filter, preserving their order
+func (f instanceListFilters) apply(instances []inventory.Instance) []inventory.Instance {
+	if f.modifiedSince != nil {
+		instances = filterInstancesModifiedSince(instances, *f.modifiedSince)
+	}
+
+	if f.createdAfter != nil || f.createdBefore != nil {
+		instances = filterInstancesCreatedBetween(instances, f.createdAfter, f.createdBefore)
+	}
+
+	if f.role != "" || f.rolePrefix != "" {
+		instances = filterInstancesByRole(instances, f.role, f.rolePrefix)
+	}
+
+	if f.unnamed != nil {
+		instances = filterInstancesByUnnamed(instances, *f.unnamed)
+	}
+
+	if f.status != "" {
+		instances = filterInstancesByStatus(instances, inventory.ProviderState(f.status).Status())
+	}
+
+	if f.instanceType != "" {
+		instances = filterInstancesByType(instances, f.instanceType)
+	}
+
+	if len(f.providers) > 0 {
+		instances = filterInstancesByAnyOf(instances, f.providers, func(instance inventory.Instance) string {
+			return string(instance.Provider)
+		})
+	}
+
+	if len(f.regions) > 0 {
+		instances = filterInstancesByAnyOf(instances, f.regions, inventory.Instance.Region)
+	}
+
+	if len(f.states) > 0 {
+		instances = filterInstancesByAnyOf(instances, f.states, func(instance inventory.Instance) string {
+			return string(instance.ProviderState)
+		})
+	}
+
+	if len(f.tags) > 0 {
+		instances = filterInstancesByTags(instances, f.tags)
+	}
+
+	return instances
+}
+
+// filterInstancesByAnyOf returns the instances whose field matches any of the
+// values (case insensitive). Unknown values don't match any instance
+func filterInstancesByAnyOf(instances []inventory.Instance, values []string, field func(inventory.Instance) string) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		return slices.ContainsFunc(values, func(value string) bool {
+			return strings.EqualFold(field(instance), value)
+		})
+	})
+}
+
+// filterInstancesByTags returns the instances having every tag, with the same
+// key and value (case sensitive, as on the providers)
+func filterInstancesByTags(instances []inventory.Instance, tags []inventory.Tag) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		for _, tag := range tags {
+			found := inventory.LookForTagByKey(tag.Key, instance.Tags)
+			if found == nil || found.Value != tag.Value {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// filterInstancesModifiedSince returns the instances created or scanned after since
+func filterInstancesModifiedSince(instances []inventory.Instance, since time.Time) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		return instance.ModifiedSince(since)
+	})
+}
+
+// filterInstancesByStatus returns the instances whose canonical status is the given one
+func filterInstancesByStatus(instances []inventory.Instance, status inventory.InstanceStatus) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		return inventory.ProviderState(instance.Status).Status() == status
+	})
+}
+
+// filterInstancesExcluded returns the instances whose ID is not on the excluded set
+func filterInstancesExcluded(instances []inventory.Instance, excluded map[string]struct{}) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		_, ok := excluded[instance.ID]
+		return !ok
+	})
+}
+
+// filterInstancesByType returns the instances of the given type (case insensitive)
+func filterInstancesByType(instances []inventory.Instance, instanceType string) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		return strings.EqualFold(instance.InstanceType, instanceType)
+	})
+}
+
+// filterInstancesByUnnamed returns the unnamed instances if unnamed is true, or the named ones otherwise
+func filterInstancesByUnnamed(instances []inventory.Instance, unnamed bool) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		return instance.IsUnnamed() == unnamed
+	})
+}
+
+// filterClustersModifiedSince returns the clusters created or scanned after since
+func filterClustersModifiedSince(clusters []inventory.Cluster, since time.Time) []inventory.Cluster {
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		return cluster.ModifiedSince(since)
+	})
+}
+
+// filterInstancesCreatedBetween returns the instances created in the [after, before) range. nil limits are ignored
+func filterInstancesCreatedBetween(instances []inventory.Instance, after, before *time.Time) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		return instance.CreatedBetween(after, before)
+	})
+}
+
+// filterInstancesCreatedBefore returns the instances created before the
+// cutoff. The instances without creation timestamp are removed, and their
+// count is returned
+func filterInstancesCreatedBefore(instances []inventory.Instance, cutoff time.Time) ([]inventory.Instance, int) {
+	skipped := 0
+	filtered := filterItems(instances, func(instance inventory.Instance) bool {
+		if instance.CreationTimestamp.IsZero() {
+			skipped++
+			return false
+		}
+		return instance.CreationTimestamp.Before(cutoff)
+	})
+	return filtered, skipped
+}
+
+// filterClustersCreatedBetween returns the clusters created in the [after, before) range. nil limits are ignored
+func filterClustersCreatedBetween(clusters []inventory.Cluster, after, before *time.Time) []inventory.Cluster {
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		return cluster.CreatedBetween(after, before)
+	})
+}
+
+// filterAccountsModifiedSince returns the accounts scanned after since
+func filterAccountsModifiedSince(accounts []inventory.Account, since time.Time) []inventory.Account {
+	return filterItems(accounts, func(account inventory.Account) bool {
+		return account.ModifiedSince(since)
+	})
+}
+
+// filterInstancesByRole returns the instances whose IAM role is equal to role
+// and/or starts with rolePrefix. Empty arguments are ignored, and instances
+// without role data are always excluded
+func filterInstancesByRole(instances []inventory.Instance, role string, rolePrefix string) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		if instance.IAMRole == "" {
+			return false
+		}
+		if role != "" && instance.IAMRole != role {
+			return false
+		}
+		return strings.HasPrefix(instance.IAMRole, rolePrefix)
+	})
+}
+
+// filterClustersByUptime returns the clusters whose uptime percentage is in the [minUptime, maxUptime] range.
+// nil limits are ignored
+func filterClustersByUptime(clusters []inventory.Cluster, minUptime, maxUptime *float64) []inventory.Cluster {
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		if minUptime != nil && cluster.UptimePercent < *minUptime {
+			return false
+		}
+		return maxUptime == nil || cluster.UptimePercent <= *maxUptime
+	})
+}
+
+// filterClustersByInstanceCount returns the clusters whose instance count is in the [minInstances, maxInstances] range.
+// nil limits are ignored
+func filterClustersByInstanceCount(clusters []inventory.Cluster, minInstances, maxInstances *int) []inventory.Cluster {
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		if minInstances != nil && cluster.InstanceCount < *minInstances {
+			return false
+		}
+		return maxInstances == nil || cluster.InstanceCount <= *maxInstances
+	})
+}
+
+// filterClustersByStatus returns the clusters whose canonical status is the given one
+func filterClustersByStatus(clusters []inventory.Cluster, status inventory.InstanceStatus) []inventory.Cluster {
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		return inventory.ProviderState(cluster.Status).Status() == status
+	})
+}
+
+// filterClustersByName returns the clusters whose name is (exact), starts
+// with (prefix) or contains (substring) the given name, optionally ignoring
+// the case
+func filterClustersByName(clusters []inventory.Cluster, name string, match string, caseInsensitive bool) []inventory.Cluster {
+	if caseInsensitive {
+		name = strings.ToLower(name)
+	}
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		clusterName := cluster.Name
+		if caseInsensitive {
+			clusterName = strings.ToLower(clusterName)
+		}
+		switch match {
+		case matchExact:
+			return clusterName == name
+		case matchPrefix:
+			return strings.HasPrefix(clusterName, name)
+		default:
+			return strings.Contains(clusterName, name)
+		}
+	})
+}
+
+// filterClustersByProvider returns the clusters of any of the providers (case
+// insensitive). Unknown providers don't match any cluster
+func filterClustersByProvider(clusters []inventory.Cluster, providers []string) []inventory.Cluster {
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		return slices.ContainsFunc(providers, func(provider string) bool {
+			return strings.EqualFold(string(cluster.Provider), provider)
+		})
+	})
+}
+
+// searchAccounts returns the accounts matching the search query
+func searchAccounts(accounts []inventory.Account, query string) []inventory.Account {
+	return filterItems(accounts, func(account inventory.Account) bool {
+		return account.MatchesSearch(query)
+	})
+}
+
+// searchClusters returns the clusters matching the search query
+func searchClusters(clusters []inventory.Cluster, query string) []inventory.Cluster {
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		return cluster.MatchesSearch(query)
+	})
+}
+
+// searchInstances returns the instances matching the search query
+func searchInstances(instances []inventory.Instance, query string) []inventory.Instance {
+	return filterItems(instances, func(instance inventory.Instance) bool {
+		return instance.MatchesSearch(query)
+	})
+}
+
+// filterClustersBySelector returns the non terminated clusters belonging to
+// accountName, whose name matches namePattern and having every label of
+// selectedLabels, with the same key and value. Empty selectors are ignored.
+// labels are the labels of every cluster, indexed by cluster ID (see clusterLabels)
+func filterClustersBySelector(clusters []inventory.Cluster, accountName string, namePattern string, selectedLabels map[string]string, labels map[string]map[string]string) []inventory.Cluster {
+	return filterItems(clusters, func(cluster inventory.Cluster) bool {
+		if cluster.Status == inventory.Terminated {
+			return false
+		}
+		if accountName != "" && cluster.AccountName != accountName {
+			return false
+		}
+		for key, value := range selectedLabels {
+			if clusterValue, ok := labels[cluster.ID][key]; !ok || clusterValue != value {
+				return false
+			}
+		}
+		if namePattern == "" {
+			return true
+		}
+		matched, _ := path.Match(namePattern, cluster.Name)
+		return matched
+	})
+}
+
+// filterExpensesAsOf returns the expenses accounted in the billing period of asOf, up to that date
+func filterExpensesAsOf(expenses []inventory.Expense, asOf time.Time) []inventory.Expense {
+	return filterItems(expenses, func(expense inventory.Expense) bool {
+		return expense.InBillingPeriodAsOf(asOf)
+	})
+}
+
+// updateClustersUptime computes the uptime percentage of every cluster using
+// their status history, as of their reference time (see statusReferenceTime)
+func updateClustersUptime(clusters []inventory.Cluster, history map[string][]inventory.StatusChange) {
+	for i := range clusters {
+		changes := history[clusters[i].ID]
+		clusters[i].UpdateUptimePercent(changes, statusReferenceTime(clusters[i].LastScanTimestamp, changes))
+	}
+}
+
+// updateInstancesCostPerHour computes the cost per running hour of every
+// instance using their status history, as of their reference time (see
+// statusReferenceTime)
+func updateInstancesCostPerHour(instances []inventory.Instance, history map[string][]inventory.StatusChange) {
+	for i := range instances {
+		changes := history[instances[i].ID]
+		instances[i].UpdateCostPerHour(changes, statusReferenceTime(instances[i].LastScanTimestamp, changes))
+	}
+}
+
+// statusReferenceTime returns the time the status history based fields of a
+// resource are computed at: its last scan, or its last status change if it's
+// later. Unlike the current time, it only changes when the inventory does, so
+// the responses (and their ETag) are the same until the next scan or status
+// change. The costs are also accumulated until the last scan.
+func statusReferenceTime(lastScan time.Time, history []inventory.StatusChange) time.Time {
+	if len(history) > 0 && history[len(history)-1].Timestamp.After(lastScan) {
+		return history[len(history)-1].Timestamp
+	}
+	return lastScan
+}
+
+// attachInstancesToClusters sets the Instances list of every cluster using the given instances
+func attachInstancesToClusters(clusters []inventory.Cluster, instances []inventory.Instance) {
+	byCluster := instancesByCluster(instances)
+	for i := range clusters {
+		clusters[i].Instances = byCluster[clusters[i].ID]
+	}
+}
+
+// instancesByCluster groups the instances by their cluster ID
+func instancesByCluster(instances []inventory.Instance) map[string][]inventory.Instance {
+	byCluster := make(map[string][]inventory.Instance)
+	for _, instance := range instances {
+		byCluster[instance.ClusterID] = append(byCluster[instance.ClusterID], instance)
+	}
+	return byCluster
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+)
+
+// mixedProvidersFixture returns an inventory spanning AWS, Azure and GCP, plus an instance of an unknown provider
+func mixedProvidersFixture() ([]inventory.Cluster, []inventory.Instance) {
+	clusters := []inventory.Cluster{
+		{ID: "aws-c1", Name: "c1", Provider: inventory.AWSProvider, Region: "us-east-1", AccountName: "aws-account"},
+		{ID: "azure-c1", Name: "c1", Provider: inventory.AzureProvider, Region: "westeurope", AccountName: "azure-account"},
+		{ID: "gcp-c1", Name: "c1", Provider: inventory.GCPProvider, Region: "europe-west1", AccountName: "gcp-account"},
+	}
+	instances := []inventory.Instance{
+		{ID: "i-aws-1", ClusterID: "aws-c1", Provider: inventory.AWSProvider, AvailabilityZone: "us-east-1a", Status: inventory.Running, TotalCost: 10},
+		{ID: "i-aws-2", ClusterID: "aws-c1", Provider: inventory.AWSProvider, AvailabilityZone: "us-east-1b", Status: inventory.Stopped, TotalCost: 5},
+		{ID: "i-azure-1", ClusterID: "azure-c1", Provider: inventory.AzureProvider, AvailabilityZone: "westeurope", Status: inventory.Running, TotalCost: 7},
+		{ID: "i-gcp-1", ClusterID: "gcp-c1", Provider: inventory.GCPProvider, AvailabilityZone: "europe-west1-b", Status: inventory.Running, TotalCost: 3},
+		{ID: "i-unknown-1", Provider: inventory.UnknownProvider},
+	}
+	return clusters, instances
+}
+
+// TestFilterByProviderMixedInventory verifies the provider filters on an inventory with several providers
+func TestFilterByProviderMixedInventory(t *testing.T) {
+	clusters, instances := mixedProvidersFixture()
+
+	tests := []struct {
+		name          string
+		providers     []string
+		wantClusters  []string
+		wantInstances []string
+	}{
+		{name: "Single", providers: []string{"azure"}, wantClusters: []string{"azure-c1"}, wantInstances: []string{"i-azure-1"}},
+		{name: "Several", providers: []string{"AWS", "gcp"}, wantClusters: []string{"aws-c1", "gcp-c1"}, wantInstances: []string{"i-aws-1", "i-aws-2", "i-gcp-1"}},
+		{name: "Unknown", providers: []string{"unknown"}, wantClusters: []string{}, wantInstances: []string{"i-unknown-1"}},
+		{name: "Unrecognized", providers: []string{"DigitalOcean"}, wantClusters: []string{}, wantInstances: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotClusters []string
+			for _, cluster := range filterClustersByProvider(clusters, tt.providers) {
+				gotClusters = append(gotClusters, cluster.ID)
+			}
+			assertIDs(t, "clusters", gotClusters, tt.wantClusters)
+
+			var gotInstances []string
+			for _, instance := range (instanceListFilters{providers: tt.providers}).apply(instances) {
+				gotInstances = append(gotInstances, instance.ID)
+			}
+			assertIDs(t, "instances", gotInstances, tt.wantInstances)
+		})
+	}
+}
+
+// TestRegionStatsMixedInventory verifies the region stats are scoped to the requested provider, as the provider filter does
+func TestRegionStatsMixedInventory(t *testing.T) {
+	clusters, instances := mixedProvidersFixture()
+
+	for _, provider := range []string{string(inventory.AWSProvider), "aws"} {
+		stats := regionStats(instances, clusters, provider, "")
+		if len(stats) != 1 || stats[0].Region != "us-east-1" || stats[0].Instances != 2 || stats[0].RunningInstances != 1 || stats[0].TotalCost != 15 {
+			t.Errorf("unexpected %s region stats: %+v", provider, stats)
+		}
+	}
+
+	// Unrecognized providers return an empty list, as the provider filter does
+	if stats := regionStats(instances, clusters, "DigitalOcean", ""); len(stats) != 0 {
+		t.Errorf("expected no region stats for an unrecognized provider, got %+v", stats)
+	}
+
+	stats := regionStats(instances, clusters, "", "")
+
+	// Every provider, sorted by cost. The instance without zone has no region
+	var regions []string
+	for _, stat := range stats {
+		regions = append(regions, stat.Region)
+	}
+	assertIDs(t, "regions", regions, []string{"us-east-1", "westeurope", "europe-west1", ""})
+}
+
+// TestRegionSummaries verifies the instances are grouped by region and provider, with the instances without zone on the unknown region
+func TestRegionSummaries(t *testing.T) {
+	instances := []inventory.Instance{
+		{ID: "i-1", Provider: inventory.AWSProvider, AvailabilityZone: "us-east-1a", Status: inventory.Running, TotalCost: 10},
+		{ID: "i-2", Provider: inventory.AWSProvider, AvailabilityZone: "us-east-1b", Status: inventory.Stopped, TotalCost: 5},
+		{ID: "i-3", Provider: inventory.GCPProvider, AvailabilityZone: "europe-west1-b", Status: inventory.Running, TotalCost: 3},
+		{ID: "i-4", Provider: inventory.AzureProvider, AvailabilityZone: "westeurope", Status: inventory.Running, TotalCost: 7},
+		{ID: "i-5", Provider: inventory.UnknownProvider},
+	}
+
+	want := []RegionSummary{
+		{Region: "europe-west1", Provider: inventory.GCPProvider, InstanceCount: 1, RunningCount: 1, TotalCost: 3},
+		{Region: "unknown", Provider: inventory.UnknownProvider, InstanceCount: 1},
+		{Region: "us-east-1", Provider: inventory.AWSProvider, InstanceCount: 2, RunningCount: 1, TotalCost: 15},
+		{Region: "westeurope", Provider: inventory.AzureProvider, InstanceCount: 1, RunningCount: 1, TotalCost: 7},
+	}
+	if got := regionSummaries(instances, unknownRegion, false, compareRegionNames); !slices.Equal(got, want) {
+		t.Errorf("expected regions %+v, got %+v", want, got)
+	}
+
+	if got := NewRegionListResponse(regionSummaries(nil, unknownRegion, false, compareRegionNames)).Regions; got == nil || len(got) != 0 {
+		t.Errorf("expected an empty regions list, got %#v", got)
+	}
+}
+
+// TestInstanceStateStats verifies the instances are counted by status, omitting the statuses without instances
+func TestInstanceStateStats(t *testing.T) {
+	_, instances := mixedProvidersFixture()
+
+	states, total := instanceStateStats(instances)
+	want := map[inventory.InstanceStatus]InstanceStateStats{
+		inventory.Running: {Count: 3, TotalCost: 20},
+		inventory.Stopped: {Count: 1, TotalCost: 5},
+		inventory.Unknown: {Count: 1},
+	}
+	if len(states) != len(want) {
+		t.Fatalf("expected states %+v, got %+v", want, states)
+	}
+	for status, stats := range want {
+		if states[status] != stats {
+			t.Errorf("expected %s stats %+v, got %+v", status, stats, states[status])
+		}
+	}
+	if total != (InstanceStateStats{Count: 5, TotalCost: 25}) {
+		t.Errorf("unexpected total stats: %+v", total)
+	}
+}
+
+// TestEmbedClusterAccounts verifies the account descriptor is embedded on the clusters of known accounts only
+func TestEmbedClusterAccounts(t *testing.T) {
+	clusters, _ := mixedProvidersFixture()
+	accounts := []inventory.Account{
+		{Name: "aws-account", Provider: inventory.AWSProvider},
+		{Name: "azure-account", Provider: inventory.AzureProvider},
+	}
+
+	embedClusterAccounts(clusters, accounts)
+	if got := clusters[0].Account; got == nil || *got != (inventory.ClusterAccount{Name: "aws-account", Provider: inventory.AWSProvider}) {
+		t.Errorf("unexpected aws-c1 account: %+v", got)
+	}
+	if got := clusters[1].Account; got == nil || *got != (inventory.ClusterAccount{Name: "azure-account", Provider: inventory.AzureProvider}) {
+		t.Errorf("unexpected azure-c1 account: %+v", got)
+	}
+	if got := clusters[2].Account; got != nil {
+		t.Errorf("expected no account on gcp-c1, got %+v", got)
+	}
+}
+
+// TestStatusReferenceTime verifies the uptime and cost per hour are computed as of the last scan or status change, so they don't change between requests
+func TestStatusReferenceTime(t *testing.T) {
+	lastScan := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	history := map[string][]inventory.StatusChange{
+		"c1":  {{Timestamp: lastScan.Add(-10 * time.Hour), Status: inventory.Running}, {Timestamp: lastScan.Add(-5 * time.Hour), Status: inventory.Stopped}},
+		"c2":  {{Timestamp: lastScan.Add(-4 * time.Hour), Status: inventory.Stopped}, {Timestamp: lastScan, Status: inventory.Running}, {Timestamp: lastScan.Add(4 * time.Hour), Status: inventory.Stopped}},
+		"i-1": {{Timestamp: lastScan.Add(-10 * time.Hour), Status: inventory.Running}},
+	}
+	clusters := []inventory.Cluster{
+		{ID: "c1", Status: inventory.Stopped, LastScanTimestamp: lastScan},
+		{ID: "c2", Status: inventory.Stopped, LastScanTimestamp: lastScan},
+	}
+	instances := []inventory.Instance{
+		{ID: "i-1", Status: inventory.Running, TotalCost: 10, LastScanTimestamp: lastScan},
+		{ID: "i-2", Status: inventory.Running, TotalCost: 10, LastScanTimestamp: lastScan, CreationTimestamp: lastScan.Add(-20 * time.Hour)},
+	}
+
+	updateClustersUptime(clusters, history)
+	updateInstancesCostPerHour(instances, history)
+
+	// c2 was stopped after its last scan, so its uptime is computed until the stop
+	for i, want := range []float64{50, 50} {
+		if clusters[i].UptimePercent != want {
+			t.Errorf("expected %s uptime %v, got %v", clusters[i].ID, want, clusters[i].UptimePercent)
+		}
+	}
+	for i, want := range []float64{1, 0.5} {
+		if instances[i].CostPerHour != want {
+			t.Errorf("expected %s cost per hour %v, got %v", instances[i].ID, want, instances[i].CostPerHour)
+		}
+	}
+}
+
+// assertIDs compares two lists of identifiers, including their order
+func assertIDs(t *testing.T, kind string, got []string, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %s %v, got %v", kind, want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %s %v, got %v", kind, want, got)
+		}
+	}
+}
+
+// TestFilterClustersBySelector verifies the clusters are selected by account, name pattern and labels together, leaving the terminated ones out
+func TestFilterClustersBySelector(t *testing.T) {
+	clusters := []inventory.Cluster{
+		{ID: "c1", Name: "dev-1", AccountName: "acc", Status: inventory.Running},
+		{ID: "c2", Name: "dev-2", AccountName: "acc", Status: inventory.Stopped},
+		{ID: "c3", Name: "prod-1", AccountName: "acc", Status: inventory.Running},
+		{ID: "c4", Name: "dev-3", AccountName: "acc", Status: inventory.Terminated},
+	}
+	labels := clusterLabels([]inventory.Instance{
+		{ClusterID: "c1", Tags: []inventory.Tag{{Key: "env", Value: "dev"}, {Key: "team", Value: "a"}}},
+		{ClusterID: "c2", Tags: []inventory.Tag{{Key: "env", Value: "dev"}}},
+		{ClusterID: "c3", Tags: []inventory.Tag{{Key: "env", Value: "prod"}, {Key: "team", Value: "a"}}},
+		{ClusterID: "c4", Tags: []inventory.Tag{{Key: "env", Value: "dev"}}},
+	})
+
+	tests := []struct {
+		name        string
+		accountName string
+		namePattern string
+		labels      map[string]string
+		expected    []string
+	}{
+		{name: "Account", accountName: "acc", expected: []string{"c1", "c2", "c3"}},
+		{name: "Label", labels: map[string]string{"env": "dev"}, expected: []string{"c1", "c2"}},
+		{name: "Every label", labels: map[string]string{"env": "dev", "team": "a"}, expected: []string{"c1"}},
+		{name: "Label and pattern", namePattern: "prod-*", labels: map[string]string{"team": "a"}, expected: []string{"c3"}},
+		{name: "Unknown label", labels: map[string]string{"owner": "me"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, cluster := range filterClustersBySelector(clusters, tt.accountName, tt.namePattern, tt.labels, labels) {
+				ids = append(ids, cluster.ID)
+			}
+			if !slices.Equal(ids, tt.expected) {
+				t.Errorf("expected clusters %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
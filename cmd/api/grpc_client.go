@@ -24,6 +24,8 @@ type APIGRPCClient struct {
 	CTX context.Context
 	// Cancel is the function to cancel the gRPC context.
 	Cancel context.CancelFunc
+	// conn is the connection to the Agent service.
+	conn *grpc.ClientConn
 	// logger is used for logging gRPC operations and errors.
 	logger *zap.Logger
 }
@@ -50,10 +52,20 @@ func NewAPIGRPCClient(agentURL string, logger *zap.Logger) (*APIGRPCClient, erro
 		Client: pb.NewAgentServiceClient(conn),
 		CTX:    ctx,
 		Cancel: cancel,
+		conn:   conn,
 		logger: logger,
 	}, nil
 }
 
+// Close cancels the gRPC context and closes the connection to the Agent service.
+//
+// Returns:
+// - An error if the connection can't be closed.
+func (a APIGRPCClient) Close() error {
+	a.Cancel()
+	return a.conn.Close()
+}
+
 // PowerOffCluster sends a gRPC request to power off a cluster by the given ClusterID.
 // It logs the details of the request and the response received.
 //
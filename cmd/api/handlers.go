@@ -1,14 +1,23 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/actions"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/events"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/models"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -35,7 +44,7 @@ func (a APIServer) HandlerHealthCheck(c *gin.Context) {
 	if err := a.sql.Ping(); err == nil {
 		hc.DBHealth = true
 	} else {
-		a.logger.Error("Can't ping DB", zap.Error(err))
+		a.requestLogger(c).Error("Can't ping DB", zap.Error(err))
 	}
 
 	// Checking API's Router status
@@ -43,7 +52,98 @@ func (a APIServer) HandlerHealthCheck(c *gin.Context) {
 		hc.APIHealth = true
 	}
 
-	c.PureJSON(http.StatusOK, HealthCheckResponse{HealthChecks: hc})
+	writeJSON(c, http.StatusOK, HealthCheckResponse{HealthChecks: hc})
+}
+
+// HandlerLiveness handles the request for checking if the API process is alive
+//
+//	@Summary		Runs the liveness check
+//	@Description	Always responds 200, as the API process is able to serve requests
+//	@Tags			Health
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	LivenessResponse
+//	@Router			/healthz [get]
+func (a APIServer) HandlerLiveness(c *gin.Context) {
+	writeJSON(c, http.StatusOK, LivenessResponse{Alive: true})
+}
+
+// HandlerVersion handles the request for the build metadata of the API
+//
+//	@Summary		Obtain the API build metadata
+//	@Description	Returns the version, commit, Go version and build time of the running binary, so the replicas of a rollout can be told apart. It doesn't depend on the DB
+//	@Tags			Health
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	VersionResponse
+//	@Router			/version [get]
+func (a APIServer) HandlerVersion(c *gin.Context) {
+	writeJSON(c, http.StatusOK, VersionResponse{
+		Version:   version,
+		Commit:    commit,
+		GoVersion: runtime.Version(),
+		BuildTime: buildTime,
+	})
+}
+
+// HandlerReadiness handles the request for checking if the API is ready to serve
+//
+//	@Summary		Runs readiness checks
+//	@Description	Checks the DB responds within a short timeout and, if CIQ_MAX_INVENTORY_STALENESS is set, that the last scan is not older than it. The body includes the DB address and the last scan timestamp for debugging
+//	@Tags			Health
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	ReadinessResponse
+//	@Failure		503	{object}	ReadinessResponse
+//	@Router			/readyz [get]
+func (a APIServer) HandlerReadiness(c *gin.Context) {
+	response := ReadinessResponse{DBAddress: dbAddress(a.cfg.DBURL)}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessPingTimeout)
+	defer cancel()
+	if err := a.sql.PingContext(ctx); err != nil {
+		a.requestLogger(c).Error("Can't ping DB", zap.Error(err))
+		response.FailedCheck = readinessCheckDB
+		response.Message = err.Error()
+		writeJSON(c, http.StatusServiceUnavailable, response)
+		return
+	}
+
+	lastScan, err := a.sql.GetScannerLastScanTimestamp()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve scanner last scan timestamp", zap.Error(err))
+	}
+	response.LastScanTimestamp = lastScan
+
+	if maxStaleness := a.cfg.MaxInventoryStaleness; maxStaleness > 0 {
+		switch {
+		case err != nil:
+			response.FailedCheck = readinessCheckInventory
+			response.Message = err.Error()
+		case lastScan == nil:
+			response.FailedCheck = readinessCheckInventory
+			response.Message = "inventory was never scanned"
+		case time.Since(*lastScan) > maxStaleness:
+			response.FailedCheck = readinessCheckInventory
+			response.Message = fmt.Sprintf("last scan was %s ago, exceeding the maximum staleness of %s", time.Since(*lastScan).Round(time.Second), maxStaleness)
+		}
+		if response.FailedCheck != "" {
+			writeJSON(c, http.StatusServiceUnavailable, response)
+			return
+		}
+	}
+
+	response.Ready = true
+	writeJSON(c, http.StatusOK, response)
+}
+
+// dbAddress returns the host and port of the DB URL, so it can be reported without the credentials
+func dbAddress(dbURL string) string {
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
 }
 
 // ==================== Scheduled Actions Handlers ====================
@@ -59,7 +159,7 @@ func (a APIServer) HandlerHealthCheck(c *gin.Context) {
 //	@Failure		500		{object}	GenericErrorResponse
 //	@Router			/schedule [get]
 func (a APIServer) HandlerGetScheduledActions(c *gin.Context) {
-	a.logger.Debug("Retrieving scheduled actions")
+	a.requestLogger(c).Debug("Retrieving scheduled actions")
 
 	// Capturing query params
 	var conditions []string
@@ -80,12 +180,15 @@ func (a APIServer) HandlerGetScheduledActions(c *gin.Context) {
 	// Running sql client function
 	schedule, err := a.sql.GetScheduledActions(conditions, args)
 	if err != nil {
-		a.logger.Error("Failed to retrieve scheduled actions", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Failed to retrieve scheduled actions", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewScheduledActionListResponse(schedule))
+	schedule, truncated := truncateResults(c, schedule, a.cfg.MaxResults)
+	response := NewScheduledActionListResponse(schedule)
+	response.Truncated = truncated
+	writeJSON(c, http.StatusOK, response)
 }
 
 // HandlerGetScheduledActionByID retrieves a single scheduled action by its unique identifier
@@ -99,16 +202,16 @@ func (a APIServer) HandlerGetScheduledActions(c *gin.Context) {
 //	@Router			/schedule/{action_id} [get]
 func (a APIServer) HandlerGetScheduledActionByID(c *gin.Context) {
 	actionID := c.Param("action_id")
-	a.logger.Debug("Retrieving scheduled action by ID", zap.String("action_id", actionID))
+	a.requestLogger(c).Debug("Retrieving scheduled action by ID", zap.String("action_id", actionID))
 
 	schedule, err := a.sql.GetScheduledActionByID(actionID)
 	if err != nil {
-		a.logger.Error("Failed to retrieve scheduled action", zap.String("action_id", actionID), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Failed to retrieve scheduled action", zap.String("action_id", actionID), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewScheduledActionListResponse(schedule))
+	writeJSON(c, http.StatusOK, NewScheduledActionListResponse(schedule))
 }
 
 // HandlerEnableScheduledAction activates a scheduled action so it can be executed according to its schedule
@@ -122,16 +225,16 @@ func (a APIServer) HandlerGetScheduledActionByID(c *gin.Context) {
 //	@Router			/schedule/{action_id}/enable [patch]
 func (a APIServer) HandlerEnableScheduledAction(c *gin.Context) {
 	actionID := c.Param("action_id")
-	a.logger.Debug("Enabling scheduled action", zap.String("action_id", actionID))
+	a.requestLogger(c).Debug("Enabling scheduled action", zap.String("action_id", actionID))
 
 	err := a.sql.EnableScheduledAction(actionID)
 	if err != nil {
-		a.logger.Error("Failed to enable scheduled action", zap.String("action_id", actionID), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Failed to enable scheduled action", zap.String("action_id", actionID), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerDisableScheduledAction deactivates a scheduled action to prevent its execution
@@ -145,16 +248,16 @@ func (a APIServer) HandlerEnableScheduledAction(c *gin.Context) {
 //	@Router			/schedule/{action_id}/disable [patch]
 func (a APIServer) HandlerDisableScheduledAction(c *gin.Context) {
 	actionID := c.Param("action_id")
-	a.logger.Debug("Disabling action", zap.String("action_id", actionID))
+	a.requestLogger(c).Debug("Disabling action", zap.String("action_id", actionID))
 
 	err := a.sql.DisableScheduledAction(actionID)
 	if err != nil {
-		a.logger.Error("Failed to disable scheduled action", zap.String("action_id", actionID), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Failed to disable scheduled action", zap.String("action_id", actionID), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerPostScheduledAction processes the creation of new scheduled actions
@@ -167,13 +270,13 @@ func (a APIServer) HandlerDisableScheduledAction(c *gin.Context) {
 //	@Failure		500		{object}	GenericErrorResponse
 //	@Router			/schedule [post]
 func (a APIServer) HandlerPostScheduledAction(c *gin.Context) {
-	a.logger.Debug("Creating scheduled actions")
+	a.requestLogger(c).Debug("Creating scheduled actions")
 
 	// Getting scheduled actions list on request's body
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		a.logger.Error("Can't get body from request", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't get body from request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -183,29 +286,33 @@ func (a APIServer) HandlerPostScheduledAction(c *gin.Context) {
 	// Unmarshalling response
 	err = json.Unmarshal(body, &result)
 	if err != nil {
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	// Unmarshalling Actions by type
 	decodedActions, err := actions.DecodeActions(result)
 	if err != nil {
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	for _, action := range *decodedActions {
+		auditBodyResources(c, "cluster_id", action.GetTarget().ClusterID)
+	}
+
 	// Writing scheduled action
-	a.logger.Debug("Writing a new Scheduled Action", zap.Reflect("actions", decodedActions))
+	a.requestLogger(c).Debug("Writing a new Scheduled Action", zap.Reflect("actions", decodedActions))
 	err = a.sql.WriteScheduledActions(*decodedActions)
 	if err != nil {
-		a.logger.Error("Failed to create scheduled actions", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Failed to create scheduled actions", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
 	// TODO
 	// We should return at least ID, nil is not useful
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerPatchStatusScheduledActions modifies only the status field of a scheduled action
@@ -220,24 +327,24 @@ func (a APIServer) HandlerPostScheduledAction(c *gin.Context) {
 //	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/schedule/{action_id}/status [patch]
 func (a APIServer) HandlerPatchStatusScheduledActions(c *gin.Context) {
-	a.logger.Debug("Patching status of Scheduled Action Status")
+	a.requestLogger(c).Debug("Patching status of Scheduled Action Status")
 
 	actionID := c.Param("action_id")
 	status := c.Query("status")
 	if status == "" {
-		c.PureJSON(http.StatusBadRequest, NewGenericErrorResponse("Status parameter is required"))
+		respondError(c, http.StatusBadRequest, "Status parameter is required")
 		return
 	}
 
 	// Writing scheduled action
 	err := a.sql.PatchScheduledActionStatus(actionID, status)
 	if err != nil {
-		a.logger.Error("Failed to update scheduled action status", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Failed to update scheduled action status", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerPatchScheduledActions processes updates to scheduled actions
@@ -250,12 +357,12 @@ func (a APIServer) HandlerPatchStatusScheduledActions(c *gin.Context) {
 //	@Failure		500		{object}	GenericErrorResponse
 //	@Router			/schedule [patch]
 func (a APIServer) HandlerPatchScheduledActions(c *gin.Context) {
-	a.logger.Debug("Updating scheduled actions")
+	a.requestLogger(c).Debug("Updating scheduled actions")
 
 	// Getting scheduled actions list on request's body
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -265,27 +372,31 @@ func (a APIServer) HandlerPatchScheduledActions(c *gin.Context) {
 	// Unmarshalling response
 	err = json.Unmarshal(body, &result)
 	if err != nil {
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	// Unmarshalling Actions by type
 	decodedActions, err := actions.DecodeActions(result)
 	if err != nil {
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	for _, action := range *decodedActions {
+		auditBodyResources(c, "action_id", action.GetID())
+	}
+
 	// Writing scheduled action
-	a.logger.Debug("Patching Scheduled Actions", zap.Int("action_count", len(*decodedActions)))
+	a.requestLogger(c).Debug("Patching Scheduled Actions", zap.Int("action_count", len(*decodedActions)))
 	err = a.sql.PatchScheduledAction(*decodedActions)
 	if err != nil {
-		a.logger.Error("Failed to update scheduled actions", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Failed to update scheduled actions", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerDeleteScheduledAction permanently removes a scheduled action
@@ -299,15 +410,15 @@ func (a APIServer) HandlerPatchScheduledActions(c *gin.Context) {
 //	@Router			/schedule/{action_id} [delete]
 func (a APIServer) HandlerDeleteScheduledAction(c *gin.Context) {
 	actionID := c.Param("action_id")
-	a.logger.Debug("Removing a Scheduled Action", zap.String("action_id", actionID))
+	a.requestLogger(c).Debug("Removing a Scheduled Action", zap.String("action_id", actionID))
 
 	if err := a.sql.DeleteScheduledAction(actionID); err != nil {
-		a.logger.Error("Failed to delete scheduled action", zap.String("action_id", actionID), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Failed to delete scheduled action", zap.String("action_id", actionID), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // ==================== Expenses      Handlers ====================
@@ -319,20 +430,37 @@ func (a APIServer) HandlerDeleteScheduledAction(c *gin.Context) {
 //	@Tags			Expenses
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	ExpenseListResponse
-//	@Failure		500	{object}	GenericErrorResponse
+//	@Param			as_of	query		string	false	"Date (YYYY-MM-DD). Returns only the expenses of its billing period up to that date"
+//	@Success		200		{object}	ExpenseListResponse
+//	@Failure		400		{object}	GenericErrorResponse
+//	@Failure		500		{object}	GenericErrorResponse
 //	@Router			/expenses [get]
 func (a APIServer) HandlerGetExpenses(c *gin.Context) {
-	a.logger.Debug("Retrieving complete expenses list")
+	a.requestLogger(c).Debug("Retrieving complete expenses list")
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	expenses, err := a.sql.GetExpenses()
 	if err != nil {
-		a.logger.Error("Can't retrieve Expenses list", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't retrieve Expenses list", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewExpenseListResponse(expenses))
+	if asOf != nil {
+		expenses = filterExpensesAsOf(expenses, *asOf)
+	}
+
+	expenses, truncated := truncateResults(c, expenses, a.cfg.MaxResults)
+	response := NewExpenseListResponse(expenses)
+	response.Truncated = truncated
+	if err := streamExpenseList(c, http.StatusOK, response); err != nil {
+		a.requestLogger(c).Warn("Can't stream Expenses list", zap.Error(err))
+	}
 }
 
 // HandlerGetExpensesByInstance HandlerGetExpenseByID handles the request for obtain an Expense by its ID
@@ -343,21 +471,50 @@ func (a APIServer) HandlerGetExpenses(c *gin.Context) {
 //	@Accept			json
 //	@Produce		json
 //	@Param			instance_id	path		string	true	"Instance ID"
+//	@Param			as_of		query		string	false	"Date (YYYY-MM-DD). Returns only the expenses of its billing period up to that date"
 //	@Success		200			{object}	ExpenseListResponse
-//	@Failure		404			{object}	nil
+//	@Failure		400			{object}	GenericErrorResponse
+//	@Failure		404			{object}	GenericErrorResponse
+//	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/expenses/{instance_id} [get]
 func (a APIServer) HandlerGetExpensesByInstance(c *gin.Context) {
 	instanceID := c.Param("instance_id")
-	a.logger.Debug("Retrieving expenses by InstanceID", zap.String("instance_id", instanceID))
+	a.requestLogger(c).Debug("Retrieving expenses by InstanceID", zap.String("instance_id", instanceID))
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	expenses, err := a.sql.GetExpensesByInstance(instanceID)
 	if err != nil {
-		a.logger.Error("Instance not found", zap.String("instance_id", instanceID), zap.Error(err))
-		c.PureJSON(http.StatusNotFound, nil)
+		a.requestLogger(c).Error("Can't retrieve expenses of instance", zap.String("instance_id", instanceID), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
+	// The expenses lookup doesn't fail for unknown instances, so an empty list is checked against the instances
+	if len(expenses) == 0 {
+		exists, err := a.sql.CheckInstanceExists(instanceID)
+		if err != nil {
+			a.requestLogger(c).Error("Can't check instance", zap.String("instance_id", instanceID), zap.Error(err))
+			a.writeInventoryError(c, err)
+			return
+		}
+		if !exists {
+			respondError(c, http.StatusNotFound, fmt.Sprintf("instance '%s' not found", instanceID))
+			return
+		}
+	}
 
-	c.PureJSON(http.StatusOK, NewExpenseListResponse(expenses))
+	if asOf != nil {
+		expenses = filterExpensesAsOf(expenses, *asOf)
+	}
+
+	expenses, truncated := truncateResults(c, expenses, a.cfg.MaxResults)
+	response := NewExpenseListResponse(expenses)
+	response.Truncated = truncated
+	writeJSON(c, http.StatusOK, response)
 }
 
 // HandlerPostExpense handles the request for writing a new Expense in the inventory
@@ -376,54 +533,527 @@ func (a APIServer) HandlerPostExpense(c *gin.Context) {
 	// Getting expenses list on request's body
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		a.logger.Error("Can't get body from request", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't get body from request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	var expenses []inventory.Expense
 	err = json.Unmarshal(body, &expenses)
 	if err != nil {
-		a.logger.Error("Can't obtain data from body request", zap.Error(err))
-		c.PureJSON(http.StatusBadRequest, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't obtain data from body request", zap.Error(err))
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	for _, expense := range expenses {
+		auditBodyResources(c, "instance_id", expense.InstanceID)
+	}
 
 	// Writing expenses
-	a.logger.Debug("Writing a new Expense", zap.Reflect("expenses", expenses))
+	a.requestLogger(c).Debug("Writing a new Expense", zap.Reflect("expenses", expenses))
 	err = a.sql.WriteExpenses(expenses)
 	if err != nil {
-		a.logger.Error("Can't write new Expenses into DB", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't write new Expenses into DB", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, nil)
+}
+
+// HandlerGetCostDimensions handles the request for obtaining the configured cost dimensions
+//
+//	@Summary		Obtain cost dimensions
+//	@Description	Returns the cost dimensions configured on CIQ_COST_DIMENSIONS_FILE and the tag key of every provider
+//	@Tags			Expenses
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	CostDimensionsResponse
+//	@Router			/expenses/dimensions [get]
+func (a APIServer) HandlerGetCostDimensions(c *gin.Context) {
+	writeJSON(c, http.StatusOK, CostDimensionsResponse{Dimensions: a.costDimensions})
+}
+
+// HandlerGetCostByDimension handles the request for obtaining the instances cost aggregated by a cost dimension
+//
+//	@Summary		Obtain costs by dimension
+//	@Description	Returns the instances total cost aggregated by the value of a cost dimension across every provider, sorted by cost descending
+//	@Tags			Expenses
+//	@Accept			json
+//	@Produce		json
+//	@Param			dimension			path		string	true	"Cost dimension"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	CostByDimensionResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/expenses/dimensions/{dimension} [get]
+func (a APIServer) HandlerGetCostByDimension(c *gin.Context) {
+	dimension := c.Param("dimension")
+	a.requestLogger(c).Debug("Retrieving costs by dimension", zap.String("dimension", dimension))
+
+	if _, ok := a.costDimensions[dimension]; !ok {
+		respondError(c, http.StatusNotFound, fmt.Sprintf("cost dimension '%s' is not configured", dimension))
+		return
+	}
+
+	instances, err := a.instances.get()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, NewCostByDimensionResponse(dimension, costByDimension(instances, a.costDimensions, dimension)))
 }
 
 // ==================== Instances     Handlers ====================
 
+// removeExcludedInstances removes the instances tagged with CIQ_EXCLUDE_TAG,
+// unless the request sets 'include_excluded=true'. On errors, the error
+// response is written and false is returned.
+func (a APIServer) removeExcludedInstances(c *gin.Context, instances []inventory.Instance) ([]inventory.Instance, bool) {
+	excluded, ok := a.excludedInstanceIDs(c)
+	if !ok || excluded == nil {
+		return instances, ok
+	}
+	return filterInstancesExcluded(instances, excluded), true
+}
+
+// removeExcludedResources removes the instances tagged with CIQ_EXCLUDE_TAG
+// as removeExcludedInstances does, and subtracts their cost and count from
+// the given clusters and accounts, whose totals are computed by the DB over
+// every instance. On errors, the error response is written and false is
+// returned.
+func (a APIServer) removeExcludedResources(c *gin.Context, instances []inventory.Instance, clusters []inventory.Cluster, accounts []inventory.Account) ([]inventory.Instance, bool) {
+	excluded, ok := a.excludedInstanceIDs(c)
+	if !ok || excluded == nil {
+		return instances, ok
+	}
+	return removeExcludedInstanceTotals(instances, excluded, clusters, accounts), true
+}
+
+// excludedInstanceIDs returns the IDs of the instances tagged with
+// CIQ_EXCLUDE_TAG, or nil if they are not excluded (no tag configured or
+// 'include_excluded=true'). On errors, the error response is written and
+// false is returned.
+func (a APIServer) excludedInstanceIDs(c *gin.Context) (map[string]struct{}, bool) {
+	tag, ok := a.requestExcludeTag(c)
+	if !ok || tag == "" {
+		return nil, ok
+	}
+
+	ids, err := a.sql.GetInstanceIDsByTagKey(tag)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve excluded instances", zap.String("tag", tag), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return nil, false
+	}
+
+	excluded := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		excluded[id] = struct{}{}
+	}
+	return excluded, true
+}
+
+// requestExcludeTag returns the CIQ_EXCLUDE_TAG whose instances are excluded
+// from the response, or "" if none are (no tag configured or
+// 'include_excluded=true'). On errors, the error response is written and
+// false is returned.
+func (a APIServer) requestExcludeTag(c *gin.Context) (string, bool) {
+	if a.cfg.ExcludeTag == "" {
+		return "", true
+	}
+
+	include, err := parseBoolParam(c, includeExcludedParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+	if include != nil && *include {
+		return "", true
+	}
+	return a.cfg.ExcludeTag, true
+}
+
 // HandlerGetInstances handles the request for obtain the entire Instances list
 //
 //	@Summary		Obtain every Instance
-//	@Description	Returns a list of Instances with every Instance in the inventory
+//	@Description	Returns a list of Instances with every Instance in the inventory. The list is sent as a CSV attachment (id, name, provider, region, state, cluster, account, cost) with 'format=csv' or 'Accept: text/csv', and as YAML with 'format=yaml' or 'Accept: application/yaml'
 //	@Tags			Instances
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	InstanceListResponse
-//	@Failure		500	{object}	GenericErrorResponse
+//	@Produce		text/csv
+//	@Param			modified_since		query		string		false	"RFC3339 timestamp. Returns only instances created or scanned after it"
+//	@Param			role				query		string		false	"Returns only instances with this IAM role/service account"
+//	@Param			role_prefix			query		string		false	"Returns only instances whose IAM role/service account starts with this prefix"
+//	@Param			unnamed				query		bool		false	"Returns only the instances without name (true) or with name (false)"
+//	@Param			created_after		query		string		false	"Date (YYYY-MM-DD) or RFC3339 timestamp. Returns only instances created on or after it"
+//	@Param			created_before		query		string		false	"Date (YYYY-MM-DD) or RFC3339 timestamp. Returns only instances created before it"
+//	@Param			tz					query		string		false	"IANA timezone of the date filters (default CIQ_DEFAULT_TZ)"
+//	@Param			status				query		string		false	"Returns only the instances on this status. Provider states are normalized"	Enums(Running, Stopped, Terminated, Unknown)
+//	@Param			type				query		string		false	"Returns only the instances of this type (case insensitive)"
+//	@Param			provider			query		[]string	false	"Returns only the instances of any of these providers (repeatable)"										collectionFormat(multi)
+//	@Param			region				query		[]string	false	"Returns only the instances on any of these regions, derived from their availability zone (repeatable)"	collectionFormat(multi)
+//	@Param			state				query		[]string	false	"Returns only the instances on any of these provider states (repeatable)"								Enums(running, pending, stopping, stopped, shutting-down, terminated)	collectionFormat(multi)
+//	@Param			tag					query		[]string	false	"Returns only the instances with all of these tags, as 'key:value' (repeatable, case sensitive)"		collectionFormat(multi)
+//	@Param			embed				query		string		false	"Related data embedded on every instance"																Enums(cluster_labels)
+//	@Param			sort				query		string		false	"Sorting field. Inventory order (by name) if empty"														Enums(costPerHour, cost, name, region)
+//	@Param			order				query		string		false	"Sorting order"																							Enums(asc, desc)
+//	@Param			include_excluded	query		bool		false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Param			format				query		string		false	"Response format. Takes precedence over the Accept header (default json)"																																Enums(json, csv, yaml)
+//	@Param			group				query		string		false	"Response shape: a single list (default), indexed by cluster ID (by-cluster, InstancesByClusterResponse) or by account and cluster name (by-account, InstancesByAccountResponse). Not supported on CSV"	Enums(flat, by-cluster, by-account)
+//	@Param			limit				query		int			false	"Page size (default 100)"
+//	@Param			offset				query		int			false	"Number of items skipped before the page (default 0)"
+//	@Param			count				query		bool		false	"Returns only the number of matching instances (CountResponse and X-Total-Count header). Implied by HEAD"
+//	@Param			fields				query		string		false	"Comma separated list of fields kept on every item (e.g. name,region). Unknown fields are ignored and reported on the Warning header"
+//	@Param			exclude				query		string		false	"Comma separated list of fields removed from every item (e.g. tags)"
+//	@Success		200					{object}	InstanceListResponse
+//	@Header			200					{integer}	X-Total-Count	"Number of matching instances, on count only requests"
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Failure		503					{object}	GenericErrorResponse	"Inventory not yet populated"
 //	@Router			/instances [get]
+//	@Router			/instances [head]
 func (a APIServer) HandlerGetInstances(c *gin.Context) {
-	a.logger.Debug("Retrieving complete instance inventory")
+	a.requestLogger(c).Debug("Retrieving complete instance inventory")
+
+	filters, err := parseInstanceListFilters(c, a.location)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	embeds, err := parseEmbedParam(c, embedClusterLabels)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	instances, err := a.instances.get()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	if len(instances) == 0 && a.rejectUnpopulatedInventory(c) {
+		return
+	}
+
+	// Labels are collected before filtering, as they come from every instance of the cluster
+	if slices.Contains(embeds, embedClusterLabels) {
+		labels := clusterLabels(instances)
+		for i := range instances {
+			instances[i].ClusterLabels = labels[instances[i].ClusterID]
+		}
+	}
+
+	a.writeInstanceList(c, filters.apply(instances))
+}
+
+// writeInstanceList completes the instance list requests: removes the
+// excluded instances, updates the instances cost per hour, sorts them as requested by the 'sort' and 'order'
+// params and writes the InstanceListResponse. Count only requests receive the number of instances instead.
+// The cost per hour is only computed for the instances on the page, unless they are sorted by it
+func (a APIServer) writeInstanceList(c *gin.Context, instances []inventory.Instance) {
+	countOnly, err := isCountOnly(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	format, err := parseListFormat(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	group, err := parseInstancesGroup(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if group != groupFlat && format == exportFormatCSV {
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("'%s' is not supported on the %s format", groupParam, exportFormatCSV))
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+	if countOnly {
+		writeCount(c, len(instances))
+		return
+	}
+
+	// Sorting by cost per hour needs it on every instance, not only on the page
+	sortedByCostPerHour := c.Query(sortParam) == instanceSortCostPerHour
+	if sortedByCostPerHour && !a.loadInstancesCostPerHour(c, instances) {
+		return
+	}
+	if err := sortList(c, instances, instanceSortFields); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	total := len(instances)
+
+	instances, truncated := truncateResults(c, paginate(instances, limit, offset), a.cfg.MaxResults)
+	if !sortedByCostPerHour && !a.loadInstancesCostPerHour(c, instances) {
+		return
+	}
+	if format == exportFormatCSV {
+		a.writeInstanceListCSV(c, instances)
+		return
+	}
+	if group != groupFlat {
+		a.writeGroupedInstanceList(c, instances, group, total, truncated)
+		return
+	}
+
+	response := NewInstanceListResponse(instances)
+	response.Total = total
+	response.Truncated = truncated
+	if err := streamInstanceList(c, http.StatusOK, response); err != nil {
+		a.requestLogger(c).Warn("Can't stream Instances list", zap.Error(err))
+	}
+}
+
+// loadInstancesCostPerHour computes the cost per hour of the instances,
+// retrieving the status history of those instances only. It reports if it
+// succeeded, writing the error response otherwise
+func (a APIServer) loadInstancesCostPerHour(c *gin.Context, instances []inventory.Instance) bool {
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		ids = append(ids, instance.ID)
+	}
+	history, err := a.sql.GetInstancesStatusHistory(ids)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances status history", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return false
+	}
+	updateInstancesCostPerHour(instances, history)
+	return true
+}
+
+// writeGroupedInstanceList writes the instances list indexed by cluster or by
+// account and cluster. The cluster and account of every instance are resolved
+// from its cluster
+func (a APIServer) writeGroupedInstanceList(c *gin.Context, instances []inventory.Instance, group string, total int, truncated bool) {
+	clusters, err := a.sql.GetClusters()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	if group == groupByAccount {
+		response := NewInstancesByAccountResponse(instances, clusters)
+		response.Total = total
+		response.Truncated = truncated
+		writeJSON(c, http.StatusOK, response)
+		return
+	}
+
+	response := NewInstancesByClusterResponse(instances)
+	response.Total = total
+	response.Truncated = truncated
+	writeJSON(c, http.StatusOK, response)
+}
+
+// writeInstanceListCSV writes the instances list as a CSV attachment. The
+// account of every instance is resolved from its cluster
+func (a APIServer) writeInstanceListCSV(c *gin.Context, instances []inventory.Instance) {
+	clusters, err := a.sql.GetClusters()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	accounts := clusterAccounts(clusters)
+	writeBody := func(w io.Writer) error {
+		return writeInstancesCSV(w, instances, accounts, a.cfg.HiddenFields)
+	}
+
+	notModified, err := middleware.StreamETag(c, writeBody)
+	if err != nil {
+		a.requestLogger(c).Error("Can't write the instances list as CSV", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if notModified {
+		return
+	}
+
+	// The rows are sent as they are written, without being buffered by the middlewares
+	middleware.Stream(c)
+	c.Header("Content-Disposition", `attachment; filename="instances.csv"`)
+	c.Header("Content-Type", middleware.MIMECSV)
+	c.Status(http.StatusOK)
+	// The status is already sent, so the errors can only be logged
+	if err := writeBody(c.Writer); err != nil {
+		a.requestLogger(c).Error("Can't write the instances list as CSV", zap.Error(err))
+	}
+}
+
+// HandlerGetInstancesByOwner handles the request for obtaining the instances count and cost per owner
+//
+//	@Summary		Obtain instances count and cost per owner
+//	@Description	Returns, for each value of the Owner tag, the number of instances and their total cost sorted by cost descending. Instances without owner are grouped as "unassigned"
+//	@Tags			Instances
+//	@Accept			json
+//	@Produce		json
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	InstancesByOwnerResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/instances/by-owner [get]
+func (a APIServer) HandlerGetInstancesByOwner(c *gin.Context) {
+	a.requestLogger(c).Debug("Retrieving instances by owner")
+
+	instances, err := a.instances.get()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewInstancesByOwnerResponse(instances))
+}
+
+// HandlerGetInstancesCostOutliers handles the request for obtaining the instances with an anomalous cost within their account
+//
+//	@Summary		Obtain instances cost outliers
+//	@Description	Returns the instances whose total cost is more than N standard deviations above the mean instance cost of their account, sorted by deviation descending
+//	@Tags			Instances
+//	@Accept			json
+//	@Produce		json
+//	@Param			deviations			query		number	false	"Number of standard deviations above the mean (default CIQ_COST_OUTLIER_DEVIATIONS)"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	CostOutliersResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/instances/cost-outliers [get]
+func (a APIServer) HandlerGetInstancesCostOutliers(c *gin.Context) {
+	deviations, err := parsePositiveFloatParam(c, deviationsParam, a.cfg.CostOutlierDeviations)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving instances cost outliers", zap.Float64("deviations", deviations))
+
+	instances, err := a.sql.GetInstancesWithoutTags()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	clusters, err := a.sql.GetClusters()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+
+	outliers := findCostOutliers(instances, clusterAccounts(clusters), deviations)
+	writeJSON(c, http.StatusOK, NewCostOutliersResponse(deviations, outliers))
+}
+
+// HandlerGetInstancesStats handles the request for obtaining the instances count and cost by status
+//
+//	@Summary		Obtain instances stats by status
+//	@Description	Returns the number and total cost of the instances on each status (Running, Stopped, Terminated, Unknown), and of all the instances. Statuses without instances are omitted
+//	@Tags			Instances
+//	@Accept			json
+//	@Produce		json
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	InstanceStateStatsResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/instances/stats [get]
+func (a APIServer) HandlerGetInstancesStats(c *gin.Context) {
+	a.requestLogger(c).Debug("Retrieving instances stats by status")
+
+	instances, err := a.sql.GetInstancesWithoutTags()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewInstanceStateStatsResponse(instanceStateStats(instances)))
+}
+
+// HandlerGetInstancesOlderThan handles the request for obtaining the instances created more than N days ago
+//
+//	@Summary		Obtain instances older than N days
+//	@Description	Returns the instances created more than N days ago. Instances without creation timestamp are not included
+//	@Tags			Instances
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			days				path		int		true	"Minimum age (days) of the instances"
+//	@Param			sort				query		string	false	"Sorting field. Inventory order (by name) if empty"	Enums(costPerHour, cost, name, region)
+//	@Param			order				query		string	false	"Sorting order"										Enums(asc, desc)
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Param			format				query		string	false	"Response format. Takes precedence over the Accept header (default json)"																																Enums(json, csv, yaml)
+//	@Param			group				query		string	false	"Response shape: a single list (default), indexed by cluster ID (by-cluster, InstancesByClusterResponse) or by account and cluster name (by-account, InstancesByAccountResponse). Not supported on CSV"	Enums(flat, by-cluster, by-account)
+//	@Param			limit				query		int		false	"Page size (default 100)"
+//	@Param			offset				query		int		false	"Number of items skipped before the page (default 0)"
+//	@Success		200					{object}	InstanceListResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/instances/older-than/{days} [get]
+func (a APIServer) HandlerGetInstancesOlderThan(c *gin.Context) {
+	value := c.Param("days")
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("invalid days value (%s). Expected a positive integer", value))
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving instances older than", zap.Int("days", days))
 
-	instances, err := a.sql.GetInstances()
+	instances, err := a.instances.get()
 	if err != nil {
-		a.logger.Error("Can't retrieve Instances list", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewInstanceListResponse(instances))
+	instances, skipped := filterInstancesCreatedBefore(instances, time.Now().AddDate(0, 0, -days))
+	if skipped > 0 {
+		a.requestLogger(c).Info("Instances without creation timestamp skipped", zap.Int("days", days), zap.Int("skipped", skipped))
+	}
+
+	a.writeInstanceList(c, instances)
 }
 
 // HandlerGetInstancesForBillingUpdate handles the request for obtain a list of instances that needs to update its billing information
@@ -437,16 +1067,21 @@ func (a APIServer) HandlerGetInstances(c *gin.Context) {
 //	@Failure		500	{object}	GenericErrorResponse
 //	@Router			/instances/expense_update [get]
 func (a APIServer) HandlerGetInstancesForBillingUpdate(c *gin.Context) {
-	a.logger.Debug("Retrieving instances with outdated billing information")
+	a.requestLogger(c).Debug("Retrieving instances with outdated billing information")
 
 	instances, err := a.sql.GetInstancesOutdatedBilling()
 	if err != nil {
-		a.logger.Error("Can't retrieve Last Expenses list", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't retrieve Last Expenses list", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewInstanceListResponse(instances))
+	instances, truncated := truncateResults(c, instances, a.cfg.MaxResults)
+	response := NewInstanceListResponse(instances)
+	response.Truncated = truncated
+	if err := streamInstanceList(c, http.StatusOK, response); err != nil {
+		a.requestLogger(c).Warn("Can't stream Instances list", zap.Error(err))
+	}
 }
 
 // HandlerGetInstanceByID handles the request for obtain an Instance by its ID
@@ -457,21 +1092,31 @@ func (a APIServer) HandlerGetInstancesForBillingUpdate(c *gin.Context) {
 //	@Accept			json
 //	@Produce		json
 //	@Param			instance_id	path		string	true	"Instance ID"
+//	@Param			fields		query		string	false	"Comma separated list of fields kept on every item (e.g. name,region). Unknown fields are ignored and reported on the Warning header"
+//	@Param			exclude		query		string	false	"Comma separated list of fields removed from every item (e.g. tags)"
 //	@Success		200			{object}	InstanceListResponse
-//	@Failure		404			{object}	nil
+//	@Failure		404			{object}	GenericErrorResponse
+//	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/instances/{instance_id} [get]
 func (a APIServer) HandlerGetInstanceByID(c *gin.Context) {
 	instanceID := c.Param("instance_id")
-	a.logger.Debug("Retrieving instance by ID", zap.String("instance_id", instanceID))
+	a.requestLogger(c).Debug("Retrieving instance by ID", zap.String("instance_id", instanceID))
 
 	instances, err := a.sql.GetInstanceByID(instanceID)
 	if err != nil {
-		a.logger.Error("Instance not found", zap.String("instance_id", instanceID), zap.Error(err))
-		c.PureJSON(http.StatusNotFound, nil)
+		a.requestLogger(c).Error("Can't retrieve instance", zap.String("instance_id", instanceID), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	// Instance IDs are unique, so there's a single instance or none
+	if len(instances) == 0 {
+		a.requestLogger(c).Debug("Instance not found", zap.String("instance_id", instanceID))
+		respondError(c, http.StatusNotFound, fmt.Sprintf("instance '%s' not found", instanceID))
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewInstanceListResponse(instances))
+	writeJSON(c, http.StatusOK, NewInstanceListResponse(instances))
 }
 
 // HandlerPostInstance handles the request for writing a new Instance in the inventory
@@ -489,27 +1134,35 @@ func (a APIServer) HandlerGetInstanceByID(c *gin.Context) {
 func (a APIServer) HandlerPostInstance(c *gin.Context) {
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		a.logger.Error("Can't get body from request", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't get body from request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	var instances []inventory.Instance
 	err = json.Unmarshal(body, &instances)
 	if err != nil {
-		a.logger.Error("Can't obtain data from body request", zap.Error(err))
-		c.PureJSON(http.StatusBadRequest, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't obtain data from body request", zap.Error(err))
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, instance := range instances {
+		auditBodyResources(c, "instance_id", instance.ID)
+	}
+	if a.rejectInvalidBatch(c, "instances", inventory.ValidateInstances(instances)) {
 		return
 	}
 
-	a.logger.Debug("Writing a new Instance", zap.Reflect("instance", instances))
+	a.requestLogger(c).Debug("Writing a new Instance", zap.Reflect("instance", instances))
+	start := time.Now()
 	err = a.sql.WriteInstances(instances)
+	a.metrics.observeStockUpdate("instances", start)
 	if err != nil {
-		a.logger.Error("Can't write new instances into DB", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't write new instances into DB", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerDeleteInstance handles the request for removing an Instance in the inventory
@@ -521,41 +1174,99 @@ func (a APIServer) HandlerPostInstance(c *gin.Context) {
 //	@Produce		json
 //	@Param			instance_id	path		string	true	"Instance ID"
 //	@Success		200			{object}	nil
-//	@Failure		500			{object}	nil
+//	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/instances/{instance_id} [delete]
 //
 // TODO: Not Implemented
 func (a APIServer) HandlerDeleteInstance(c *gin.Context) {
 	instanceID := c.Param("instance_id")
-	a.logger.Debug("Removing an Instance", zap.String("instance_id", instanceID))
+	a.requestLogger(c).Debug("Removing an Instance", zap.String("instance_id", instanceID))
 
 	if err := a.sql.DeleteInstance(instanceID); err != nil {
-		a.logger.Error("Can't delete instance from DB", zap.String("instance_id", instanceID), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, nil)
+		a.requestLogger(c).Error("Can't delete instance from DB", zap.String("instance_id", instanceID), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
-// HandlerPatchInstance handles the request for patching an Instance in the inventory
+// HandlerProtectInstance handles the request for protecting an Instance from the power actions
 //
-//	@Summary		Patches an Instance in the inventory
-//	@Description	Receives and patch into the DB the information for an existing Instance
+//	@Summary		Protects an Instance
+//	@Description	Marks the Instance as protected. Protected instances are skipped by every power on/off action, instant or scheduled
 //	@Tags			Instances
-//	@Accept			json
 //	@Produce		json
-//	@Param			instance	body		inventory.Instance	true	"Instance to be modified"
-//	@Param			instance_id	path		string				true	"Instance ID"
-//	@Failure		501			{object}	nil					"Not Implemented"
-//	@Router			/instances/{instance_id} [patch]
-//
-// TODO: NOT IMPLEMENTED
-func (a APIServer) HandlerPatchInstance(c *gin.Context) {
+//	@Param			instance_id	path		string	true	"Instance ID"
+//	@Success		200			{object}	InstanceListResponse
+//	@Failure		404			{object}	GenericErrorResponse
+//	@Failure		500			{object}	GenericErrorResponse
+//	@Router			/instances/{instance_id}/protect [put]
+func (a APIServer) HandlerProtectInstance(c *gin.Context) {
+	a.setInstanceProtection(c, true)
+}
+
+// HandlerUnprotectInstance handles the request for removing the protection of an Instance
+//
+//	@Summary		Unprotects an Instance
+//	@Description	Removes the protection of the Instance, so it's affected again by the power actions
+//	@Tags			Instances
+//	@Produce		json
+//	@Param			instance_id	path		string	true	"Instance ID"
+//	@Success		200			{object}	InstanceListResponse
+//	@Failure		404			{object}	GenericErrorResponse
+//	@Failure		500			{object}	GenericErrorResponse
+//	@Router			/instances/{instance_id}/unprotect [put]
+func (a APIServer) HandlerUnprotectInstance(c *gin.Context) {
+	a.setInstanceProtection(c, false)
+}
+
+// setInstanceProtection updates the protected flag of the requested instance and returns it
+func (a APIServer) setInstanceProtection(c *gin.Context, protected bool) {
+	instanceID := c.Param("instance_id")
+	a.requestLogger(c).Debug("Updating instance protection", zap.String("instance_id", instanceID), zap.Bool("protected", protected))
+
+	instances, err := a.sql.GetInstanceByID(instanceID)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve instance", zap.String("instance_id", instanceID), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	if len(instances) == 0 {
+		respondError(c, http.StatusNotFound, fmt.Sprintf("instance %s not found", instanceID))
+		return
+	}
+
+	if err := a.sql.UpdateInstanceProtection(instanceID, protected); err != nil {
+		a.requestLogger(c).Error("Can't update instance protection", zap.String("instance_id", instanceID), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	for i := range instances {
+		instances[i].Protected = protected
+	}
+	writeJSON(c, http.StatusOK, NewInstanceListResponse(instances))
+}
+
+// HandlerPatchInstance handles the request for patching an Instance in the inventory
+//
+//	@Summary		Patches an Instance in the inventory
+//	@Description	Receives and patch into the DB the information for an existing Instance
+//	@Tags			Instances
+//	@Accept			json
+//	@Produce		json
+//	@Param			instance	body		inventory.Instance		true	"Instance to be modified"
+//	@Param			instance_id	path		string					true	"Instance ID"
+//	@Failure		501			{object}	GenericErrorResponse	"Not Implemented"
+//	@Router			/instances/{instance_id} [patch]
+//
+// TODO: NOT IMPLEMENTED
+func (a APIServer) HandlerPatchInstance(c *gin.Context) {
 	instanceID := c.Param("instance_id")
-	a.logger.Debug("Patching an Instance", zap.String("instance_id", instanceID))
+	a.requestLogger(c).Debug("Patching an Instance", zap.String("instance_id", instanceID))
 
-	c.PureJSON(http.StatusNotImplemented, nil)
+	respondError(c, http.StatusNotImplemented, "not implemented")
 }
 
 // ==================== Clusters      Handlers ====================
@@ -567,70 +1278,518 @@ func (a APIServer) HandlerPatchInstance(c *gin.Context) {
 //	@Tags			Clusters
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	ClusterListResponse
-//	@Failure		500	{object}	GenericErrorResponse
+//	@Param			modified_since		query		string		false	"RFC3339 timestamp. Returns only clusters created or scanned after it"
+//	@Param			min_uptime			query		number		false	"Returns only clusters with an uptime percentage greater or equal than it"
+//	@Param			max_uptime			query		number		false	"Returns only clusters with an uptime percentage lower or equal than it"
+//	@Param			min_instances		query		integer		false	"Returns only clusters with an instance count greater or equal than it"
+//	@Param			max_instances		query		integer		false	"Returns only clusters with an instance count lower or equal than it"
+//	@Param			status				query		string		false	"Returns only the clusters on this status"																	Enums(Running, Stopped, Terminated, Unknown)
+//	@Param			provider			query		[]string	false	"Returns only the clusters of any of these providers (repeatable). Unknown providers return an empty list"	collectionFormat(multi)
+//	@Param			name				query		string		false	"Returns only the clusters whose name matches it"
+//	@Param			match				query		string		false	"Name matching mode (default exact)"	Enums(exact, prefix, substring)
+//	@Param			ci					query		bool		false	"Case insensitive name matching"
+//	@Param			created_after		query		string		false	"Date (YYYY-MM-DD) or RFC3339 timestamp. Returns only clusters created on or after it"
+//	@Param			created_before		query		string		false	"Date (YYYY-MM-DD) or RFC3339 timestamp. Returns only clusters created before it"
+//	@Param			tz					query		string		false	"IANA timezone of the date filters (default CIQ_DEFAULT_TZ)"
+//	@Param			mode				query		string		false	"Response representation"							Enums(full, counts)
+//	@Param			sort				query		string		false	"Sorting field. Inventory order (by name) if empty"	Enums(cost, instanceCount, name, region)
+//	@Param			order				query		string		false	"Sorting order"										Enums(asc, desc)
+//	@Param			limit				query		int			false	"Page size (default 100)"
+//	@Param			offset				query		int			false	"Number of items skipped before the page (default 0)"
+//	@Param			count				query		bool		false	"Returns only the number of matching clusters (CountResponse and X-Total-Count header). Implied by HEAD"
+//	@Param			fields				query		string		false	"Comma separated list of fields kept on every item (e.g. name,region). Unknown fields are ignored and reported on the Warning header"
+//	@Param			exclude				query		string		false	"Comma separated list of fields removed from every item (e.g. Instances)"
+//	@Param			embed				query		string		false	"Related data embedded on every cluster (full mode only)"	Enums(account)
+//	@Param			include_excluded	query		bool		false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	ClusterListResponse
+//	@Header			200					{integer}	X-Total-Count	"Number of matching clusters, on count only requests"
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Failure		503					{object}	GenericErrorResponse	"Inventory not yet populated"
 //	@Router			/clusters [get]
+//	@Router			/clusters [head]
 func (a APIServer) HandlerGetClusters(c *gin.Context) {
-	a.logger.Debug("Retrieving complete clusters inventory")
+	a.requestLogger(c).Debug("Retrieving complete clusters inventory")
+
+	modifiedSince, err := parseModifiedSince(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	minUptime, err := parsePercentParam(c, minUptimeParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	maxUptime, err := parsePercentParam(c, maxUptimeParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	minInstances, err := parseCountParam(c, minInstancesParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	maxInstances, err := parseCountParam(c, maxInstancesParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	createdAfter, createdBefore, err := parseCreatedRange(c, a.location)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	match, ci, err := parseNameMatch(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	countOnly, err := isCountOnly(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	embeds, err := parseEmbedParam(c, embedAccount)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	clusters, err := a.sql.GetClusters()
 	if err != nil {
-		a.logger.Error("Can't retrieve Clusters list", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	if len(clusters) == 0 && a.rejectUnpopulatedInventory(c) {
+		return
+	}
+
+	// The excluded instances are removed before filtering, as they change the
+	// instance count and cost of the clusters. The count only requests only
+	// need the instances if some are excluded
+	excluded, ok := a.excludedInstanceIDs(c)
+	if !ok {
+		return
+	}
+	var instances []inventory.Instance
+	if !countOnly || excluded != nil {
+		if instances, err = a.sql.GetInstancesWithoutTags(); err != nil {
+			a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+			a.writeInventoryError(c, err)
+			return
+		}
+	}
+	if excluded != nil {
+		instances = removeExcludedInstanceTotals(instances, excluded, clusters, nil)
+	}
+
+	if modifiedSince != nil {
+		clusters = filterClustersModifiedSince(clusters, *modifiedSince)
+	}
+
+	if createdAfter != nil || createdBefore != nil {
+		clusters = filterClustersCreatedBetween(clusters, createdAfter, createdBefore)
+	}
+
+	if minInstances != nil || maxInstances != nil {
+		clusters = filterClustersByInstanceCount(clusters, minInstances, maxInstances)
+	}
+
+	if status := c.Query(statusParam); status != "" {
+		clusters = filterClustersByStatus(clusters, inventory.ProviderState(status).Status())
+	}
+
+	if providers := parseListParam(c, providerParam); len(providers) > 0 {
+		clusters = filterClustersByProvider(clusters, providers)
+	}
+
+	if name := c.Query(nameParam); name != "" {
+		clusters = filterClustersByName(clusters, name, match, ci)
+	}
+
+	// The uptime needs the status history, so it's computed last, only for the
+	// clusters matching the other filters
+	uptimeFiltered := minUptime != nil || maxUptime != nil
+	if !countOnly || uptimeFiltered {
+		ids := make([]string, 0, len(clusters))
+		for _, cluster := range clusters {
+			ids = append(ids, cluster.ID)
+		}
+		history, err := a.sql.GetClustersStatusHistory(ids)
+		if err != nil {
+			a.requestLogger(c).Error("Can't retrieve Clusters status history", zap.Error(err))
+			a.writeInventoryError(c, err)
+			return
+		}
+		updateClustersUptime(clusters, history)
+	}
+	if uptimeFiltered {
+		clusters = filterClustersByUptime(clusters, minUptime, maxUptime)
+	}
+
+	if countOnly {
+		writeCount(c, len(clusters))
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewClusterListResponse(clusters))
+	if err := sortList(c, clusters, clusterSortFields); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total := len(clusters)
+	switch mode := c.DefaultQuery(modeParam, clustersModeFull); mode {
+	case clustersModeFull:
+		costs := costByStatus(instances, func(instance inventory.Instance) string { return instance.ClusterID })
+		byCluster := instancesByCluster(instances)
+		for i := range clusters {
+			clusters[i].CostByStatus = costs[clusters[i].ID]
+			clusters[i].UpdateInstanceCounts(byCluster[clusters[i].ID])
+		}
+		clusters, truncated := truncateResults(c, paginate(clusters, limit, offset), a.cfg.MaxResults)
+		if slices.Contains(embeds, embedAccount) {
+			accounts, err := a.sql.GetAccounts()
+			if err != nil {
+				a.requestLogger(c).Error("Can't retrieve Accounts list", zap.Error(err))
+				a.writeInventoryError(c, err)
+				return
+			}
+			embedClusterAccounts(clusters, accounts)
+		}
+		response := NewClusterListResponse(clusters)
+		response.Total = total
+		response.Truncated = truncated
+		if err := streamClusterList(c, http.StatusOK, response); err != nil {
+			a.requestLogger(c).Warn("Can't stream Clusters list", zap.Error(err))
+		}
+	case clustersModeCounts:
+		attachInstancesToClusters(clusters, instances)
+		clusters, truncated := truncateResults(c, paginate(clusters, limit, offset), a.cfg.MaxResults)
+		response := NewClusterCountsListResponse(clusters)
+		response.Total = total
+		response.Truncated = truncated
+		writeJSON(c, http.StatusOK, response)
+	default:
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("invalid '%s' value (%s)", modeParam, mode))
+	}
 }
 
-// HandlerGetClustersByID handles the request for obtain a Cluster by its Name
+// HandlerPostClustersSchedule handles the request for scheduling the power on/off of a group of clusters
 //
-//	@Summary		Obtain a single Cluster by its Name
-//	@Description	Returns a list of Clusters with a single Cluster filtered by Name
+//	@Summary		Schedule power on/off for a group of clusters
+//	@Description	Creates cron actions for every non terminated cluster matching the selector (account, name pattern and/or labels) and returns the matched clusters. The created actions are managed by the /schedule endpoints
 //	@Tags			Clusters
 //	@Accept			json
 //	@Produce		json
-//	@Param			cluster_id	path		string	true	"Cluster ID"
+//	@Param			schedule	body		BulkClusterScheduleRequest	true	"Clusters selector and power schedule"
 //	@Success		200			{object}	ClusterListResponse
-//	@Failure		404			{object}	nil
+//	@Failure		400			{object}	GenericErrorResponse
+//	@Failure		500			{object}	GenericErrorResponse
+//	@Router			/clusters/schedule [post]
+func (a APIServer) HandlerPostClustersSchedule(c *gin.Context) {
+	var request BulkClusterScheduleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.requestLogger(c).Error("Can't obtain data from body request", zap.Error(err))
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := request.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	clusters, err := a.sql.GetClusters()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	// Labels come from the instances of every cluster, so they're only retrieved when selecting by them
+	var labels map[string]map[string]string
+	if len(request.Labels) > 0 {
+		instances, err := a.instances.get()
+		if err != nil {
+			a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+			a.writeInventoryError(c, err)
+			return
+		}
+		labels = clusterLabels(instances)
+	}
+	clusters = filterClustersBySelector(clusters, request.AccountName, request.NamePattern, request.Labels, labels)
+
+	var newActions []actions.Action
+	for _, cluster := range clusters {
+		auditBodyResources(c, "cluster_id", cluster.ID)
+		target := *actions.NewActionTarget(cluster.AccountName, cluster.Region, cluster.ID, nil)
+		if request.PowerOnCronExp != "" {
+			newActions = append(newActions, *actions.NewCronAction(actions.PowerOnCluster, target, "Pending", true, request.PowerOnCronExp))
+		}
+		if request.PowerOffCronExp != "" {
+			newActions = append(newActions, *actions.NewCronAction(actions.PowerOffCluster, target, "Pending", true, request.PowerOffCronExp))
+		}
+	}
+
+	if len(newActions) > 0 {
+		a.requestLogger(c).Debug("Writing clusters power schedule", zap.Int("clusters", len(clusters)), zap.Int("actions", len(newActions)))
+		if err := a.sql.WriteScheduledActions(newActions); err != nil {
+			a.requestLogger(c).Error("Failed to create scheduled actions", zap.Error(err))
+			a.writeInventoryError(c, err)
+			return
+		}
+	}
+
+	writeJSON(c, http.StatusOK, NewClusterListResponse(clusters))
+}
+
+// HandlerPostClustersBatch handles the request for looking up a set of clusters by name
+//
+//	@Summary		Look up a set of Clusters by name
+//	@Description	Returns every Cluster named like any of the requested names (a name can match clusters of several accounts), and the requested names without any Cluster as missing. Up to 100 names per request
+//	@Tags			Clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			batch	body		ClusterBatchRequest	true	"Cluster names to look up"
+//	@Success		200		{object}	ClusterBatchResponse
+//	@Failure		400		{object}	GenericErrorResponse
+//	@Failure		500		{object}	GenericErrorResponse
+//	@Router			/clusters/batch [post]
+func (a APIServer) HandlerPostClustersBatch(c *gin.Context) {
+	var request ClusterBatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.requestLogger(c).Error("Can't obtain data from body request", zap.Error(err))
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := request.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Looking up Clusters by name", zap.Int("names", len(request.Names)))
+
+	clusters, err := a.sql.GetClusters()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	clusters = filterItems(clusters, func(cluster inventory.Cluster) bool { return slices.Contains(request.Names, cluster.Name) })
+
+	writeJSON(c, http.StatusOK, NewClusterBatchResponse(request.Names, clusters))
+}
+
+// HandlerGetClustersByID handles the request for obtain a Cluster by its ID, or the Clusters matching a partial name
+//
+//	@Summary		Obtain a single Cluster by its ID, or the Clusters matching a partial name
+//	@Description	Returns a list of Clusters with the Cluster of the given ID. With 'match=prefix' or 'match=substring', returns the Clusters whose name starts with or contains the given value instead, which might be empty
+//	@Tags			Clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			cluster_id			path		string	true	"Cluster ID, or partial Cluster name when matching by prefix or substring"
+//	@Param			match				query		string	false	"Matching mode (default exact)"	Enums(exact, prefix, substring)
+//	@Param			ci					query		bool	false	"Case insensitive matching"
+//	@Param			fields				query		string	false	"Comma separated list of fields kept on every item (e.g. name,region). Unknown fields are ignored and reported on the Warning header"
+//	@Param			exclude				query		string	false	"Comma separated list of fields removed from every item (e.g. Instances)"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	ClusterListResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
 //	@Router			/clusters/{cluster_id} [get]
 func (a APIServer) HandlerGetClustersByID(c *gin.Context) {
-	clusterID := c.Param("cluster_id")
-	a.logger.Debug("Retrieving Cluster Tags by ID", zap.String("cluster_id", clusterID))
+	clusterID, err := parseNameParam(c, "cluster_id")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	match, ci, err := parseNameMatch(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving Cluster by ID", zap.String("cluster_id", clusterID), zap.String("match", match))
+
+	if match == matchExact && !ci {
+		clusters, err := a.sql.GetClusterByID(clusterID)
+		if err != nil {
+			a.requestLogger(c).Error("Can't retrieve cluster", zap.String("cluster_id", clusterID), zap.Error(err))
+			a.writeClusterLookupError(c, clusterID, err)
+			return
+		}
+		if !a.removeExcludedClusterTotals(c, clusters, func() ([]inventory.Instance, error) { return a.sql.GetInstancesOnCluster(clusters[0].ID) }) {
+			return
+		}
+		writeJSON(c, http.StatusOK, NewClusterListResponse(clusters))
+		return
+	}
 
-	clusters, err := a.sql.GetClusterByID(clusterID)
+	clusters, err := a.sql.GetClusters()
 	if err != nil {
-		a.logger.Error("Cluster not found", zap.String("cluster_id", clusterID), zap.Error(err))
-		c.PureJSON(http.StatusNotFound, nil)
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	if match == matchExact {
+		// Case insensitive lookup of the ID
+		clusters = filterItems(clusters, func(cluster inventory.Cluster) bool { return strings.EqualFold(cluster.ID, clusterID) })
+		if len(clusters) == 0 {
+			respondError(c, http.StatusNotFound, fmt.Sprintf("cluster '%s' not found", clusterID))
+			return
+		}
+	} else {
+		clusters = filterClustersByName(clusters, clusterID, match, ci)
+	}
+
+	clusters, truncated := truncateResults(c, clusters, a.cfg.MaxResults)
+	if !a.removeExcludedClusterTotals(c, clusters, a.sql.GetInstancesWithoutTags) {
 		return
 	}
+	response := NewClusterListResponse(clusters)
+	response.Truncated = truncated
+	writeJSON(c, http.StatusOK, response)
+}
+
+// removeExcludedClusterTotals subtracts the cost and count of the instances
+// tagged with CIQ_EXCLUDE_TAG from the clusters, as removeExcludedResources
+// does. The instances are loaded only if some are excluded. On errors, the
+// error response is written and false is returned.
+func (a APIServer) removeExcludedClusterTotals(c *gin.Context, clusters []inventory.Cluster, load func() ([]inventory.Instance, error)) bool {
+	excluded, ok := a.excludedInstanceIDs(c)
+	if !ok || excluded == nil || len(clusters) == 0 {
+		return ok
+	}
 
-	c.PureJSON(http.StatusOK, NewClusterListResponse(clusters))
+	instances, err := load()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return false
+	}
+	removeExcludedInstanceTotals(instances, excluded, clusters, nil)
+	return true
 }
 
 // HandlerGetInstancesOnCluster handles the request for obtain the list of Instances belonging to a specific Cluster
 //
 //	@Summary		Obtain Instances list belonging to a Cluster
-//	@Description	Returns a list of Instances belonging to a Cluster given by Name
+//	@Description	Returns a list of Instances belonging to a Cluster given by Name, supporting the same filters, sorting and formats than the Instances list
 //	@Tags			Clusters
 //	@Accept			json
 //	@Produce		json
-//	@Param			cluster_id	path		string	true	"Cluster ID"
-//	@Success		200			{object}	InstanceListResponse
-//	@Failure		500			{object}	nil
+//	@Produce		text/csv
+//	@Param			cluster_id			path		string		true	"Cluster ID"
+//	@Param			modified_since		query		string		false	"RFC3339 timestamp. Returns only instances created or scanned after it"
+//	@Param			role				query		string		false	"Returns only instances with this IAM role/service account"
+//	@Param			role_prefix			query		string		false	"Returns only instances whose IAM role/service account starts with this prefix"
+//	@Param			unnamed				query		bool		false	"Returns only the instances without name (true) or with name (false)"
+//	@Param			created_after		query		string		false	"Date (YYYY-MM-DD) or RFC3339 timestamp. Returns only instances created on or after it"
+//	@Param			created_before		query		string		false	"Date (YYYY-MM-DD) or RFC3339 timestamp. Returns only instances created before it"
+//	@Param			tz					query		string		false	"IANA timezone of the date filters (default CIQ_DEFAULT_TZ)"
+//	@Param			status				query		string		false	"Returns only the instances on this status. Provider states are normalized"	Enums(Running, Stopped, Terminated, Unknown)
+//	@Param			type				query		string		false	"Returns only the instances of this type (case insensitive)"
+//	@Param			provider			query		[]string	false	"Returns only the instances of any of these providers (repeatable)"										collectionFormat(multi)
+//	@Param			region				query		[]string	false	"Returns only the instances on any of these regions, derived from their availability zone (repeatable)"	collectionFormat(multi)
+//	@Param			state				query		[]string	false	"Returns only the instances on any of these provider states (repeatable)"								Enums(running, pending, stopping, stopped, shutting-down, terminated)	collectionFormat(multi)
+//	@Param			tag					query		[]string	false	"Returns only the instances with all of these tags, as 'key:value' (repeatable, case sensitive)"		collectionFormat(multi)
+//	@Param			sort				query		string		false	"Sorting field. Inventory order (by name) if empty"														Enums(costPerHour, cost, name, region)
+//	@Param			order				query		string		false	"Sorting order"																							Enums(asc, desc)
+//	@Param			include_excluded	query		bool		false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Param			format				query		string		false	"Response format. Takes precedence over the Accept header (default json)"																																Enums(json, csv, yaml)
+//	@Param			group				query		string		false	"Response shape: a single list (default), indexed by cluster ID (by-cluster, InstancesByClusterResponse) or by account and cluster name (by-account, InstancesByAccountResponse). Not supported on CSV"	Enums(flat, by-cluster, by-account)
+//	@Param			limit				query		int			false	"Page size (default 100)"
+//	@Param			offset				query		int			false	"Number of items skipped before the page (default 0)"
+//	@Param			fields				query		string		false	"Comma separated list of fields kept on every item (e.g. name,region). Unknown fields are ignored and reported on the Warning header"
+//	@Param			exclude				query		string		false	"Comma separated list of fields removed from every item (e.g. tags)"
+//	@Success		200					{object}	InstanceListResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
 //	@Router			/clusters/{cluster_id}/instances [get]
 func (a APIServer) HandlerGetInstancesOnCluster(c *gin.Context) {
 	clusterID := c.Param("cluster_id")
-	a.logger.Debug("Retrieving Cluster's Instances", zap.String("cluster_id", clusterID))
+	a.requestLogger(c).Debug("Retrieving Cluster's Instances", zap.String("cluster_id", clusterID))
+
+	filters, err := parseInstanceListFilters(c, a.location)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	instances, err := a.sql.GetInstancesOnCluster(clusterID)
 	if err != nil {
-		a.logger.Error("Can't retrieve instances on cluster", zap.String("cluster_id", clusterID), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't retrieve instances on cluster", zap.String("cluster_id", clusterID), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	a.writeInstanceList(c, filters.apply(instances))
+}
+
+// HandlerFilterInstancesOnCluster handles the request for looking up a set of instances within a Cluster
+//
+//	@Summary		Look up instances within a Cluster
+//	@Description	Returns the requested instances belonging to the Cluster with their full data. Requested IDs not belonging to the Cluster are reported as excluded
+//	@Tags			Clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			cluster_id			path		string							true	"Cluster ID"
+//	@Param			filter				body		ClusterInstancesFilterRequest	true	"Instance IDs to look up"
+//	@Param			include_excluded	query		bool							false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	ClusterInstancesFilterResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/clusters/{cluster_id}/instances/filter [post]
+func (a APIServer) HandlerFilterInstancesOnCluster(c *gin.Context) {
+	clusterID := c.Param("cluster_id")
+	a.requestLogger(c).Debug("Filtering Cluster's Instances", zap.String("cluster_id", clusterID))
+
+	var request ClusterInstancesFilterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.requestLogger(c).Error("Can't obtain data from body request", zap.Error(err))
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := request.Validate(); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := a.sql.GetClusterByID(clusterID); err != nil {
+		a.requestLogger(c).Error("Can't retrieve cluster", zap.String("cluster_id", clusterID), zap.Error(err))
+		a.writeClusterLookupError(c, clusterID, err)
+		return
+	}
+
+	instances, err := a.sql.GetInstancesOnClusterByIDs(clusterID, request.InstanceIDs)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve instances on cluster", zap.String("cluster_id", clusterID), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewInstanceListResponse(instances))
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewClusterInstancesFilterResponse(clusterID, request.InstanceIDs, instances))
 }
 
 // HandlerGetClusterTags handles the request for obtain the list of tags of a Cluster
@@ -642,20 +1801,69 @@ func (a APIServer) HandlerGetInstancesOnCluster(c *gin.Context) {
 //	@Produce		json
 //	@Param			cluster_id	path		string	true	"Cluster ID"
 //	@Success		200			{object}	TagListResponse
-//	@Failure		500			{object}	nil
+//	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/clusters/{cluster_id}/tags [get]
 func (a APIServer) HandlerGetClusterTags(c *gin.Context) {
 	clusterID := c.Param("cluster_id")
-	a.logger.Debug("Retrieving Cluster's Tags", zap.String("cluster_id", clusterID))
+	a.requestLogger(c).Debug("Retrieving Cluster's Tags", zap.String("cluster_id", clusterID))
 
 	tags, err := a.sql.GetClusterTags(clusterID)
 	if err != nil {
-		a.logger.Error("Can't retrieve Tags of cluster", zap.String("cluster_id", clusterID), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't retrieve Tags of cluster", zap.String("cluster_id", clusterID), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	tags, truncated := truncateResults(c, tags, a.cfg.MaxResults)
+	response := NewTagListResponse(tags)
+	response.Truncated = truncated
+	writeJSON(c, http.StatusOK, response)
+}
+
+// HandlerGetClusterCost handles the request for obtaining the cost of a Cluster on a billing window
+//
+//	@Summary		Obtain the cost of a Cluster
+//	@Description	Returns the cost of every instance of a Cluster given by ID, summed from their expenses. As the expenses are daily, the window is applied to their dates: from the day of 'from' (included) to the day of 'to' (excluded). Without window, the cost of every expense is returned
+//	@Tags			Clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			cluster_id	path		string	true	"Cluster ID"
+//	@Param			from		query		string	false	"Date (YYYY-MM-DD) or RFC3339 timestamp. First day of the window"
+//	@Param			to			query		string	false	"Date (YYYY-MM-DD) or RFC3339 timestamp. Day after the end of the window"
+//	@Param			tz			query		string	false	"IANA timezone of the window dates (default CIQ_DEFAULT_TZ)"
+//	@Success		200			{object}	ClusterCostResponse
+//	@Failure		400			{object}	GenericErrorResponse
+//	@Failure		404			{object}	GenericErrorResponse
+//	@Failure		500			{object}	GenericErrorResponse
+//	@Router			/clusters/{cluster_id}/cost [get]
+func (a APIServer) HandlerGetClusterCost(c *gin.Context) {
+	clusterID, err := parseNameParam(c, "cluster_id")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, to, err := parseBillingWindow(c, a.location)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving Cluster's cost", zap.String("cluster_id", clusterID))
+
+	if _, err := a.sql.GetClusterByID(clusterID); err != nil {
+		a.requestLogger(c).Error("Can't retrieve cluster", zap.String("cluster_id", clusterID), zap.Error(err))
+		a.writeClusterLookupError(c, clusterID, err)
+		return
+	}
+
+	costs, err := a.sql.GetClusterInstancesCost(clusterID, from, to)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve the cost of the cluster's instances", zap.String("cluster_id", clusterID), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewTagListResponse(tags))
+	writeJSON(c, http.StatusOK, NewClusterCostResponse(clusterID, from, to, costs))
 }
 
 // HandlerPostCluster handles the request for writing a new Cluster in the inventory
@@ -673,39 +1881,47 @@ func (a APIServer) HandlerGetClusterTags(c *gin.Context) {
 func (a APIServer) HandlerPostCluster(c *gin.Context) {
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		a.logger.Error("Can't get body from request", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't get body from request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	var clusters []inventory.Cluster
 	err = json.Unmarshal(body, &clusters)
 	if err != nil {
-		a.logger.Error("Can't obtain data from body request", zap.Error(err))
-		c.PureJSON(http.StatusBadRequest, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't obtain data from body request", zap.Error(err))
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, cluster := range clusters {
+		auditBodyResources(c, "cluster_id", cluster.ID)
+	}
+	if a.rejectInvalidBatch(c, "clusters", inventory.ValidateClusters(clusters)) {
 		return
 	}
 
-	a.logger.Debug("Writing new Clusters", zap.Reflect("clusters", clusters))
+	a.requestLogger(c).Debug("Writing new Clusters", zap.Reflect("clusters", clusters))
+	start := time.Now()
 	err = a.sql.WriteClusters(clusters)
+	a.metrics.observeStockUpdate("clusters", start)
 	if err != nil {
-		a.logger.Error("Can't write new Clusters into DB", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't write new Clusters into DB", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerPowerOnCluster handles startup of cluster instances
 //
 //	@Summary		Power on cluster
-//	@Description	Starts all instances in the specified cluster
+//	@Description	Starts all non protected instances in the specified cluster. Protected instances are reported as skipped, and a cluster with only protected instances is left untouched
 //	@Tags			Clusters
 //	@Accept			json
 //	@Produce		json
 //	@Param			cluster_id	path		string	true	"Cluster ID"
 //	@Success		200			{object}	nil
-//	@Failure		500			{object}	nil
+//	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/clusters/{cluster_id}/power_on [post]
 func (a APIServer) HandlerPowerOnCluster(c *gin.Context) {
 	// TODO. We must add validation logic (middleware, validator, whatever)
@@ -717,28 +1933,28 @@ func (a APIServer) HandlerPowerOnCluster(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.PureJSON(http.StatusBadRequest, NewGenericErrorResponse("Invalid request body"))
+		respondError(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	a.logger.Debug("Power On Cluster request received",
+	a.requestLogger(c).Debug("Power On Cluster request received",
 		zap.String("cluster_id", clusterID),
 		zap.String("triggered_by", request.TriggeredBy))
 
-	resp, err := a.handlePowerOn(clusterID, request.TriggeredBy, request.Description)
+	resp, err := a.handlePowerOn(a.requestLogger(c), clusterID, request.TriggeredBy, request.Description)
 	if err != nil {
-		a.logger.Error("Failed to power on cluster",
+		a.requestLogger(c).Error("Failed to power on cluster",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	c.PureJSON(http.StatusOK, resp)
+	writeJSON(c, http.StatusOK, resp)
 }
 
-func (a APIServer) handlePowerOn(clusterID, triggeredBy string, description *string) (*ClusterStatusChangeResponse, error) {
-	a.logger.Debug("Powering On Cluster",
+func (a APIServer) handlePowerOn(logger *zap.Logger, clusterID, triggeredBy string, description *string) (*ClusterStatusChangeResponse, error) {
+	logger.Debug("Powering On Cluster",
 		zap.String("cluster_id", clusterID),
 		zap.String("triggered_by", triggeredBy))
 
@@ -756,27 +1972,45 @@ func (a APIServer) handlePowerOn(clusterID, triggeredBy string, description *str
 	// Getting a new ClusterStatusChangeRequest for building the gRPC request
 	cscr, err := NewClusterStatusChangeRequest(a.sql, clusterID)
 	if err != nil {
-		a.logger.Error("Cannot get ClusterStatusChangeRequest for the PowerOn gRPC request",
+		logger.Error("Cannot get ClusterStatusChangeRequest for the PowerOn gRPC request",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
 		tracker.Failed()
 		return nil, fmt.Errorf("cannot get cluster status: %w", err)
 	}
 
+	// Status of the cluster once its non protected instances are Running
+	status := cscr.ResultingStatus(inventory.Running)
+
+	// Every instance is protected, so there's nothing to power on
+	if len(cscr.InstancesIdList) == 0 {
+		logger.Info("Cluster has only protected instances, skipping Power On request", zap.String("cluster_id", clusterID))
+		tracker.Success()
+		return NewClusterStatusChangeResponse(
+			cscr.AccountName,
+			cscr.ClusterID,
+			cscr.Region,
+			status,
+			cscr.InstancesIdList,
+			cscr.SkippedInstancesIdList,
+			nil,
+		), nil
+	}
+
 	// RPC call for power on a cluster
 	if err := a.grpc.PowerOnCluster(cscr); err != nil {
-		a.logger.Error("Error processing Cluster Power On request",
+		logger.Error("Error processing Cluster Power On request",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
 		tracker.Failed()
 		return nil, fmt.Errorf("error processing power on request: %w", err)
 	}
 
-	a.logger.Info("Cluster Powered On successfully", zap.String("cluster_id", clusterID))
+	logger.Info("Cluster Powered On successfully", zap.String("cluster_id", clusterID))
 
 	// Update cluster status in DB
-	if err := a.sql.UpdateClusterStatusByClusterID("Running", clusterID); err != nil {
-		a.logger.Error("Error updating status in DB",
+	if err := a.sql.UpdateClusterStatusByClusterID(string(status), string(inventory.Running), clusterID); err != nil {
+		logger.Error("Error updating status in DB",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
 		tracker.Failed()
@@ -790,8 +2024,9 @@ func (a APIServer) handlePowerOn(clusterID, triggeredBy string, description *str
 		cscr.AccountName,
 		cscr.ClusterID,
 		cscr.Region,
-		inventory.Running,
+		status,
 		cscr.InstancesIdList,
+		cscr.SkippedInstancesIdList,
 		nil,
 	), nil
 }
@@ -799,13 +2034,13 @@ func (a APIServer) handlePowerOn(clusterID, triggeredBy string, description *str
 // HandlerPowerOffCluster handles graceful shutdown of cluster instances
 //
 //	@Summary		Power off cluster
-//	@Description	Gracefully stops all instances in the specified cluster
+//	@Description	Gracefully stops all non protected instances in the specified cluster. Protected instances are reported as skipped, and a cluster with only protected instances is left untouched
 //	@Tags			Clusters
 //	@Accept			json
 //	@Produce		json
 //	@Param			cluster_id	path		string	true	"Cluster ID"
 //	@Success		200			{object}	nil
-//	@Failure		500			{object}	nil
+//	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/clusters/{cluster_id}/power_off [post]
 func (a APIServer) HandlerPowerOffCluster(c *gin.Context) {
 	// TODO. We must add validation logic (middleware, validator, whatever)
@@ -817,28 +2052,28 @@ func (a APIServer) HandlerPowerOffCluster(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.PureJSON(http.StatusBadRequest, NewGenericErrorResponse("Invalid request body"))
+		respondError(c, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	a.logger.Debug("Power Off Cluster request received",
+	a.requestLogger(c).Debug("Power Off Cluster request received",
 		zap.String("cluster_id", clusterID),
 		zap.String("triggered_by", request.TriggeredBy))
 
-	resp, err := a.handlePowerOff(clusterID, request.TriggeredBy, request.Description)
+	resp, err := a.handlePowerOff(a.requestLogger(c), clusterID, request.TriggeredBy, request.Description)
 	if err != nil {
-		a.logger.Error("Failed to power off cluster",
+		a.requestLogger(c).Error("Failed to power off cluster",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	c.PureJSON(http.StatusOK, resp)
+	writeJSON(c, http.StatusOK, resp)
 }
 
-func (a APIServer) handlePowerOff(clusterID, triggeredBy string, description *string) (*ClusterStatusChangeResponse, error) {
-	a.logger.Debug("Powering Off Cluster",
+func (a APIServer) handlePowerOff(logger *zap.Logger, clusterID, triggeredBy string, description *string) (*ClusterStatusChangeResponse, error) {
+	logger.Debug("Powering Off Cluster",
 		zap.String("cluster_id", clusterID),
 		zap.String("triggered_by", triggeredBy))
 
@@ -855,27 +2090,45 @@ func (a APIServer) handlePowerOff(clusterID, triggeredBy string, description *st
 	// Getting a new ClusterStatusChangeRequest for building the gRPC request
 	cscr, err := NewClusterStatusChangeRequest(a.sql, clusterID)
 	if err != nil {
-		a.logger.Error("Cannot get ClusterStatusChangeRequest for the PowerOff gRPC request",
+		logger.Error("Cannot get ClusterStatusChangeRequest for the PowerOff gRPC request",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
 		tracker.Failed()
 		return nil, fmt.Errorf("cannot get cluster status: %w", err)
 	}
 
+	// Status of the cluster once its non protected instances are Stopped
+	status := cscr.ResultingStatus(inventory.Stopped)
+
+	// Every instance is protected, so there's nothing to power off
+	if len(cscr.InstancesIdList) == 0 {
+		logger.Info("Cluster has only protected instances, skipping Power Off request", zap.String("cluster_id", clusterID))
+		tracker.Success()
+		return NewClusterStatusChangeResponse(
+			cscr.AccountName,
+			cscr.ClusterID,
+			cscr.Region,
+			status,
+			cscr.InstancesIdList,
+			cscr.SkippedInstancesIdList,
+			nil,
+		), nil
+	}
+
 	// RPC call for power off a cluster
 	if err := a.grpc.PowerOffCluster(cscr); err != nil {
-		a.logger.Error("Error processing Cluster Power Off request",
+		logger.Error("Error processing Cluster Power Off request",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
 		tracker.Failed()
 		return nil, fmt.Errorf("error processing power off request: %w", err)
 	}
 
-	a.logger.Info("Cluster Powered Off successfully", zap.String("cluster_id", clusterID))
+	logger.Info("Cluster Powered Off successfully", zap.String("cluster_id", clusterID))
 
 	// Update cluster status in DB
-	if err := a.sql.UpdateClusterStatusByClusterID("Stopped", clusterID); err != nil {
-		a.logger.Error("Error updating status in DB",
+	if err := a.sql.UpdateClusterStatusByClusterID(string(status), string(inventory.Stopped), clusterID); err != nil {
+		logger.Error("Error updating status in DB",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
 		tracker.Failed()
@@ -889,8 +2142,9 @@ func (a APIServer) handlePowerOff(clusterID, triggeredBy string, description *st
 		cscr.AccountName,
 		cscr.ClusterID,
 		cscr.Region,
-		inventory.Stopped,
+		status,
 		cscr.InstancesIdList,
+		cscr.SkippedInstancesIdList,
 		nil,
 	), nil
 }
@@ -904,19 +2158,19 @@ func (a APIServer) handlePowerOff(clusterID, triggeredBy string, description *st
 //	@Produce		json
 //	@Param			cluster_id	path		string	true	"Cluster ID"
 //	@Success		200			{object}	nil
-//	@Failure		500			{object}	nil
+//	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/clusters/{cluster_id} [delete]
 func (a APIServer) HandlerDeleteCluster(c *gin.Context) {
 	clusterName := c.Param("cluster_id")
-	a.logger.Debug("Removing a Cluster", zap.String("cluster_id", clusterName))
+	a.requestLogger(c).Debug("Removing a Cluster", zap.String("cluster_id", clusterName))
 
 	if err := a.sql.DeleteCluster(clusterName); err != nil {
-		a.logger.Error("Can't delete Cluster from DB", zap.String("cluster_id", clusterName), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't delete Cluster from DB", zap.String("cluster_id", clusterName), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerPatchCluster handles the request for patching a Cluster in the inventory
@@ -929,15 +2183,15 @@ func (a APIServer) HandlerDeleteCluster(c *gin.Context) {
 //	@Param			cluster_id	path		string				true	"Cluster ID"
 //	@Param			cluster		body		inventory.Cluster	true	"Cluster to be modified"
 //	@Success		200			{object}	nil
-//	@Failure		501			{object}	nil	"Not Implemented"
+//	@Failure		501			{object}	GenericErrorResponse	"Not Implemented"
 //	@Router			/clusters/{cluster_id} [patch]
 //
 // TODO: NOT IMPLEMENTED
 func (a APIServer) HandlerPatchCluster(c *gin.Context) {
 	clusterID := c.Param("cluster_id")
-	a.logger.Debug("Patching a Cluster", zap.String("cluster_id", clusterID))
+	a.requestLogger(c).Debug("Patching a Cluster", zap.String("cluster_id", clusterID))
 
-	c.PureJSON(http.StatusNotImplemented, nil)
+	respondError(c, http.StatusNotImplemented, "not implemented")
 }
 
 // ==================== Accounts      Handlers ====================
@@ -949,70 +2203,460 @@ func (a APIServer) HandlerPatchCluster(c *gin.Context) {
 //	@Tags			Accounts
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	AccountListResponse
-//	@Failure		500	{object}	nil
+//	@Param			modified_since		query		string	false	"RFC3339 timestamp. Returns only accounts scanned after it"
+//	@Param			sort				query		string	false	"Sorting field. Inventory order (by name) if empty"	Enums(clusterCount, cost, name)
+//	@Param			order				query		string	false	"Sorting order"										Enums(asc, desc)
+//	@Param			limit				query		int		false	"Page size (default 100)"
+//	@Param			offset				query		int		false	"Number of items skipped before the page (default 0)"
+//	@Param			count				query		bool	false	"Returns only the number of matching accounts (CountResponse and X-Total-Count header). Implied by HEAD"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	AccountListResponse
+//	@Header			200					{integer}	X-Total-Count	"Number of matching accounts, on count only requests"
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Failure		503					{object}	GenericErrorResponse	"Inventory not yet populated"
 //	@Router			/accounts [get]
+//	@Router			/accounts [head]
 func (a APIServer) HandlerGetAccounts(c *gin.Context) {
-	a.logger.Debug("Retrieving complete Accounts inventory")
+	a.requestLogger(c).Debug("Retrieving complete Accounts inventory")
 
-	accounts, err := a.sql.GetAccounts()
+	modifiedSince, err := parseModifiedSince(c)
 	if err != nil {
-		a.logger.Error("Can't retrieve Accounts list", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewAccountListResponse(accounts))
-}
-
-// HandlerGetAccountsByName handles the request for obtain an Account by its Name
-//
-//	@Summary		Obtain a single Account by its Name
-//	@Description	Returns a list of Accounts with a single Account filtered by Name
-//	@Tags			Accounts
-//	@Accept			json
-//	@Produce		json
-//	@Param			account_name	path		string	true	"Account Name"
-//	@Success		200				{object}	AccountListResponse
-//	@Failure		404				{object}	GenericErrorResponse
-//	@Router			/accounts/{account_name} [get]
-func (a APIServer) HandlerGetAccountsByName(c *gin.Context) {
-	accountName := c.Param("account_name")
-	a.logger.Debug("Retrieving Account by Name", zap.String("account_name", accountName))
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	accounts, err := a.sql.GetAccountByName(accountName)
+	countOnly, err := isCountOnly(c)
 	if err != nil {
-		a.logger.Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
-		c.PureJSON(http.StatusNotFound, NewGenericErrorResponse(err.Error()))
+		respondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	c.PureJSON(http.StatusOK, NewAccountListResponse(accounts))
-}
+	accounts, err := a.sql.GetAccounts()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Accounts list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	if len(accounts) == 0 && a.rejectUnpopulatedInventory(c) {
+		return
+	}
+
+	if modifiedSince != nil {
+		accounts = filterAccountsModifiedSince(accounts, *modifiedSince)
+	}
+	if countOnly {
+		writeCount(c, len(accounts))
+		return
+	}
+
+	clusters, err := a.sql.GetClusters()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	instances, err := a.sql.GetInstancesWithoutTags()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	instances, ok := a.removeExcludedResources(c, instances, clusters, accounts)
+	if !ok {
+		return
+	}
+
+	// Sorted once the excluded costs are subtracted
+	if err := sortList(c, accounts, accountSortFields); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	accountsByCluster := clusterAccounts(clusters)
+	costs := costByStatus(instances, func(instance inventory.Instance) string { return accountsByCluster[instance.ClusterID] })
+	for i := range accounts {
+		accounts[i].CostByStatus = costs[accounts[i].Name]
+	}
+
+	total := len(accounts)
+	accounts, truncated := truncateResults(c, paginate(accounts, limit, offset), a.cfg.MaxResults)
+	response := NewAccountListResponse(accounts)
+	response.Total = total
+	response.Truncated = truncated
+	if err := streamAccountList(c, http.StatusOK, response); err != nil {
+		a.requestLogger(c).Warn("Can't stream Accounts list", zap.Error(err))
+	}
+}
+
+// HandlerGetAccountsByName handles the request for obtain an Account by its Name
+//
+//	@Summary		Obtain a single Account by its Name
+//	@Description	Returns a list of Accounts with a single Account filtered by Name
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			account_name		path		string	true	"Account Name or alias"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	AccountListResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/accounts/{account_name} [get]
+func (a APIServer) HandlerGetAccountsByName(c *gin.Context) {
+	accountName, err := parseNameParam(c, "account_name")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving Account by Name", zap.String("account_name", accountName))
+
+	accounts, err := a.sql.GetAccountByName(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+		a.writeAccountLookupError(c, accountName, err)
+		return
+	}
+
+	// The excluded instances are subtracted from the account cost, as /accounts does
+	excluded, ok := a.excludedInstanceIDs(c)
+	if !ok {
+		return
+	}
+	if excluded != nil {
+		clusters, err := a.sql.GetClustersOnAccount(accounts[0].Name)
+		if err != nil {
+			a.requestLogger(c).Error("Can't retrieve clusters on account", zap.String("account_name", accounts[0].Name), zap.Error(err))
+			a.writeInventoryError(c, err)
+			return
+		}
+		instances, err := a.sql.GetInstancesOnAccount(accounts[0].Name)
+		if err != nil {
+			a.requestLogger(c).Error("Can't retrieve instances on account", zap.String("account_name", accounts[0].Name), zap.Error(err))
+			a.writeInventoryError(c, err)
+			return
+		}
+		removeExcludedInstanceTotals(instances, excluded, clusters, accounts)
+	}
+
+	writeJSON(c, http.StatusOK, NewAccountListResponse(accounts))
+}
 
 // HandlerGetClustersOnAccount handles the request for obtain the list of clusters deployed on a specific Account
 //
 //	@Summary		Obtain Cluster list on an Account
-//	@Description	Returns a list of Clusters which belongs to an Account given by Name
+//	@Description	Returns a list of Clusters which belongs to an Account given by Name. The clusters instances are not included unless requested
 //	@Tags			Accounts
 //	@Accept			json
 //	@Produce		json
-//	@Param			account_name	path		string	true	"Account Name"
-//	@Success		200				{object}	ClusterListResponse
-//	@Failure		500				{object}	nil
+//	@Param			account_name		path		string	true	"Account Name or alias"
+//	@Param			instances			query		bool	false	"Includes the instances of every cluster (default false)"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	ClusterListResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
 //	@Router			/accounts/{account_name}/clusters [get]
 func (a APIServer) HandlerGetClustersOnAccount(c *gin.Context) {
-	accountName := c.Param("account_name")
-	a.logger.Debug("Retrieving Account's Clusters", zap.String("account_name", accountName))
+	accountName, err := parseNameParam(c, "account_name")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving Account's Clusters", zap.String("account_name", accountName))
+
+	withInstances, err := parseBoolParam(c, instancesParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Aliases are resolved to the account name
+	accounts, err := a.sql.GetAccountByName(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+		a.writeAccountLookupError(c, accountName, err)
+		return
+	}
+	accountName = accounts[0].Name
 
 	clusters, err := a.sql.GetClustersOnAccount(accountName)
 	if err != nil {
-		a.logger.Error("Can't retrieve clusters on account", zap.String("account_name", accountName), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't retrieve clusters on account", zap.String("account_name", accountName), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	// The instances are needed to subtract the excluded ones even if they are not included
+	instances, err := a.sql.GetInstancesOnAccount(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve instances on account", zap.String("account_name", accountName), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	instances, ok := a.removeExcludedResources(c, instances, clusters, nil)
+	if !ok {
 		return
 	}
+	if withInstances != nil && *withInstances {
+		attachInstancesToClusters(clusters, instances)
+	}
 
-	c.PureJSON(http.StatusOK, NewClusterListResponse(clusters))
+	clusters, truncated := truncateResults(c, clusters, a.cfg.MaxResults)
+	response := NewClusterListResponse(clusters)
+	response.Truncated = truncated
+	writeJSON(c, http.StatusOK, response)
+}
+
+// HandlerGetAccountSummary handles the request for obtaining the summary of an Account
+//
+//	@Summary		Obtain the summary of an Account
+//	@Description	Returns the clusters and instances count, the instances count by status and the total cost of an Account, without its clusters and instances
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			account_name		path		string	true	"Account Name or alias"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	AccountSummaryResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/accounts/{account_name}/summary [get]
+func (a APIServer) HandlerGetAccountSummary(c *gin.Context) {
+	accountName, err := parseNameParam(c, "account_name")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving Account's summary", zap.String("account_name", accountName))
+
+	// Aliases are resolved to the account name
+	accounts, err := a.sql.GetAccountByName(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+		a.writeAccountLookupError(c, accountName, err)
+		return
+	}
+	accountName = accounts[0].Name
+
+	clusters, err := a.sql.GetClustersOnAccount(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve clusters on account", zap.String("account_name", accountName), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, err := a.sql.GetInstancesOnAccount(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve instances on account", zap.String("account_name", accountName), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedResources(c, instances, clusters, nil)
+	if !ok {
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewAccountSummaryResponse(accountName, clusters, instances))
+}
+
+// HandlerGetAccountUtilization handles the request for obtaining the CPU utilization rollup of an Account
+//
+//	@Summary		Obtain the CPU utilization rollup of an Account
+//	@Description	Returns the average/min/max CPU utilization of the running instances of an Account and the number of idle ones. Instances with an unknown CPU utilization are left out of both
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			account_name		path		string	true	"Account Name or alias"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	AccountUtilizationResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/accounts/{account_name}/utilization [get]
+func (a APIServer) HandlerGetAccountUtilization(c *gin.Context) {
+	accountName, err := parseNameParam(c, "account_name")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving Account's utilization", zap.String("account_name", accountName))
+
+	accounts, err := a.sql.GetAccountByName(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+		a.writeAccountLookupError(c, accountName, err)
+		return
+	}
+	accountName = accounts[0].Name
+
+	instances, err := a.sql.GetInstancesOnAccount(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve instances on account", zap.String("account_name", accountName), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewAccountUtilizationResponse(accountName, instances))
+}
+
+// HandlerGetAccountScanInfo handles the request for obtaining the timing and rate limiting context of the last scan of an Account
+//
+//	@Summary		Obtain the last scan info of an Account
+//	@Description	Returns the start/end time and duration of the last scan of an Account, and whether the provider API throttled it
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			account_name	path		string	true	"Account Name or alias"
+//	@Success		200				{object}	AccountScanInfoResponse
+//	@Failure		400				{object}	GenericErrorResponse
+//	@Failure		404				{object}	GenericErrorResponse
+//	@Router			/accounts/{account_name}/scan-info [get]
+func (a APIServer) HandlerGetAccountScanInfo(c *gin.Context) {
+	accountName, err := parseNameParam(c, "account_name")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	a.requestLogger(c).Debug("Retrieving Account's scan info", zap.String("account_name", accountName))
+
+	accounts, err := a.sql.GetAccountByName(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+		a.writeAccountLookupError(c, accountName, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewAccountScanInfoResponse(accounts[0]))
+}
+
+// HandlerSearchOnAccount handles the request for searching clusters and instances within an Account
+//
+//	@Summary		Search clusters and instances of an Account
+//	@Description	Returns the clusters and instances of the Account matching the query (case insensitive)
+//	@Tags			Accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			account_name		path		string	true	"Account Name or alias"
+//	@Param			q					query		string	true	"Text to search"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	SearchResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/accounts/{account_name}/search [get]
+func (a APIServer) HandlerSearchOnAccount(c *gin.Context) {
+	accountName, err := parseNameParam(c, "account_name")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := strings.TrimSpace(c.Query(searchQueryParam))
+	a.requestLogger(c).Debug("Searching on Account", zap.String("account_name", accountName), zap.String("query", query))
+
+	if query == "" {
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("missing '%s' param", searchQueryParam))
+		return
+	}
+
+	accounts, err := a.sql.GetAccountByName(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+		a.writeAccountLookupError(c, accountName, err)
+		return
+	}
+	accountName = accounts[0].Name
+
+	clusters, err := a.sql.GetClustersOnAccount(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve clusters on account", zap.String("account_name", accountName), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, err := a.sql.GetInstancesOnAccount(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve instances on account", zap.String("account_name", accountName), zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewSearchResponse(query, searchClusters(clusters, query), searchInstances(instances, query)))
+}
+
+// HandlerSearch handles the request for searching accounts, clusters and instances on the whole inventory
+//
+//	@Summary		Search the inventory
+//	@Description	Returns the accounts (ID, name or alias), clusters and instances matching the query (case insensitive substring), capped to 'limit' results per category
+//	@Tags			Inventory
+//	@Accept			json
+//	@Produce		json
+//	@Param			q					query		string	true	"Text to search"
+//	@Param			limit				query		int		false	"Maximum number of results of every category (default 20)"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	InventorySearchResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/search [get]
+func (a APIServer) HandlerSearch(c *gin.Context) {
+	query := strings.TrimSpace(c.Query(searchQueryParam))
+	a.requestLogger(c).Debug("Searching on the inventory", zap.String("query", query))
+
+	if query == "" {
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("missing '%s' param", searchQueryParam))
+		return
+	}
+
+	limit, err := parseLimit(c, defaultSearchLimit)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	accounts, err := a.sql.GetAccounts()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Accounts list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	clusters, err := a.sql.GetClusters()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, err := a.instances.get()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewInventorySearchResponse(query, limit, searchAccounts(accounts, query), searchClusters(clusters, query), searchInstances(instances, query)))
 }
 
 // HandlerPostAccount handles the request for writing a new Account in the inventory
@@ -1024,34 +2668,42 @@ func (a APIServer) HandlerGetClustersOnAccount(c *gin.Context) {
 //	@Produce		json
 //	@Param			account	body		inventory.Account	true	"New Account to be added"
 //	@Success		200		{object}	nil
-//	@Failure		400		{object}	nil
+//	@Failure		400		{object}	GenericErrorResponse
 //	@Failure		500		{object}	GenericErrorResponse
 //	@Router			/accounts [post]
 func (a APIServer) HandlerPostAccount(c *gin.Context) {
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		a.logger.Error("Can't get body from request", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't get body from request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	var accounts []inventory.Account
 	err = json.Unmarshal(body, &accounts)
 	if err != nil {
-		a.logger.Error("Can't obtain data from body request", zap.Error(err))
-		c.PureJSON(http.StatusBadRequest, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't obtain data from body request", zap.Error(err))
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, account := range accounts {
+		auditBodyResources(c, "account_name", account.Name)
+	}
+	if a.rejectInvalidBatch(c, "accounts", inventory.ValidateAccounts(accounts)) {
 		return
 	}
 
-	a.logger.Debug("Writing a new Account", zap.Reflect("accounts", accounts))
+	a.requestLogger(c).Debug("Writing a new Account", zap.Reflect("accounts", accounts))
+	start := time.Now()
 	err = a.sql.WriteAccounts(accounts)
+	a.metrics.observeStockUpdate("accounts", start)
 	if err != nil {
-		a.logger.Error("Can't write new Accounts into DB", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't write new Accounts into DB", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerDeleteAccount handles the request for deleting an Account in the inventory
@@ -1067,15 +2719,15 @@ func (a APIServer) HandlerPostAccount(c *gin.Context) {
 //	@Router			/accounts/{account_name} [delete]
 func (a APIServer) HandlerDeleteAccount(c *gin.Context) {
 	accountName := c.Param("account_name")
-	a.logger.Debug("Removing an Account", zap.String("account", accountName))
+	a.requestLogger(c).Debug("Removing an Account", zap.String("account", accountName))
 
 	if err := a.sql.DeleteAccount(accountName); err != nil {
-		a.logger.Error("Can't delete Cluster from DB", zap.String("account_name", accountName), zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't delete Cluster from DB", zap.String("account_name", accountName), zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 
-	c.PureJSON(http.StatusOK, nil)
+	writeJSON(c, http.StatusOK, nil)
 }
 
 // HandlerPatchAccount handles the request for patching an Account in the inventory
@@ -1085,15 +2737,15 @@ func (a APIServer) HandlerDeleteAccount(c *gin.Context) {
 //	@Tags			Accounts
 //	@Accept			json
 //	@Produce		json
-//	@Param			Account			body		inventory.Account	true	"Account to be modified"
-//	@Param			account_name	path		string				true	"Account Name"
-//	@Failure		501				{object}	nil					"Not Implemented"
+//	@Param			Account			body		inventory.Account		true	"Account to be modified"
+//	@Param			account_name	path		string					true	"Account Name"
+//	@Failure		501				{object}	GenericErrorResponse	"Not Implemented"
 //	@Router			/accounts/{account_name} [patch]
 func (a APIServer) HandlerPatchAccount(c *gin.Context) {
 	accountName := c.Param("account_name")
-	a.logger.Debug("Patching an Account", zap.String("account", accountName))
+	a.requestLogger(c).Debug("Patching an Account", zap.String("account", accountName))
 
-	c.PureJSON(http.StatusNotImplemented, nil)
+	respondError(c, http.StatusNotImplemented, "not implemented")
 }
 
 // ==================== Extra      Handlers ====================
@@ -1108,17 +2760,330 @@ func (a APIServer) HandlerPatchAccount(c *gin.Context) {
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	nil
-//	@Failure		500	{object}	nil
+//	@Failure		500	{object}	GenericErrorResponse
 //	@Router			/inventory/refresh [post]
 func (a APIServer) HandlerRefreshInventory(c *gin.Context) {
 	if err := a.sql.RefreshInventory(); err != nil {
-		a.logger.Error("Can't refresh inventory data on DB", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse(err.Error()))
+		a.requestLogger(c).Error("Can't refresh inventory data on DB", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
 	// This function doesn't return any 200OK code for preventing duplicated responses
 }
 
+// HandlerValidateInventory handles the request for validating an inventory without writing it
+//
+//	@Summary		Validate an inventory
+//	@Description	Checks the accounts, clusters and instances that a scanner would post, with the same rules applied when they are written, plus the references between them (clusters on unknown accounts, instances without cluster). Nothing is written
+//	@Tags			Inventory
+//	@Accept			json
+//	@Produce		json
+//	@Param			inventory	body		InventoryValidationRequest	true	"Inventory to be validated"
+//	@Success		200			{object}	InventoryValidationResponse
+//	@Failure		400			{object}	GenericErrorResponse
+//	@Failure		422			{object}	InventoryValidationResponse
+//	@Router			/validate [post]
+func (a APIServer) HandlerValidateInventory(c *gin.Context) {
+	var request InventoryValidationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.requestLogger(c).Error("Can't obtain data from body request", zap.Error(err))
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := NewInventoryValidationResponse(inventory.ValidateInventory(request.Accounts, request.Clusters, request.Instances))
+	a.requestLogger(c).Debug("Validated inventory",
+		zap.Int("accounts", len(request.Accounts)),
+		zap.Int("clusters", len(request.Clusters)),
+		zap.Int("instances", len(request.Instances)),
+		zap.Int("issues", len(response.Issues)))
+	if !response.Valid {
+		writeJSON(c, http.StatusUnprocessableEntity, response)
+		return
+	}
+	writeJSON(c, http.StatusOK, response)
+}
+
+// HandlerGetScanCoverage handles the request for checking which of the expected accounts were scanned
+//
+//	@Summary		Obtain the scan coverage report
+//	@Description	Compares the expected accounts (CIQ_EXPECTED_ACCOUNTS) against the accounts present in the inventory
+//	@Tags			Scan
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	ScanCoverageResponse
+//	@Failure		404	{object}	GenericErrorResponse
+//	@Failure		500	{object}	GenericErrorResponse
+//	@Router			/scan/coverage [get]
+func (a APIServer) HandlerGetScanCoverage(c *gin.Context) {
+	a.requestLogger(c).Debug("Retrieving scan coverage report")
+
+	if len(a.cfg.ExpectedAccounts) == 0 {
+		respondError(c, http.StatusNotFound, "no expected accounts configured (CIQ_EXPECTED_ACCOUNTS)")
+		return
+	}
+
+	accounts, err := a.sql.GetAccounts()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Accounts list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewScanCoverageResponse(a.cfg.ExpectedAccounts, accounts))
+}
+
+// HandlerGetRegionStats handles the request for obtaining the instances and costs per region
+//
+//	@Summary		Obtain per region stats
+//	@Description	Returns the instance count, running instance count and total cost of every region, sorted by cost descending. The region of an instance is derived from its availability zone, and the instances without it are grouped under the empty region
+//	@Tags			Stats
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider			query		string	false	"Returns only the instances of this provider (case insensitive). Unknown providers return an empty list"
+//	@Param			account				query		string	false	"Returns only the instances of this Account (name or alias)"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	RegionStatsResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/stats/regions [get]
+func (a APIServer) HandlerGetRegionStats(c *gin.Context) {
+	accountName := c.Query(accountParam)
+	provider := c.Query(providerParam)
+	a.requestLogger(c).Debug("Retrieving region stats", zap.String("account_name", accountName), zap.String("provider", provider))
+
+	if accountName != "" {
+		accounts, err := a.sql.GetAccountByName(accountName)
+		if err != nil {
+			a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+			a.writeAccountLookupError(c, accountName, err)
+			return
+		}
+		accountName = accounts[0].Name
+	}
+
+	instances, err := a.sql.GetInstancesWithoutTags()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
+	}
+
+	clusters, err := a.sql.GetClusters()
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve Clusters list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewRegionStatsResponse(regionStats(instances, clusters, provider, accountName)))
+}
+
+// HandlerExportDOT handles the request for exporting the inventory hierarchy as a GraphViz DOT graph
+//
+//	@Summary		Export the inventory as a GraphViz DOT graph
+//	@Description	Returns the account->cluster->instance hierarchy in DOT format, ready to be rendered with `dot`. Nodes are colored by status
+//	@Tags			Export
+//	@Produce		plain
+//	@Param			account				query		string	false	"Scopes the graph to a single account"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{string}	string
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/export/dot [get]
+func (a APIServer) HandlerExportDOT(c *gin.Context) {
+	accountName := c.Query(accountParam)
+	a.requestLogger(c).Debug("Exporting inventory as DOT graph", zap.String("account_name", accountName))
+
+	if accountName != "" {
+		if _, err := a.sql.GetAccountByName(accountName); err != nil {
+			a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+			a.writeAccountLookupError(c, accountName, err)
+			return
+		}
+	}
+
+	accounts, clusters, instances, err := a.getInventoryHierarchy(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve inventory for the DOT export", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	instances, ok := a.removeExcludedResources(c, instances, clusters, accounts)
+	if !ok {
+		return
+	}
+
+	c.Data(http.StatusOK, MIMEGraphviz, []byte(renderInventoryDOT(accounts, clusters, instances)))
+}
+
+// HandlerExportCost handles the request for exporting the costs of every account rolled up by cluster and instance
+//
+//	@Summary		Export the inventory costs
+//	@Description	Returns every account with its clusters and instances costs. The CSV format has one row per instance with its account and cluster cost columns, ready for spreadsheet import
+//	@Tags			Export
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			account				query		string	false	"Scopes the export to a single account"
+//	@Param			format				query		string	false	"Export format (default json)"	Enums(json, csv, yaml)
+//	@Param			tag_columns			query		string	false	"Comma separated list of tag keys exported for every instance. On CSV, each one becomes a column"
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	CostExportResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		404					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/export/cost [get]
+func (a APIServer) HandlerExportCost(c *gin.Context) {
+	accountName := c.Query(accountParam)
+	format := c.DefaultQuery(formatParam, exportFormatJSON)
+	a.requestLogger(c).Debug("Exporting inventory costs", zap.String("account_name", accountName), zap.String("format", format))
+
+	if format != exportFormatJSON && format != exportFormatCSV && format != exportFormatYAML {
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("invalid '%s' value (%s). Expected %s, %s or %s", formatParam, format, exportFormatJSON, exportFormatCSV, exportFormatYAML))
+		return
+	}
+
+	if accountName != "" {
+		if _, err := a.sql.GetAccountByName(accountName); err != nil {
+			a.requestLogger(c).Error("Account not found", zap.String("account_name", accountName), zap.Error(err))
+			a.writeAccountLookupError(c, accountName, err)
+			return
+		}
+	}
+
+	accounts, clusters, instances, err := a.getInventoryHierarchy(accountName)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve inventory for the cost export", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	instances, ok := a.removeExcludedResources(c, instances, clusters, accounts)
+	if !ok {
+		return
+	}
+
+	export := NewCostExportResponse(accounts, clusters, instances)
+	if tagColumns := parseListParam(c, tagColumnsParam); len(tagColumns) > 0 {
+		tags, err := a.sql.GetTagsByKeys(tagColumns)
+		if err != nil {
+			a.requestLogger(c).Error("Can't retrieve instance tags for the cost export", zap.Error(err))
+			a.writeInventoryError(c, err)
+			return
+		}
+		export.SetTagColumns(tagColumns, tags)
+	}
+
+	// YAML is converted from the JSON document by the YAML middleware
+	if format != exportFormatCSV {
+		writeJSON(c, http.StatusOK, export)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := writeCostCSV(&buf, export, a.cfg.HiddenFields); err != nil {
+		a.requestLogger(c).Error("Can't render the cost export as CSV", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="cost-export.csv"`)
+	c.Data(http.StatusOK, middleware.MIMECSV, buf.Bytes())
+}
+
+// getInventoryHierarchy retrieves the accounts, clusters and instances of the
+// inventory. If accountName is not empty, only that account and its resources
+// are retrieved.
+func (a APIServer) getInventoryHierarchy(accountName string) ([]inventory.Account, []inventory.Cluster, []inventory.Instance, error) {
+	var accounts []inventory.Account
+	var clusters []inventory.Cluster
+	var instances []inventory.Instance
+	var err error
+
+	if accountName != "" {
+		if accounts, err = a.sql.GetAccountByName(accountName); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get account: %w", err)
+		}
+		accountName = accounts[0].Name
+		if clusters, err = a.sql.GetClustersOnAccount(accountName); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get clusters on account: %w", err)
+		}
+		if instances, err = a.sql.GetInstancesOnAccount(accountName); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get instances on account: %w", err)
+		}
+		return accounts, clusters, instances, nil
+	}
+
+	if accounts, err = a.sql.GetAccounts(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	if clusters, err = a.sql.GetClusters(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get clusters: %w", err)
+	}
+	if instances, err = a.sql.GetInstancesWithoutTags(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get instances: %w", err)
+	}
+	return accounts, clusters, instances, nil
+}
+
+// HandlerGetDebugStats handles the request for obtaining the internal request counters
+//
+//	@Summary		Obtain the internal request counters
+//	@Description	Returns the total number of requests, per route counts and error counts since the API started
+//	@Tags			Debug
+//	@Produce		json
+//	@Success		200	{object}	RequestStatsResponse
+//	@Router			/debug/stats [get]
+func (a APIServer) HandlerGetDebugStats(c *gin.Context) {
+	writeJSON(c, http.StatusOK, a.stats.snapshot())
+}
+
+// HandlerGetRequestAudit handles the request for obtaining the most recent entries of the requests audit trail
+//
+//	@Summary		Obtain the requests audit trail
+//	@Description	Returns who (auth token ID), what and when of the most recent mutating requests, newest first. Requires the CIQ_AUDIT_TOKEN bearer token
+//	@Tags			Audit
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		integer	false	"Maximum number of entries (default 100, max 1000)"
+//	@Success		200		{object}	RequestAuditResponse
+//	@Failure		400		{object}	GenericErrorResponse
+//	@Failure		401		{object}	GenericErrorResponse
+//	@Failure		404		{object}	GenericErrorResponse
+//	@Failure		500		{object}	GenericErrorResponse
+//	@Router			/audit [get]
+func (a APIServer) HandlerGetRequestAudit(c *gin.Context) {
+	if a.cfg.AuditToken == "" {
+		respondError(c, http.StatusNotFound, "audit trail reader not configured (CIQ_AUDIT_TOKEN)")
+		return
+	}
+
+	limit, err := parseCountParam(c, limitParam)
+	if err != nil || (limit != nil && (*limit == 0 || *limit > maxAuditLimit)) {
+		respondError(c, http.StatusBadRequest, fmt.Sprintf("invalid '%s' value (%s). Expected an integer between 1 and %d", limitParam, c.Query(limitParam), maxAuditLimit))
+		return
+	}
+	if limit == nil {
+		limit = new(int)
+		*limit = defaultAuditLimit
+	}
+	a.requestLogger(c).Debug("Retrieving requests audit trail", zap.Int("limit", *limit))
+
+	entries, err := a.sql.GetRequestAuditEntries(*limit)
+	if err != nil {
+		a.requestLogger(c).Error("Can't retrieve requests audit trail", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, NewRequestAuditResponse(entries))
+}
+
 // HandlerGetSystemEvents handles the request for obtain the list of system events
 //
 //	@Summary		Obtain system events
@@ -1127,20 +3092,23 @@ func (a APIServer) HandlerRefreshInventory(c *gin.Context) {
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	EventsListResponse
-//	@Failure		500	{object}	nil
+//	@Failure		500	{object}	GenericErrorResponse
 //	@Router			/events [get]
 func (a APIServer) HandlerGetSystemEvents(c *gin.Context) {
-	a.logger.Debug("Retrieving system-wide events")
+	a.requestLogger(c).Debug("Retrieving system-wide events")
 
 	dbEvents, err := a.sql.GetSystemEvents()
 	if err != nil {
-		a.logger.Error("Failed to retrieve system-wide events", zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse("failed to retrieve system-wide events"))
+		a.requestLogger(c).Error("Failed to retrieve system-wide events", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "failed to retrieve system-wide events")
 		return
 	}
 
 	appEvents := events.ToSystemAuditEvents(dbEvents)
-	c.PureJSON(http.StatusOK, NewSystemEventsListResponse(appEvents))
+	appEvents, truncated := truncateResults(c, appEvents, a.cfg.MaxResults)
+	response := NewSystemEventsListResponse(appEvents)
+	response.Truncated = truncated
+	writeJSON(c, http.StatusOK, response)
 }
 
 // HandlerGetClusterEvents handles the request for obtain the list of events of a Cluster
@@ -1152,77 +3120,202 @@ func (a APIServer) HandlerGetSystemEvents(c *gin.Context) {
 //	@Produce		json
 //	@Param			cluster_id	path		string	true	"Cluster ID"
 //	@Success		200			{object}	EventsListResponse
-//	@Failure		500			{object}	nil
+//	@Failure		500			{object}	GenericErrorResponse
 //	@Router			/clusters/{cluster_id}/events [get]
 func (a APIServer) HandlerGetClusterEvents(c *gin.Context) {
 	clusterID := c.Param("cluster_id")
-	a.logger.Debug("Retrieving cluster events", zap.String("cluster_id", clusterID))
+	a.requestLogger(c).Debug("Retrieving cluster events", zap.String("cluster_id", clusterID))
 
 	dbEvents, err := a.sql.GetClusterEvents(clusterID)
 	if err != nil {
-		a.logger.Error("Failed to retrieve cluster events",
+		a.requestLogger(c).Error("Failed to retrieve cluster events",
 			zap.String("cluster_id", clusterID),
 			zap.Error(err))
-		c.PureJSON(http.StatusInternalServerError,
-			NewGenericErrorResponse("failed to retrieve cluster events"))
+		respondError(c, http.StatusInternalServerError, "failed to retrieve cluster events")
 		return
 	}
 	appEvents := events.ToAuditEvents(dbEvents)
-	c.PureJSON(http.StatusOK, NewEventsListResponse(appEvents))
+	appEvents, truncated := truncateResults(c, appEvents, a.cfg.MaxResults)
+	response := NewEventsListResponse(appEvents)
+	response.Truncated = truncated
+	writeJSON(c, http.StatusOK, response)
 }
 
 // HandlerGetInventoryOverview handles the request to obtain an overview of the inventory
 //
 //	@Summary		Obtain an inventory overview
-//	@Description	Returns an overview of the inventory
+//	@Description	Returns an overview of the inventory: accounts, clusters and instances counts, instances by status and provider, and active clusters per account. Every number is read from the same snapshot of the inventory
 //	@Tags			Overview
 //	@Accept			json
 //	@Produce		json
-//	@Success		200			{object}	models.OverviewSummary
-//	@Failure		500			{object}	GenericErrorResponse
-//	@Router			/overview	[get]
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	models.OverviewSummary
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/overview			[get]
 func (a APIServer) HandlerGetInventoryOverview(c *gin.Context) {
-	a.logger.Debug("Retrieving overview data")
+	a.requestLogger(c).Debug("Retrieving overview data")
+
+	excludeTag, ok := a.requestExcludeTag(c)
+	if !ok {
+		return
+	}
 
-	overview, err := a.getInventoryOverview()
+	overview, err := a.getInventoryOverview(excludeTag)
 	if err != nil {
-		c.PureJSON(http.StatusInternalServerError, NewGenericErrorResponse("failed to retrieve inventory overview"))
+		a.requestLogger(c).Error("Failed to retrieve inventory overview", zap.Error(err))
+		a.writeInventoryError(c, err)
 		return
 	}
-	c.PureJSON(http.StatusOK, overview)
+	writeJSON(c, http.StatusOK, overview)
 }
 
-// getInventoryOverview retrieves all components of the inventory overview.
-func (a APIServer) getInventoryOverview() (models.OverviewSummary, error) {
-	var overview models.OverviewSummary
+// HandlerStreamInventoryOverview handles the request for streaming the inventory overview changes
+//
+//	@Summary		Stream the inventory overview
+//	@Description	Server-Sent Events stream of the inventory overview. An 'overview' event, identified by the hash of the overview, is sent on connection and then every time the overview changes (checked every CIQ_STREAM_INTERVAL). The instances tagged with CIQ_EXCLUDE_TAG are never counted, as every client receives the same events. A keep-alive comment is sent every CIQ_STREAM_KEEPALIVE. The number of clients is capped by CIQ_STREAM_MAX_CONNECTIONS
+//	@Tags			Overview
+//	@Produce		text/event-stream
+//	@Success		200	{object}	models.OverviewSummary
+//	@Failure		500	{object}	GenericErrorResponse
+//	@Failure		503	{object}	GenericErrorResponse
+//	@Router			/stream [get]
+func (a APIServer) HandlerStreamInventoryOverview(c *gin.Context) {
+	events, ok := a.overviewStream.subscribe()
+	if !ok {
+		a.requestLogger(c).Warn("Too many stream clients", zap.Int("max_clients", a.cfg.StreamMaxConnections))
+		respondError(c, http.StatusServiceUnavailable, "too many stream clients")
+		return
+	}
+	defer a.overviewStream.unsubscribe(events)
 
-	// Get clusters summary
-	clusters, err := a.sql.GetClustersOverview()
+	overview, err := a.getInventoryOverview(a.cfg.ExcludeTag)
 	if err != nil {
-		return models.OverviewSummary{}, fmt.Errorf("failed to get clusters overview: %w", err)
+		a.requestLogger(c).Error("Failed to retrieve inventory overview", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+	event, err := newOverviewEvent(overview, a.cfg.HiddenFields)
+	if err != nil {
+		a.requestLogger(c).Error("Can't render the inventory overview", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	a.requestLogger(c).Debug("Stream client connected")
+	c.Header("Content-Type", middleware.MIMEEventStream)
+	c.Header("Cache-Control", "no-cache")
+	// Disables the response buffering of the nginx based proxies
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	keepAlive := time.NewTicker(a.cfg.StreamKeepAlive)
+	defer keepAlive.Stop()
+
+	lastID := ""
+	for {
+		if event.id != lastID {
+			if err := writeOverviewEvent(c.Writer, event); err != nil {
+				a.requestLogger(c).Debug("Stream client disconnected", zap.Error(err))
+				return
+			}
+			lastID = event.id
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			a.requestLogger(c).Debug("Stream client disconnected")
+			return
+		case <-a.overviewStream.done:
+			return
+		case event = <-events:
+		case <-keepAlive.C:
+			if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+				a.requestLogger(c).Debug("Stream client disconnected", zap.Error(err))
+				return
+			}
+			c.Writer.Flush()
+		}
 	}
-	overview.Clusters = clusters
+}
+
+// HandlerGetProviders handles the request to obtain the cloud providers found on the inventory
+//
+//	@Summary		Obtain the cloud providers on the inventory
+//	@Description	Returns the cloud providers with at least one account, cluster or instance on the inventory, sorted by name, with their accounts, active clusters and active instances counts
+//	@Tags			Overview
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	ProviderListResponse
+//	@Failure		500	{object}	GenericErrorResponse
+//	@Router			/providers [get]
+func (a APIServer) HandlerGetProviders(c *gin.Context) {
+	a.requestLogger(c).Debug("Retrieving cloud providers")
 
-	// Get instances summary
-	instances, err := a.sql.GetInstancesOverview()
+	providers, err := a.sql.GetProviders()
 	if err != nil {
-		return models.OverviewSummary{}, fmt.Errorf("failed to get instances overview: %w", err)
+		a.requestLogger(c).Error("Can't retrieve Providers list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
 	}
-	overview.Instances = instances
+	writeJSON(c, http.StatusOK, NewProviderListResponse(providers))
+}
 
-	// Get providers summary
-	providers, err := a.sql.GetProvidersOverview()
+// HandlerGetRegions handles the request to obtain the instances and costs of every region on the inventory
+//
+//	@Summary		Obtain the regions on the inventory
+//	@Description	Returns the instance count, running instance count and total cost of every region and provider, sorted by region. The region of an instance is derived from its availability zone, and the instances without it are grouped under the 'unknown' region
+//	@Tags			Overview
+//	@Accept			json
+//	@Produce		json
+//	@Param			include_excluded	query		bool	false	"Includes the instances tagged with CIQ_EXCLUDE_TAG"
+//	@Success		200					{object}	RegionListResponse
+//	@Failure		400					{object}	GenericErrorResponse
+//	@Failure		500					{object}	GenericErrorResponse
+//	@Router			/regions [get]
+func (a APIServer) HandlerGetRegions(c *gin.Context) {
+	a.requestLogger(c).Debug("Retrieving regions")
+
+	instances, err := a.instances.get()
 	if err != nil {
-		return models.OverviewSummary{}, fmt.Errorf("failed to get providers overview: %w", err)
+		a.requestLogger(c).Error("Can't retrieve Instances list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
+	}
+
+	instances, ok := a.removeExcludedInstances(c, instances)
+	if !ok {
+		return
 	}
-	overview.Providers = providers
 
-	// Get scanner last scan timestamp
-	scannerLastScan, err := a.sql.GetScannerLastScanTimestamp()
+	writeJSON(c, http.StatusOK, NewRegionListResponse(regionSummaries(instances, unknownRegion, false, compareRegionNames)))
+}
+
+// HandlerGetTagKeys handles the request to obtain the tag keys found on the inventory
+//
+//	@Summary		Obtain the tag keys on the inventory
+//	@Description	Returns the distinct keys of the instance tags, sorted by name. Useful for building the 'tag' filter of the Instances list
+//	@Tags			Instances
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	TagKeyListResponse
+//	@Failure		500	{object}	GenericErrorResponse
+//	@Router			/tags [get]
+func (a APIServer) HandlerGetTagKeys(c *gin.Context) {
+	a.requestLogger(c).Debug("Retrieving tag keys")
+
+	keys, err := a.sql.GetTagKeys()
 	if err != nil {
-		return models.OverviewSummary{}, fmt.Errorf("failed to get scanner last scan timestamp: %w", err)
+		a.requestLogger(c).Error("Can't retrieve Tag keys list", zap.Error(err))
+		a.writeInventoryError(c, err)
+		return
 	}
-	overview.Scanner = models.Scanner{LastScanTimestamp: scannerLastScan}
+	writeJSON(c, http.StatusOK, NewTagKeyListResponse(keys))
+}
 
-	return overview, nil
+// getInventoryOverview retrieves all components of the inventory overview
+// from a single snapshot of the DB, so the numbers are consistent. The
+// instances with the excludeTag tag key are not counted, unless it's empty.
+func (a APIServer) getInventoryOverview(excludeTag string) (models.OverviewSummary, error) {
+	return a.sql.GetInventoryOverview(excludeTag)
 }
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/config"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	sqlclient "github.com/RHEcosystemAppEng/cluster-iq/internal/sql_client"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// fakeRows are the rows returned by fakeDB for a query
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	args    *[]driver.Value // Receives the arguments of the last query, if set
+}
+
+// fakeDB is a database/sql driver answering every query with its canned
+// rows, so the handlers can be tested without PostgreSQL. Unknown queries fail
+type fakeDB map[string]fakeRows
+
+func (db fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db: db}, nil }
+func (db fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	rows, ok := c.db[query]
+	if !ok {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	return fakeStmt{rows: rows}, nil
+}
+func (c fakeConn) Close() error              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions not supported") }
+
+type fakeStmt struct{ rows fakeRows }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec not supported")
+}
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.rows.args != nil {
+		*s.rows.args = args
+	}
+	return &fakeCursor{rows: s.rows}, nil
+}
+
+type fakeCursor struct {
+	rows fakeRows
+	next int
+}
+
+func (r *fakeCursor) Columns() []string { return r.rows.columns }
+func (r *fakeCursor) Close() error      { return nil }
+func (r *fakeCursor) Next(dest []driver.Value) error {
+	if r.next == len(r.rows.values) {
+		return io.EOF
+	}
+	copy(dest, r.rows.values[r.next])
+	r.next++
+	return nil
+}
+
+// TestExcludedInstancesCost verifies the cost and count of the instances tagged with CIQ_EXCLUDE_TAG leave the clusters and accounts, also when filtering by instance count
+func TestExcludedInstancesCost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	accounts := fakeRows{
+		columns: []string{"name", "provider", "cluster_count", "total_cost"},
+		values:  [][]driver.Value{{"acc", "AWS", int64(1), 30.0}},
+	}
+	clusters := fakeRows{
+		columns: []string{"id", "name", "account_name", "status", "instance_count", "total_cost"},
+		values:  [][]driver.Value{{"c1", "prod", "acc", "Running", int64(2), 30.0}},
+	}
+	instances := fakeRows{
+		columns: []string{"id", "name", "cluster_id", "status", "total_cost"},
+		values: [][]driver.Value{
+			{"i-1", "master", "c1", "Running", 10.0},
+			{"i-2", "noise", "c1", "Running", 20.0},
+		},
+	}
+	db := sql.OpenDB(fakeDB{
+		sqlclient.SelectAccountsQuery:          accounts,
+		sqlclient.SelectAccountsByNameQuery:    accounts,
+		sqlclient.SelectClustersQuery:          clusters,
+		sqlclient.SelectClustersByIDuery:       clusters,
+		sqlclient.SelectClustersOnAccountQuery: clusters,
+		sqlclient.SelectClustersStatusHistoryQuery: {
+			columns: []string{"resource_id", "status"},
+		},
+		sqlclient.SelectInstancesWithoutTagsQuery: instances,
+		sqlclient.SelectInstancesOnClusterQuery:   instances,
+		sqlclient.SelectInstancesOnAccountQuery:   instances,
+		sqlclient.SelectInstanceIDsByTagKeyQuery: {
+			columns: []string{"instance_id"},
+			values:  [][]driver.Value{{"i-2"}},
+		},
+	})
+	defer db.Close()
+
+	api := APIServer{
+		cfg:                &config.APIServerConfig{ExcludeTag: "ciq:ignore"},
+		logger:             zap.NewNop(),
+		sql:                sqlclient.NewSQLClientFromDB(db, zap.NewNop()),
+		emptyInventoryOnce: &sync.Once{},
+	}
+	engine := gin.New()
+	engine.GET("/clusters", api.HandlerGetClusters)
+	engine.GET("/clusters/:cluster_id", api.HandlerGetClustersByID)
+	engine.GET("/accounts", api.HandlerGetAccounts)
+	engine.GET("/accounts/:account_name", api.HandlerGetAccountsByName)
+
+	tests := []struct {
+		name          string
+		query         string
+		wantCost      float64
+		wantInstances int
+	}{
+		{name: "Excluded", wantCost: 10, wantInstances: 1},
+		{name: "Included", query: "include_excluded=true", wantCost: 30, wantInstances: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := func(path string, response any) {
+				rec := httptest.NewRecorder()
+				if tt.query != "" && strings.Contains(path, "?") {
+					path += "&" + tt.query
+				} else if tt.query != "" {
+					path += "?" + tt.query
+				}
+				engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+				if rec.Code != http.StatusOK {
+					t.Fatalf("expected status 200 on %s, got %d: %s", path, rec.Code, rec.Body.Bytes())
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+					t.Fatalf("can't decode %s body: %v", path, err)
+				}
+			}
+
+			var clusters ClusterListResponse
+			get("/clusters", &clusters)
+			if len(clusters.Clusters) != 1 {
+				t.Fatalf("expected 1 cluster, got %d", len(clusters.Clusters))
+			}
+			cluster := clusters.Clusters[0]
+			if cluster.TotalCost != tt.wantCost || clusters.TotalCost != tt.wantCost {
+				t.Errorf("expected cluster cost %v, got %v (list total %v)", tt.wantCost, cluster.TotalCost, clusters.TotalCost)
+			}
+			if cost := cluster.CostByStatus["Running"]; cost != tt.wantCost {
+				t.Errorf("expected cluster Running cost %v, got %v", tt.wantCost, cost)
+			}
+			if cluster.InstanceCount != tt.wantInstances || cluster.TotalInstances != tt.wantInstances {
+				t.Errorf("expected %d cluster instances, got %d (total %d)", tt.wantInstances, cluster.InstanceCount, cluster.TotalInstances)
+			}
+
+			var accounts AccountListResponse
+			get("/accounts", &accounts)
+			if len(accounts.Accounts) != 1 {
+				t.Fatalf("expected 1 account, got %d", len(accounts.Accounts))
+			}
+			account := accounts.Accounts[0]
+			if account.TotalCost != tt.wantCost || accounts.TotalCost != tt.wantCost {
+				t.Errorf("expected account cost %v, got %v (list total %v)", tt.wantCost, account.TotalCost, accounts.TotalCost)
+			}
+			if cost := account.CostByStatus["Running"]; cost != tt.wantCost {
+				t.Errorf("expected account Running cost %v, got %v", tt.wantCost, cost)
+			}
+
+			for _, path := range []string{"/clusters/c1", "/clusters/pr?match=prefix"} {
+				var byID ClusterListResponse
+				get(path, &byID)
+				if len(byID.Clusters) != 1 || byID.Clusters[0].TotalCost != tt.wantCost || byID.Clusters[0].InstanceCount != tt.wantInstances {
+					t.Errorf("expected cluster cost %v and %d instances on %s, got %+v", tt.wantCost, tt.wantInstances, path, byID.Clusters)
+				}
+			}
+
+			var byName AccountListResponse
+			get("/accounts/acc", &byName)
+			if len(byName.Accounts) != 1 || byName.Accounts[0].TotalCost != tt.wantCost {
+				t.Errorf("expected account cost %v on /accounts/acc, got %+v", tt.wantCost, byName.Accounts)
+			}
+
+			// The instance count filters see the count without the excluded instances
+			minInstances := "min_instances=" + strconv.Itoa(tt.wantInstances+1)
+			var filtered ClusterListResponse
+			get("/clusters?"+minInstances, &filtered)
+			if len(filtered.Clusters) != 0 {
+				t.Errorf("expected no cluster with %s, got %+v", minInstances, filtered.Clusters)
+			}
+			for query, want := range map[string]int{minInstances: 0, "min_instances=" + strconv.Itoa(tt.wantInstances): 1} {
+				var count CountResponse
+				get("/clusters?count=true&"+query, &count)
+				if count.Count != want {
+					t.Errorf("expected %d clusters counted with %s, got %d", want, query, count.Count)
+				}
+			}
+		})
+	}
+}
+
+// TestUnrecognizedProvider verifies /stats/regions answers an unrecognized provider with an empty list, as the /clusters provider filter does
+func TestUnrecognizedProvider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := sql.OpenDB(fakeDB{
+		sqlclient.SelectClustersQuery: {
+			columns: []string{"id", "name", "account_name", "provider", "status"},
+			values:  [][]driver.Value{{"c1", "prod", "acc", "AWS", "Running"}},
+		},
+		sqlclient.SelectClustersStatusHistoryQuery: {
+			columns: []string{"resource_id", "status"},
+		},
+		sqlclient.SelectInstancesWithoutTagsQuery: {
+			columns: []string{"id", "cluster_id", "provider", "availability_zone", "status", "total_cost"},
+			values:  [][]driver.Value{{"i-1", "c1", "AWS", "us-east-1a", "Running", 10.0}},
+		},
+	})
+	defer db.Close()
+
+	api := APIServer{
+		cfg:                &config.APIServerConfig{},
+		logger:             zap.NewNop(),
+		sql:                sqlclient.NewSQLClientFromDB(db, zap.NewNop()),
+		emptyInventoryOnce: &sync.Once{},
+	}
+	engine := gin.New()
+	engine.GET("/clusters", api.HandlerGetClusters)
+	engine.GET("/stats/regions", api.HandlerGetRegionStats)
+
+	tests := []struct {
+		name     string
+		provider string
+		want     int
+	}{
+		{name: "Known", provider: "aws", want: 1},
+		{name: "Unrecognized", provider: "DigitalOcean", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			get := func(path string, response any) {
+				rec := httptest.NewRecorder()
+				engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?provider="+tt.provider, nil))
+				if rec.Code != http.StatusOK {
+					t.Fatalf("expected status 200 on %s, got %d: %s", path, rec.Code, rec.Body.Bytes())
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+					t.Fatalf("can't decode %s body: %v", path, err)
+				}
+			}
+
+			var clusters ClusterListResponse
+			get("/clusters", &clusters)
+			if len(clusters.Clusters) != tt.want {
+				t.Errorf("expected %d clusters, got %+v", tt.want, clusters.Clusters)
+			}
+
+			var regions RegionStatsResponse
+			get("/stats/regions", &regions)
+			if len(regions.Regions) != tt.want {
+				t.Errorf("expected %d regions, got %+v", tt.want, regions.Regions)
+			}
+		})
+	}
+}
+
+// TestInstancesStatusHistoryScope verifies the status history is only retrieved for the instances on the page, unless they are sorted by cost per hour
+func TestInstancesStatusHistoryScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	lastScan := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var args []driver.Value
+	db := sql.OpenDB(fakeDB{
+		sqlclient.SelectInstancesStatusHistoryQuery: {
+			columns: []string{"resource_id", "status", "timestamp"},
+			values: [][]driver.Value{
+				{"i-1", "Running", lastScan.Add(-10 * time.Hour)},
+				{"i-2", "Running", lastScan.Add(-10 * time.Hour)},
+			},
+			args: &args,
+		},
+	})
+	defer db.Close()
+
+	instances := []inventory.Instance{
+		{ID: "i-1", Status: inventory.Running, TotalCost: 10, LastScanTimestamp: lastScan},
+		{ID: "i-2", Status: inventory.Running, TotalCost: 30, LastScanTimestamp: lastScan},
+	}
+	api := APIServer{
+		cfg:                &config.APIServerConfig{},
+		logger:             zap.NewNop(),
+		sql:                sqlclient.NewSQLClientFromDB(db, zap.NewNop()),
+		instances:          newInstancesCache(0, func() ([]inventory.Instance, error) { return instances, nil }),
+		emptyInventoryOnce: &sync.Once{},
+	}
+	engine := gin.New()
+	engine.GET("/instances", api.HandlerGetInstances)
+
+	tests := []struct {
+		name            string
+		query           string
+		wantArgs        string
+		wantID          string
+		wantCostPerHour float64
+	}{
+		{name: "Page", query: "?limit=1", wantArgs: `{"i-1"}`, wantID: "i-1", wantCostPerHour: 1},
+		{name: "Sorted by cost per hour", query: "?limit=1&sort=costPerHour&order=desc", wantArgs: `{"i-1","i-2"}`, wantID: "i-2", wantCostPerHour: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args = nil
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/instances"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.Bytes())
+			}
+
+			if len(args) != 1 || args[0] != tt.wantArgs {
+				t.Errorf("expected the status history of %s, got %v", tt.wantArgs, args)
+			}
+			var response InstanceListResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("can't decode body: %v", err)
+			}
+			if len(response.Instances) != 1 || response.Instances[0].ID != tt.wantID || response.Instances[0].CostPerHour != tt.wantCostPerHour {
+				t.Errorf("expected %s with cost per hour %v, got %+v", tt.wantID, tt.wantCostPerHour, response.Instances)
+			}
+		})
+	}
+}
+
+// TestClustersStatusHistoryScope verifies the status history is only retrieved for the clusters matching the other filters, and not for the counts without uptime filter
+func TestClustersStatusHistoryScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	lastScan := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var args []driver.Value
+	db := sql.OpenDB(fakeDB{
+		sqlclient.SelectClustersQuery: {
+			columns: []string{"id", "name", "provider", "status", "last_scan_timestamp"},
+			values: [][]driver.Value{
+				{"c1", "prod", "AWS", "Running", lastScan},
+				{"c2", "prod", "GCP", "Stopped", lastScan},
+			},
+		},
+		sqlclient.SelectClustersStatusHistoryQuery: {
+			columns: []string{"resource_id", "status", "timestamp"},
+			values: [][]driver.Value{
+				{"c1", "Running", lastScan.Add(-10 * time.Hour)},
+				{"c2", "Stopped", lastScan.Add(-10 * time.Hour)},
+			},
+			args: &args,
+		},
+		sqlclient.SelectInstancesWithoutTagsQuery: {
+			columns: []string{"id"},
+		},
+	})
+	defer db.Close()
+
+	api := APIServer{
+		cfg:                &config.APIServerConfig{},
+		logger:             zap.NewNop(),
+		sql:                sqlclient.NewSQLClientFromDB(db, zap.NewNop()),
+		emptyInventoryOnce: &sync.Once{},
+	}
+	engine := gin.New()
+	engine.GET("/clusters", api.HandlerGetClusters)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantArgs []driver.Value
+	}{
+		{name: "Filtered", query: "?provider=aws", wantArgs: []driver.Value{`{"c1"}`}},
+		{name: "Count", query: "?count=true"},
+		{name: "Count by uptime", query: "?count=true&min_uptime=50", wantArgs: []driver.Value{`{"c1","c2"}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args = nil
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clusters"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.Bytes())
+			}
+			if !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("expected the status history of %v, got %v", tt.wantArgs, args)
+			}
+		})
+	}
+}
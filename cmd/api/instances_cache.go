@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// instancesCache keeps an in-memory copy of the full instances list (with
+// their tags), so the list endpoints don't query and join the whole table on
+// every request. The copy is refreshed once it's older than the TTL, and only
+// one refresh runs at a time: concurrent requests arriving during a refresh wait
+// for it and share its result
+type instancesCache struct {
+	ttl  time.Duration                        // Freshness window. Caching is disabled if zero
+	load func() ([]inventory.Instance, error) // Loads the instances from the DB
+
+	mu         sync.RWMutex         // Guards the cached copy
+	instances  []inventory.Instance // Cached copy
+	loadedAt   time.Time            // When the cached copy was loaded. Zero if there is no copy
+	generation uint64               // Increased on every invalidation
+
+	refreshMu sync.Mutex // Serializes the refreshes
+}
+
+// newInstancesCache creates an empty instancesCache
+//
+// Parameters:
+// - ttl: The freshness window of the cached copy. Caching is disabled if zero.
+// - load: The function loading the instances from the DB.
+func newInstancesCache(ttl time.Duration, load func() ([]inventory.Instance, error)) *instancesCache {
+	return &instancesCache{ttl: ttl, load: load}
+}
+
+// get returns the instances list, loading it from the DB if the cached copy
+// is missing or older than the TTL. The returned slice is a copy, so the
+// callers can sort or modify it.
+//
+// Returns:
+// - A slice of inventory.Instance objects.
+// - An error if the instances can't be loaded.
+func (c *instancesCache) get() ([]inventory.Instance, error) {
+	if c.ttl <= 0 {
+		return c.load()
+	}
+
+	if instances, ok := c.fresh(); ok {
+		return instances, nil
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	// Another request could have refreshed the copy while waiting
+	if instances, ok := c.fresh(); ok {
+		return instances, nil
+	}
+	return c.reload()
+}
+
+// refresh loads the instances from the DB into the cached copy, regardless of
+// its age. The requests arriving during the refresh wait for it.
+//
+// Returns:
+// - An error if the instances can't be loaded. The previous copy is kept.
+func (c *instancesCache) refresh() error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	_, err := c.reload()
+	return err
+}
+
+// reload loads the instances from the DB and replaces the cached copy with
+// them. Must be called holding refreshMu
+func (c *instancesCache) reload() ([]inventory.Instance, error) {
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	instances, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Discarding the copy if the inventory changed during the load
+	c.mu.Lock()
+	if c.generation == generation {
+		c.instances = instances
+		c.loadedAt = time.Now()
+	}
+	c.mu.Unlock()
+
+	return slices.Clone(instances), nil
+}
+
+// runRefresher refreshes the cached copy every interval until the context is
+// canceled, so the requests find a warm copy instead of loading it from the
+// DB. The interval should be shorter than the TTL, otherwise the copy expires
+// between refreshes. The refresh errors are logged, and the requests fall
+// back to loading the instances themselves once the copy expires.
+//
+// Parameters:
+// - ctx: Context stopping the refresher when canceled.
+// - interval: Time between the refreshes.
+// - logger: Logger for the refresh errors.
+func (c *instancesCache) runRefresher(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.refresh(); err != nil {
+			logger.Error("Can't refresh the cached Instances list", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fresh returns a copy of the cached instances if they are within the TTL
+func (c *instancesCache) fresh() ([]inventory.Instance, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.loadedAt.IsZero() || time.Since(c.loadedAt) > c.ttl {
+		return nil, false
+	}
+	return slices.Clone(c.instances), true
+}
+
+// invalidate drops the cached copy, so the next request loads it from the DB
+func (c *instancesCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.instances = nil
+	c.loadedAt = time.Time{}
+	c.generation++
+}
+
+// middleware returns a Gin middleware invalidating the cache after every
+// successful mutating request, so the writes are visible without waiting for
+// the TTL
+func (c *instancesCache) middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		if ctx.Writer.Status() < http.StatusBadRequest {
+			c.invalidate()
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// streamBufferSize is the size of the buffer between the streamed JSON and the response writer
+const streamBufferSize = 32 * 1024
+
+// streamList writes a list response encoding its items one by one straight
+// to the client, instead of rendering the whole document in memory next to
+// the items. The envelope is the response without items: it's rendered with
+// the items list under key empty, and the items are streamed in its place,
+// so every other field comes from the response struct tags. The body is the
+// same as the one rendered by writeJSON, including its HTML escaping.
+//
+// The response is rendered at once by writeJSON instead when a middleware
+// transforms the whole JSON document (see middleware.CanStreamJSON). As the
+// status is sent before the first item, encoding errors can only be logged.
+// The ETag of the body, if validated, is computed with a first encoding pass
+// (see middleware.StreamETag), and the items are only sent when it's not
+// matched by If-None-Match.
+func streamList[T any](c *gin.Context, code int, response any, envelope any, key string, items []T) error {
+	if !middleware.CanStreamJSON(c) {
+		writeJSON(c, code, response)
+		return nil
+	}
+
+	var item bytes.Buffer
+	encoder := json.NewEncoder(&item)
+	encoder.SetEscapeHTML(!middleware.IsPureJSON(c))
+
+	if err := encoder.Encode(envelope); err != nil {
+		return err
+	}
+	head, tail, found := bytes.Cut(bytes.TrimSuffix(item.Bytes(), []byte("\n")), []byte(`"`+key+`":[]`))
+	if !found {
+		writeJSON(c, code, response)
+		return fmt.Errorf("the response has no '%s' list to stream", key)
+	}
+	// The buffer is reused for the items, so the envelope is copied
+	head, tail = bytes.Clone(head), bytes.Clone(tail)
+
+	// Write errors are sticky on bufio.Writer, so they are returned by Flush
+	writeBody := func(out io.Writer) error {
+		w := bufio.NewWriterSize(out, streamBufferSize)
+		_, _ = w.Write(head)
+		_, _ = w.WriteString(`"` + key + `":[`)
+		for i, v := range items {
+			item.Reset()
+			if err := encoder.Encode(v); err != nil {
+				return err
+			}
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = w.Write(bytes.TrimSuffix(item.Bytes(), []byte("\n")))
+		}
+		_ = w.WriteByte(']')
+		_, _ = w.Write(tail)
+		return w.Flush()
+	}
+
+	notModified, err := middleware.StreamETag(c, writeBody)
+	if err != nil {
+		// Nothing is sent yet, so the client gets an error instead of a broken body
+		respondError(c, http.StatusInternalServerError, err.Error())
+		return err
+	}
+	if notModified {
+		return nil
+	}
+
+	middleware.Stream(c)
+	c.Header("Content-Type", middleware.MIMEJSONUTF8)
+	c.Status(code)
+	return writeBody(c.Writer)
+}
+
+// streamInstanceList streams an InstanceListResponse (see streamList)
+func streamInstanceList(c *gin.Context, code int, response *InstanceListResponse) error {
+	envelope := *response
+	envelope.Instances = []inventory.Instance{}
+	return streamList(c, code, response, &envelope, "instances", response.Instances)
+}
+
+// streamClusterList streams a ClusterListResponse (see streamList)
+func streamClusterList(c *gin.Context, code int, response *ClusterListResponse) error {
+	envelope := *response
+	envelope.Clusters = []inventory.Cluster{}
+	return streamList(c, code, response, &envelope, "clusters", response.Clusters)
+}
+
+// streamAccountList streams an AccountListResponse (see streamList)
+func streamAccountList(c *gin.Context, code int, response *AccountListResponse) error {
+	envelope := *response
+	envelope.Accounts = []inventory.Account{}
+	return streamList(c, code, response, &envelope, "accounts", response.Accounts)
+}
+
+// streamExpenseList streams an ExpenseListResponse (see streamList)
+func streamExpenseList(c *gin.Context, code int, response *ExpenseListResponse) error {
+	envelope := *response
+	envelope.Expenses = []inventory.Expense{}
+	return streamList(c, code, response, &envelope, "expenses", response.Expenses)
+}
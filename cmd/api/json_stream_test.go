@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// TestStreamInstanceList verifies the streamed instance list is the same document rendered by writeJSON
+func TestStreamInstanceList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	instances := []inventory.Instance{
+		{ID: "i-1", Name: "<master> & co", ClusterID: "c1", CreationTimestamp: createdAt, Tags: []inventory.Tag{{Key: "team", Value: "platform", InstanceID: "i-1"}}},
+		{ID: "i-2", Name: "worker", ClusterID: "c1", TotalCost: 12.5},
+	}
+
+	tests := []struct {
+		name      string
+		instances []inventory.Instance
+		total     int
+		truncated bool
+		pure      bool
+	}{
+		{name: "Empty"},
+		{name: "Single", instances: instances[:1], total: 1},
+		{name: "Many", instances: instances, total: 2},
+		{name: "Truncated", instances: instances, total: 10, truncated: true},
+		{name: "Pure", instances: instances, total: 2, pure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			render := func(write func(c *gin.Context, response *InstanceListResponse)) *httptest.ResponseRecorder {
+				engine := gin.New()
+				if tt.pure {
+					engine.Use(middleware.PureJSON())
+				}
+				engine.GET("/instances", func(c *gin.Context) {
+					response := NewInstanceListResponse(tt.instances)
+					response.Total = tt.total
+					response.Truncated = tt.truncated
+					write(c, response)
+				})
+				rec := httptest.NewRecorder()
+				engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/instances", nil))
+				return rec
+			}
+
+			want := render(func(c *gin.Context, response *InstanceListResponse) {
+				writeJSON(c, http.StatusOK, response)
+			})
+			got := render(func(c *gin.Context, response *InstanceListResponse) {
+				if err := streamInstanceList(c, http.StatusOK, response); err != nil {
+					t.Fatalf("can't stream the instances list: %v", err)
+				}
+			})
+
+			if got.Code != want.Code {
+				t.Errorf("expected status %d, got %d", want.Code, got.Code)
+			}
+			if got.Header().Get("Content-Type") != want.Header().Get("Content-Type") {
+				t.Errorf("expected Content-Type %q, got %q", want.Header().Get("Content-Type"), got.Header().Get("Content-Type"))
+			}
+			// PureJSON ends the document with a newline
+			if wantBody := bytes.TrimSuffix(want.Body.Bytes(), []byte("\n")); !bytes.Equal(got.Body.Bytes(), wantBody) {
+				t.Errorf("streamed body doesn't match the rendered one\nwant: %s\ngot:  %s", wantBody, got.Body.Bytes())
+			}
+		})
+	}
+}
+
+// assertStreamedJSON verifies the streamed response is the same document rendered by writeJSON
+func assertStreamedJSON(t *testing.T, response any, stream func(c *gin.Context) error, handlers ...gin.HandlerFunc) {
+	t.Helper()
+	render := func(write func(c *gin.Context)) *httptest.ResponseRecorder {
+		engine := gin.New()
+		engine.Use(handlers...)
+		engine.GET("/list", write)
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/list", nil))
+		return rec
+	}
+
+	want := render(func(c *gin.Context) { writeJSON(c, http.StatusOK, response) })
+	got := render(func(c *gin.Context) {
+		if err := stream(c); err != nil {
+			t.Fatalf("can't stream the list: %v", err)
+		}
+	})
+
+	if got.Code != want.Code {
+		t.Errorf("expected status %d, got %d", want.Code, got.Code)
+	}
+	if !bytes.Equal(bytes.TrimSuffix(got.Body.Bytes(), []byte("\n")), bytes.TrimSuffix(want.Body.Bytes(), []byte("\n"))) {
+		t.Errorf("streamed body doesn't match the rendered one\nwant: %s\ngot:  %s", want.Body.Bytes(), got.Body.Bytes())
+	}
+}
+
+// TestStreamLists verifies the streamed clusters, accounts and expenses lists are the documents rendered by writeJSON
+func TestStreamLists(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clusters := []inventory.Cluster{
+		{ID: "c1", Name: "<prod>", AccountName: "acc", TotalCost: 10, CostByStatus: map[inventory.InstanceStatus]float64{inventory.Running: 10}},
+		{ID: "c2", Name: "dev", AccountName: "acc"},
+	}
+	accounts := []inventory.Account{{Name: "acc", Provider: inventory.AWSProvider, TotalCost: 10}}
+	expenses := []inventory.Expense{{InstanceID: "i-1", Amount: 1.5}}
+
+	for _, size := range []int{0, 1, 2} {
+		t.Run(fmt.Sprintf("Clusters%d", size), func(t *testing.T) {
+			response := NewClusterListResponse(clusters[:size])
+			response.Total = 5
+			response.Truncated = size == 2
+			assertStreamedJSON(t, response, func(c *gin.Context) error { return streamClusterList(c, http.StatusOK, response) })
+		})
+	}
+	for _, size := range []int{0, 1} {
+		t.Run(fmt.Sprintf("Accounts%d", size), func(t *testing.T) {
+			response := NewAccountListResponse(accounts[:size])
+			response.Truncated = size == 1
+			assertStreamedJSON(t, response, func(c *gin.Context) error { return streamAccountList(c, http.StatusOK, response) })
+		})
+		t.Run(fmt.Sprintf("Expenses%d", size), func(t *testing.T) {
+			response := NewExpenseListResponse(expenses[:size])
+			response.Truncated = size == 1
+			assertStreamedJSON(t, response, func(c *gin.Context) error { return streamExpenseList(c, http.StatusOK, response) })
+		})
+	}
+}
+
+// TestStreamListBuffered verifies the lists transformed by the middlewares are rendered at once
+func TestStreamListBuffered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	response := NewClusterListResponse([]inventory.Cluster{{ID: "c1", Name: "prod", TotalCost: 10}})
+	streamed := false
+	assertStreamedJSON(t, response, func(c *gin.Context) error {
+		err := streamClusterList(c, http.StatusOK, response)
+		streamed = middleware.IsStreamed(c)
+		return err
+	}, middleware.HiddenFields([]string{"totalCost"}))
+
+	if streamed {
+		t.Errorf("expected the list with hidden fields not to be streamed")
+	}
+}
+
+// TestStreamListETag verifies GET /clusters keeps its ETag when streamed, and a matching If-None-Match is answered with 304
+func TestStreamListETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clusters := []inventory.Cluster{{ID: "c1", Name: "prod", AccountName: "acc", TotalCost: 10}}
+
+	engine := gin.New()
+	engine.Use(middleware.Gzip(1024))
+	engine.GET("/clusters", middleware.ETag(), func(c *gin.Context) {
+		if err := streamClusterList(c, http.StatusOK, NewClusterListResponse(clusters)); err != nil {
+			t.Fatalf("can't stream the clusters list: %v", err)
+		}
+	})
+	engine.GET("/buffered", middleware.ETag(), func(c *gin.Context) {
+		writeJSON(c, http.StatusOK, NewClusterListResponse(clusters))
+	})
+	get := func(path string, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/clusters", "")
+	etag := first.Header().Get(middleware.ETagHeader)
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d and ETag %q", first.Code, etag)
+	}
+	if buffered := get("/buffered", "").Header().Get(middleware.ETagHeader); buffered != etag {
+		t.Errorf("expected the ETag of the buffered list %q, got %q", buffered, etag)
+	}
+
+	notModified := get("/clusters", etag)
+	if notModified.Code != http.StatusNotModified {
+		t.Fatalf("expected status 304, got %d", notModified.Code)
+	}
+	if notModified.Body.Len() != 0 {
+		t.Errorf("expected no body, got %q", notModified.Body.Bytes())
+	}
+
+	clusters[0].TotalCost = 20
+	if changed := get("/clusters", etag); changed.Code != http.StatusOK || changed.Header().Get(middleware.ETagHeader) == etag {
+		t.Errorf("expected 200 with a new ETag once the inventory changes, got %d and ETag %q", changed.Code, changed.Header().Get(middleware.ETagHeader))
+	}
+}
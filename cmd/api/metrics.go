@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// unmatchedEndpoint is the endpoint label of the requests not matching any route
+const unmatchedEndpoint = "unmatched"
+
+// apiMetrics keeps the Prometheus metrics of the API. It uses its own registry,
+// so only the ClusterIQ metrics and the Go runtime ones are exposed
+type apiMetrics struct {
+	registry            *prometheus.Registry
+	handler             http.Handler             // Exposition handler of the registry
+	requests            *prometheus.CounterVec   // Requests by endpoint and status code
+	inventoryInstances  prometheus.Gauge         // Instances in the inventory
+	inventoryClusters   prometheus.Gauge         // Clusters in the inventory
+	inventoryAccounts   prometheus.Gauge         // Accounts in the inventory
+	stockUpdateDuration *prometheus.HistogramVec // Duration of the inventory writes by resource
+}
+
+// newAPIMetrics creates and registers the API metrics
+func newAPIMetrics() *apiMetrics {
+	m := &apiMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ciq_api_requests_total",
+			Help: "Total number of API requests by endpoint and status code.",
+		}, []string{"endpoint", "status"}),
+		inventoryInstances: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ciq_inventory_instances",
+			Help: "Number of instances in the inventory.",
+		}),
+		inventoryClusters: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ciq_inventory_clusters",
+			Help: "Number of clusters in the inventory.",
+		}),
+		inventoryAccounts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ciq_inventory_accounts",
+			Help: "Number of accounts in the inventory.",
+		}),
+		stockUpdateDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ciq_stock_update_duration_seconds",
+			Help:    "Duration of the inventory writes posted by the scanner, by resource.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"resource"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.inventoryInstances,
+		m.inventoryClusters,
+		m.inventoryAccounts,
+		m.stockUpdateDuration,
+	)
+	m.handler = promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	return m
+}
+
+// middleware returns a Gin middleware counting every request by its route
+// template, so the endpoint label doesn't grow with the path parameters
+func (m *apiMetrics) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = unmatchedEndpoint
+		}
+		m.requests.WithLabelValues(endpoint, strconv.Itoa(c.Writer.Status())).Inc()
+	}
+}
+
+// observeStockUpdate records the duration of an inventory write
+//
+// Parameters:
+// - resource: The kind of resource written (instances, clusters, accounts).
+// - start: When the write started.
+func (m *apiMetrics) observeStockUpdate(resource string, start time.Time) {
+	m.stockUpdateDuration.WithLabelValues(resource).Observe(time.Since(start).Seconds())
+}
+
+// HandlerMetrics serves the Prometheus metrics of the API. The inventory
+// gauges are refreshed from the DB on every scrape. If the DB can't be
+// reached, the last known values are served.
+func (a APIServer) HandlerMetrics(c *gin.Context) {
+	counts, err := a.sql.GetInventoryCounts()
+	if err != nil {
+		a.requestLogger(c).Error("Can't refresh inventory metrics", zap.Error(err))
+	} else {
+		a.metrics.inventoryInstances.Set(float64(counts.Instances))
+		a.metrics.inventoryClusters.Set(float64(counts.Clusters))
+		a.metrics.inventoryAccounts.Set(float64(counts.Accounts))
+	}
+
+	a.metrics.handler.ServeHTTP(c.Writer, c.Request)
+}
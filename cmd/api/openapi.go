@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+
+	// Generated OpenAPI spec (make swagger-doc), registered on swag
+	"github.com/RHEcosystemAppEng/cluster-iq/cmd/api/docs"
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+	"go.uber.org/zap"
+)
+
+// docsPage is the interactive API documentation. Swagger UI is loaded from
+// its CDN distribution, so it's not bundled on the API binary
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>ClusterIQ API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>`
+
+func init() {
+	// The spec is served by every deployment, so the requests must go to the
+	// host serving it instead of the one on the annotations
+	docs.SwaggerInfo.Host = ""
+}
+
+// setOpenAPIPublicURL points the OpenAPI spec requests to the public URL of
+// the API, instead of the host serving it, when the API is reached through a
+// different address (e.g. a TLS terminating route)
+func setOpenAPIPublicURL(publicURL string) {
+	u, err := url.Parse(publicURL)
+	if err != nil || u.Host == "" {
+		return
+	}
+	docs.SwaggerInfo.Host = u.Host
+	docs.SwaggerInfo.Schemes = []string{u.Scheme}
+}
+
+// HandlerOpenAPI serves the OpenAPI spec generated from the handlers annotations
+func (a APIServer) HandlerOpenAPI(c *gin.Context) {
+	spec, err := swag.ReadDoc()
+	if err != nil {
+		a.requestLogger(c).Error("Can't read OpenAPI spec", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(spec))
+}
+
+// HandlerDocs serves the interactive API documentation of the OpenAPI spec
+func (a APIServer) HandlerDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// overviewEvent is an inventory overview sent to the stream clients
+type overviewEvent struct {
+	// id is the SHA-256 of data, so it only changes when the overview does
+	id string
+	// data is the JSON document of the overview
+	data []byte
+}
+
+// newOverviewEvent renders the overview as a stream event, without the
+// hidden fields (CIQ_HIDDEN_FIELDS), as the streams aren't filtered by the
+// HiddenFields middleware
+func newOverviewEvent(overview models.OverviewSummary, hiddenFields []string) (overviewEvent, error) {
+	data, err := json.Marshal(overview)
+	if err != nil {
+		return overviewEvent{}, err
+	}
+	if data, err = middleware.RemoveHiddenFields(data, hiddenFields); err != nil {
+		return overviewEvent{}, err
+	}
+	sum := sha256.Sum256(data)
+	return overviewEvent{id: hex.EncodeToString(sum[:]), data: data}, nil
+}
+
+// overviewStream broadcasts the inventory overview to the /stream clients
+// every time it changes. The overview is only loaded while there are clients
+// connected, and the number of clients is capped.
+type overviewStream struct {
+	mu          sync.Mutex
+	subscribers map[chan overviewEvent]struct{}
+	// slots has a buffered slot for every allowed client
+	slots chan struct{}
+	// last is the last overview broadcast
+	last overviewEvent
+	// done is closed on shutdown, so the clients are disconnected instead of
+	// keeping the server waiting for them
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newOverviewStream creates an overviewStream for up to maxClients clients
+func newOverviewStream(maxClients int) *overviewStream {
+	return &overviewStream{
+		subscribers: make(map[chan overviewEvent]struct{}),
+		slots:       make(chan struct{}, maxClients),
+		done:        make(chan struct{}),
+	}
+}
+
+// subscribe registers a new client. It returns false if every slot is taken.
+// The channel receives the latest overview when it changes, dropping the
+// previous one if the client didn't read it yet.
+func (s *overviewStream) subscribe() (chan overviewEvent, bool) {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		return nil, false
+	}
+
+	events := make(chan overviewEvent, 1)
+	s.mu.Lock()
+	s.subscribers[events] = struct{}{}
+	s.mu.Unlock()
+	return events, true
+}
+
+// unsubscribe removes a client and releases its slot
+func (s *overviewStream) unsubscribe(events chan overviewEvent) {
+	s.mu.Lock()
+	delete(s.subscribers, events)
+	s.mu.Unlock()
+	<-s.slots
+}
+
+// clients returns the number of connected clients
+func (s *overviewStream) clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers)
+}
+
+// broadcast sends the event to every client if it's different from the last
+// one broadcast
+func (s *overviewStream) broadcast(event overviewEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.id == s.last.id {
+		return
+	}
+	s.last = event
+	for events := range s.subscribers {
+		// Only the latest overview matters to a slow client
+		select {
+		case <-events:
+		default:
+		}
+		events <- event
+	}
+}
+
+// close disconnects every client. Called when the server shuts down
+func (s *overviewStream) close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// run loads the overview every interval while there are clients, and
+// broadcasts it when it changes, until ctx is canceled.
+//
+// Parameters:
+// - ctx: Context stopping the polling when canceled.
+// - interval: Time between the overview loads.
+// - load: Loads the current overview.
+// - hiddenFields: Fields removed from the overview.
+// - logger: Logger for the load errors.
+func (s *overviewStream) run(ctx context.Context, interval time.Duration, load func() (models.OverviewSummary, error), hiddenFields []string, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if s.clients() == 0 {
+			continue
+		}
+		overview, err := load()
+		if err != nil {
+			logger.Error("Can't load the inventory overview for the stream clients", zap.Error(err))
+			continue
+		}
+		event, err := newOverviewEvent(overview, hiddenFields)
+		if err != nil {
+			logger.Error("Can't render the inventory overview for the stream clients", zap.Error(err))
+			continue
+		}
+		s.broadcast(event)
+	}
+}
+
+// writeOverviewEvent writes the overview as an 'overview' Server-Sent Event
+// and flushes it, so it's not held by any buffer on the way to the client
+func writeOverviewEvent(w gin.ResponseWriter, event overviewEvent) error {
+	if _, err := fmt.Fprintf(w, "id: %s\nevent: overview\ndata: %s\n\n", event.id, event.data); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/config"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TestOverviewStream verifies the clients are capped, and only receive the latest overview when it changes
+func TestOverviewStream(t *testing.T) {
+	stream := newOverviewStream(2)
+	first, ok := stream.subscribe()
+	if !ok {
+		t.Fatalf("expected the first client to be accepted")
+	}
+	second, ok := stream.subscribe()
+	if !ok {
+		t.Fatalf("expected the second client to be accepted")
+	}
+	if _, ok := stream.subscribe(); ok {
+		t.Fatalf("expected the third client to be rejected")
+	}
+
+	// Released slots are taken again
+	stream.unsubscribe(second)
+	if _, ok := stream.subscribe(); !ok {
+		t.Fatalf("expected a client to take the released slot")
+	}
+
+	event1, _ := newOverviewEvent(models.OverviewSummary{Clusters: models.ClustersSummary{Count: 1}}, nil)
+	event2, _ := newOverviewEvent(models.OverviewSummary{Clusters: models.ClustersSummary{Count: 2}}, nil)
+	stream.broadcast(event1)
+	stream.broadcast(event1)
+	stream.broadcast(event2)
+	if got := <-first; got.id != event2.id {
+		t.Errorf("expected the latest overview %s, got %s", event2.id, got.id)
+	}
+	select {
+	case got := <-first:
+		t.Errorf("expected a single pending overview, got %s", got.id)
+	default:
+	}
+
+	// Unchanged overviews are not broadcast again
+	stream.broadcast(event2)
+	select {
+	case got := <-first:
+		t.Errorf("expected no event for an unchanged overview, got %s", got.id)
+	default:
+	}
+}
+
+// TestWriteOverviewEventUnbuffered verifies the events go through the buffering middlewares as soon as they are written
+func TestWriteOverviewEventUnbuffered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	event, err := newOverviewEvent(models.OverviewSummary{Clusters: models.ClustersSummary{Count: 3}}, []string{"providers"})
+	if err != nil {
+		t.Fatalf("can't render the overview: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	engine := gin.New()
+	engine.Use(middleware.Gzip(0), middleware.HiddenFields([]string{"count"}))
+	engine.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", middleware.MIMEEventStream)
+		c.Status(http.StatusOK)
+		if err := writeOverviewEvent(c.Writer, event); err != nil {
+			t.Fatalf("can't write the event: %v", err)
+		}
+		if !rec.Flushed || !strings.Contains(rec.Body.String(), "id: "+event.id+"\n") {
+			t.Errorf("expected the event to be sent before the stream ends, got %q", rec.Body.String())
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	engine.ServeHTTP(rec, req)
+
+	if strings.Contains(string(event.data), "providers") {
+		t.Errorf("expected the hidden fields to be removed from the event, got %s", event.data)
+	}
+	want := "id: " + event.id + "\nevent: overview\ndata: " + string(event.data) + "\n\n"
+	if rec.Body.String() != want {
+		t.Errorf("expected body %q, got %q", want, rec.Body.String())
+	}
+}
+
+// TestOverviewStreamAcceptEventStream verifies the EventSource requests, which only accept text/event-stream, pass the content negotiation
+func TestOverviewStreamAcceptEventStream(t *testing.T) {
+	engine, err := setupGin(&config.APIServerConfig{GzipMinSize: 1024}, zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("can't setup gin: %v", err)
+	}
+	event, err := newOverviewEvent(models.OverviewSummary{Clusters: models.ClustersSummary{Count: 3}}, nil)
+	if err != nil {
+		t.Fatalf("can't render the overview: %v", err)
+	}
+	engine.GET("/api/v1/stream", func(c *gin.Context) {
+		c.Header("Content-Type", middleware.MIMEEventStream)
+		c.Status(http.StatusOK)
+		if err := writeOverviewEvent(c.Writer, event); err != nil {
+			t.Fatalf("can't write the event: %v", err)
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stream", nil)
+	req.Header.Set("Accept", middleware.MIMEEventStream)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != middleware.MIMEEventStream {
+		t.Errorf("expected Content-Type %q, got %q", middleware.MIMEEventStream, got)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "id: "+event.id+"\n") {
+		t.Errorf("expected the overview event, got %q", rec.Body.String())
+	}
+}
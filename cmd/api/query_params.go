@@ -0,0 +1,621 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// modifiedSinceParam is the query param used by incremental sync clients
+	// for retrieving only the resources modified after a given RFC3339 timestamp
+	modifiedSinceParam = "modified_since"
+	// createdAfterParam filters resources created on or after a date (YYYY-MM-DD) or RFC3339 timestamp
+	createdAfterParam = "created_after"
+	// createdBeforeParam filters resources created before a date (YYYY-MM-DD) or RFC3339 timestamp
+	createdBeforeParam = "created_before"
+	// fromParam sets the first day of a billing window (YYYY-MM-DD date or RFC3339 timestamp)
+	fromParam = "from"
+	// toParam sets the day after the end of a billing window (YYYY-MM-DD date or RFC3339 timestamp)
+	toParam = "to"
+	// tzParam sets the IANA timezone (e.g. America/New_York) used for interpreting the date-only filters
+	tzParam = "tz"
+	// roleParam filters instances by their exact IAM role/service account
+	roleParam = "role"
+	// rolePrefixParam filters instances by the prefix of their IAM role/service account
+	rolePrefixParam = "role_prefix"
+	// searchQueryParam is the text to look for on the search endpoints
+	searchQueryParam = "q"
+	// statusParam filters resources by their status. Any provider state is accepted and normalized
+	statusParam = "status"
+	// embedParam requests related data to be embedded on the items
+	embedParam = "embed"
+	// embedClusterLabels embeds the cluster's labels on every instance
+	embedClusterLabels = "cluster_labels"
+	// embedAccount embeds the account's descriptor on every cluster
+	embedAccount = "account"
+	// includeExcludedParam includes the instances tagged with CIQ_EXCLUDE_TAG
+	includeExcludedParam = "include_excluded"
+	// instancesParam includes the instances of every cluster
+	instancesParam = "instances"
+	// instanceTypeParam filters instances by their type/size/flavour
+	instanceTypeParam = "type"
+	// unnamedParam filters instances by the emptiness of their name
+	unnamedParam = "unnamed"
+	// asOfParam sets the date (YYYY-MM-DD) used for reporting the costs accumulated in its billing period
+	asOfParam = "as_of"
+	// minUptimeParam filters clusters with an uptime percentage greater or equal than its value
+	minUptimeParam = "min_uptime"
+	// maxUptimeParam filters clusters with an uptime percentage lower or equal than its value
+	maxUptimeParam = "max_uptime"
+	// minInstancesParam filters clusters with an instance count greater or equal than its value
+	minInstancesParam = "min_instances"
+	// maxInstancesParam filters clusters with an instance count lower or equal than its value
+	maxInstancesParam = "max_instances"
+	// accountParam scopes the results to a single account
+	accountParam = "account"
+	// providerParam scopes the results to a cloud provider
+	providerParam = "provider"
+	// regionParam filters instances by the region of their availability zone
+	regionParam = "region"
+	// stateParam filters instances by the raw state reported by their provider (e.g. running, shutting-down)
+	stateParam = "state"
+	// nameParam filters clusters by name
+	nameParam = "name"
+	// matchParam sets how the cluster name is matched (exact, prefix, substring)
+	matchParam = "match"
+	// caseInsensitiveParam makes the cluster name matching case insensitive
+	caseInsensitiveParam = "ci"
+	// deviationsParam sets the number of standard deviations above the mean for the cost outliers
+	deviationsParam = "deviations"
+	// countParam requests only the number of items matching a list request
+	countParam = "count"
+	// limitParam sets the maximum number of results
+	limitParam = "limit"
+	// defaultSearchLimit is the maximum number of results of every category of the inventory search
+	defaultSearchLimit = 20
+	// offsetParam sets the number of results skipped before the page
+	offsetParam = "offset"
+	// maxNameParamLength is the maximum length (bytes) of the name and ID path params
+	maxNameParamLength = 256
+	// defaultPageLimit is the default page size of the paginated lists
+	defaultPageLimit = 100
+	// defaultAuditLimit is the default number of audit entries returned
+	defaultAuditLimit = 100
+	// maxAuditLimit is the maximum number of audit entries returned by a single request
+	maxAuditLimit = 1000
+	// sortParam sets the field used for sorting the results
+	sortParam = "sort"
+	// orderParam sets the sorting order (asc/desc)
+	orderParam = "order"
+	// formatParam selects the format of the exported documents
+	formatParam = "format"
+	// tagParam filters the instances with a tag ("key:value", repeatable)
+	tagParam = "tag"
+	// tagColumnsParam sets the comma separated list of tag keys exported as columns
+	tagColumnsParam = "tag_columns"
+	// modeParam selects the representation of the clusters list
+	modeParam = "mode"
+	// groupParam selects how the instances list is grouped
+	groupParam = "group"
+	// fieldsParam sets the comma separated list of fields kept on the listed items
+	fieldsParam = "fields"
+	// excludeParam sets the comma separated list of fields removed from the listed items
+	excludeParam = "exclude"
+
+	// exportFormatJSON exports the document as JSON (default)
+	exportFormatJSON = "json"
+	// exportFormatCSV exports the document as CSV
+	exportFormatCSV = "csv"
+	// exportFormatYAML exports the document as YAML, converted from the JSON one by middleware.YAML
+	exportFormatYAML = "yaml"
+
+	// matchExact matches the cluster by its ID (default)
+	matchExact = "exact"
+	// matchPrefix matches the clusters whose name starts with the path param
+	matchPrefix = "prefix"
+	// matchSubstring matches the clusters whose name contains the path param
+	matchSubstring = "substring"
+
+	// groupFlat returns the instances as a single list (default)
+	groupFlat = "flat"
+	// groupByCluster returns the instances indexed by their cluster
+	groupByCluster = "by-cluster"
+	// groupByAccount returns the instances indexed by their account and cluster
+	groupByAccount = "by-account"
+
+	// clustersModeFull returns the complete clusters objects (default)
+	clustersModeFull = "full"
+	// clustersModeCounts returns the clusters with their instance counts instead of the instances list
+	clustersModeCounts = "counts"
+)
+
+// parseListParam reads a comma separated list query param, ignoring empty and
+// duplicated items. The param can be repeated (e.g. "?state=running&state=stopped").
+//
+// Parameters:
+// - c: Gin context of the request.
+// - name: Name of the query param.
+//
+// Returns:
+// - The list items in order, or nil if the param was not specified.
+func parseListParam(c *gin.Context, name string) []string {
+	var items []string
+	for _, value := range c.QueryArray(name) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" && !slices.Contains(items, item) {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// parseEmbedParam reads the comma separated 'embed' query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - supported: Embeddings supported by the endpoint.
+//
+// Returns:
+// - The requested embeddings.
+// - An error if any of them is not supported.
+func parseEmbedParam(c *gin.Context, supported ...string) ([]string, error) {
+	embeds := parseListParam(c, embedParam)
+	for _, embed := range embeds {
+		if !slices.Contains(supported, embed) {
+			return nil, fmt.Errorf("invalid '%s' value (%s). Expected one of %s", embedParam, embed, strings.Join(supported, ", "))
+		}
+	}
+	return embeds, nil
+}
+
+// parseModifiedSince reads the 'modified_since' query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+//
+// Returns:
+// - A pointer to the parsed timestamp, or nil if the param was not specified.
+// - An error if the param is not a valid RFC3339 timestamp.
+func parseModifiedSince(c *gin.Context) (*time.Time, error) {
+	value := c.Query(modifiedSinceParam)
+	if value == "" {
+		return nil, nil
+	}
+
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' value (%s). Expected RFC3339 timestamp", modifiedSinceParam, value)
+	}
+	return &since, nil
+}
+
+// parseTimezone reads the 'tz' query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - defaultLocation: Location returned if the param was not specified.
+//
+// Returns:
+// - The location for interpreting the date filters.
+// - An error if the param is not a valid IANA timezone.
+func parseTimezone(c *gin.Context, defaultLocation *time.Location) (*time.Location, error) {
+	value := c.Query(tzParam)
+	if value == "" {
+		return defaultLocation, nil
+	}
+
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' value (%s). Expected IANA timezone name", tzParam, value)
+	}
+	return location, nil
+}
+
+// parseDateParam reads a date query param. Dates (YYYY-MM-DD) are interpreted
+// as the start of the day on location, while RFC3339 timestamps keep their own offset.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - name: Name of the query param.
+// - location: Location of the date-only values.
+//
+// Returns:
+// - A pointer to the parsed time, or nil if the param was not specified.
+// - An error if the param is not a valid date or RFC3339 timestamp.
+func parseDateParam(c *gin.Context, name string, location *time.Location) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if date, err := time.ParseInLocation(time.DateOnly, value, location); err == nil {
+		return &date, nil
+	}
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' value (%s). Expected YYYY-MM-DD date or RFC3339 timestamp", name, value)
+	}
+	return &ts, nil
+}
+
+// parseCreatedRange reads the 'created_after' and 'created_before' query
+// params, interpreting their dates in the timezone of the request.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - defaultLocation: Location used if the request doesn't set the 'tz' param.
+//
+// Returns:
+// - Pointers to the lower and upper limits, nil if they were not specified.
+// - An error if any param is not valid.
+func parseCreatedRange(c *gin.Context, defaultLocation *time.Location) (*time.Time, *time.Time, error) {
+	location, err := parseTimezone(c, defaultLocation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	after, err := parseDateParam(c, createdAfterParam, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	before, err := parseDateParam(c, createdBeforeParam, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	return after, before, nil
+}
+
+// parseBillingWindow reads the 'from' and 'to' query params, interpreting
+// their dates in the timezone of the request.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - defaultLocation: Location used if the request doesn't set the 'tz' param.
+//
+// Returns:
+// - Pointers to the first day and the day after the last one, nil if they were not specified.
+// - An error if any param is not valid, or 'to' is not after 'from'.
+func parseBillingWindow(c *gin.Context, defaultLocation *time.Location) (*time.Time, *time.Time, error) {
+	location, err := parseTimezone(c, defaultLocation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	from, err := parseDateParam(c, fromParam, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseDateParam(c, toParam, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, nil, fmt.Errorf("invalid '%s' value (%s). Expected a date after '%s'", toParam, c.Query(toParam), fromParam)
+	}
+	return from, to, nil
+}
+
+// parseAsOf reads the 'as_of' query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+//
+// Returns:
+// - A pointer to the parsed date, or nil if the param was not specified.
+// - An error if the param is not a valid YYYY-MM-DD date.
+func parseAsOf(c *gin.Context) (*time.Time, error) {
+	value := c.Query(asOfParam)
+	if value == "" {
+		return nil, nil
+	}
+
+	asOf, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' value (%s). Expected YYYY-MM-DD date", asOfParam, value)
+	}
+	return &asOf, nil
+}
+
+// parsePercentParam reads a percentage (0-100) query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - name: Name of the query param.
+//
+// Returns:
+// - A pointer to the parsed percentage, or nil if the param was not specified.
+// - An error if the param is not a number between 0 and 100.
+func parsePercentParam(c *gin.Context, name string) (*float64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	percent, err := strconv.ParseFloat(value, 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("invalid '%s' value (%s). Expected a percentage between 0 and 100", name, value)
+	}
+	return &percent, nil
+}
+
+// parseCountParam reads a non-negative integer query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - name: Name of the query param.
+//
+// Returns:
+// - A pointer to the parsed count, or nil if the param was not specified.
+// - An error if the param is not a non-negative integer.
+func parseCountParam(c *gin.Context, name string) (*int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid '%s' value (%s). Expected a non-negative integer", name, value)
+	}
+	return &count, nil
+}
+
+// parseListFormat reads the format of a list response. The 'format' query
+// param takes precedence over the Accept header negotiated by the
+// middleware, so the CSV documents can be downloaded from a browser link.
+//
+// Parameters:
+// - c: Gin context of the request.
+//
+// Returns:
+// - exportFormatJSON (default), exportFormatCSV or exportFormatYAML.
+// - An error if the 'format' value is not supported.
+func parseListFormat(c *gin.Context) (string, error) {
+	switch format := c.Query(formatParam); format {
+	case exportFormatJSON, exportFormatCSV, exportFormatYAML:
+		return format, nil
+	case "":
+		switch middleware.GetNegotiatedFormat(c) {
+		case middleware.MIMECSV:
+			return exportFormatCSV, nil
+		case middleware.MIMEYAML:
+			return exportFormatYAML, nil
+		}
+		return exportFormatJSON, nil
+	default:
+		return "", fmt.Errorf("invalid '%s' value (%s). Expected %s, %s or %s", formatParam, format, exportFormatJSON, exportFormatCSV, exportFormatYAML)
+	}
+}
+
+// parseNameParam reads a name or ID path param, already decoded from its
+// percent-encoding. Values that can't name any resource are rejected, so they
+// can be told apart from the names that don't exist.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - name: Name of the path param.
+//
+// Returns:
+// - The param value.
+// - An error if the value is blank, longer than maxNameParamLength or contains control characters.
+func parseNameParam(c *gin.Context, name string) (string, error) {
+	value := c.Param(name)
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("invalid '%s' value. Expected a non-empty name", name)
+	}
+	if len(value) > maxNameParamLength {
+		return "", fmt.Errorf("invalid '%s' value. Expected a name of up to %d characters", name, maxNameParamLength)
+	}
+	if strings.ContainsFunc(value, unicode.IsControl) {
+		return "", fmt.Errorf("invalid '%s' value (%q). Expected a name without control characters", name, value)
+	}
+	return value, nil
+}
+
+// parseNameMatch reads how the cluster names are matched, from the 'match'
+// and 'ci' query params.
+//
+// Parameters:
+// - c: Gin context of the request.
+//
+// Returns:
+// - matchExact (default), matchPrefix or matchSubstring.
+// - True if the matching is case insensitive.
+// - An error if any param is not valid.
+func parseNameMatch(c *gin.Context) (string, bool, error) {
+	caseInsensitive, err := parseBoolParam(c, caseInsensitiveParam)
+	if err != nil {
+		return "", false, err
+	}
+	ci := caseInsensitive != nil && *caseInsensitive
+
+	switch match := c.DefaultQuery(matchParam, matchExact); match {
+	case matchExact, matchPrefix, matchSubstring:
+		return match, ci, nil
+	default:
+		return "", false, fmt.Errorf("invalid '%s' value (%s). Expected %s, %s or %s", matchParam, match, matchExact, matchPrefix, matchSubstring)
+	}
+}
+
+// parseInstancesGroup reads how the instances list is grouped.
+//
+// Parameters:
+// - c: Gin context of the request.
+//
+// Returns:
+// - groupFlat (default), groupByCluster or groupByAccount.
+// - An error if the 'group' value is not supported.
+func parseInstancesGroup(c *gin.Context) (string, error) {
+	switch group := c.DefaultQuery(groupParam, groupFlat); group {
+	case groupFlat, groupByCluster, groupByAccount:
+		return group, nil
+	default:
+		return "", fmt.Errorf("invalid '%s' value (%s). Expected %s, %s or %s", groupParam, group, groupFlat, groupByCluster, groupByAccount)
+	}
+}
+
+// parseLimit reads the 'limit' query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - defaultLimit: Limit used if the param is not specified.
+//
+// Returns:
+// - The maximum number of results.
+// - An error if the limit is not a positive integer.
+func parseLimit(c *gin.Context, defaultLimit int) (int, error) {
+	limit, err := parseCountParam(c, limitParam)
+	if err != nil || (limit != nil && *limit == 0) {
+		return 0, fmt.Errorf("invalid '%s' value (%s). Expected a positive integer", limitParam, c.Query(limitParam))
+	}
+	if limit == nil {
+		return defaultLimit, nil
+	}
+	return *limit, nil
+}
+
+// parsePagination reads the 'limit' and 'offset' query params of the paginated lists.
+//
+// Parameters:
+// - c: Gin context of the request.
+//
+// Returns:
+// - The page size, defaultPageLimit if not specified.
+// - The number of items to skip, 0 if not specified.
+// - An error if the limit is not a positive integer or the offset is negative.
+func parsePagination(c *gin.Context) (int, int, error) {
+	limit, err := parseLimit(c, defaultPageLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offset, err := parseCountParam(c, offsetParam)
+	if err != nil {
+		return 0, 0, err
+	}
+	if offset == nil {
+		return limit, 0, nil
+	}
+	return limit, *offset, nil
+}
+
+// parseBoolParam reads a boolean query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - name: Name of the query param.
+//
+// Returns:
+// - A pointer to the parsed value, or nil if the param was not specified.
+// - An error if the param is not a valid boolean.
+func parseBoolParam(c *gin.Context, name string) (*bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' value (%s). Expected true or false", name, value)
+	}
+	return &parsed, nil
+}
+
+// parsePositiveFloatParam reads a positive number query param.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - name: Name of the query param.
+// - defaultValue: Value returned if the param was not specified.
+//
+// Returns:
+// - The parsed value, or defaultValue if the param was not specified.
+// - An error if the param is not a positive number.
+func parsePositiveFloatParam(c *gin.Context, name string, defaultValue float64) (float64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid '%s' value (%s). Expected a positive number", name, value)
+	}
+	return parsed, nil
+}
+
+// parseInstanceListFilters reads the filters of the instance list endpoints.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - location: Default location of the date filters.
+//
+// Returns:
+// - A pointer to the parsed filters.
+// - An error if any param is not valid.
+func parseInstanceListFilters(c *gin.Context, location *time.Location) (*instanceListFilters, error) {
+	modifiedSince, err := parseModifiedSince(c)
+	if err != nil {
+		return nil, err
+	}
+
+	unnamed, err := parseBoolParam(c, unnamedParam)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAfter, createdBefore, err := parseCreatedRange(c, location)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := parseTagParam(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instanceListFilters{
+		modifiedSince: modifiedSince,
+		createdAfter:  createdAfter,
+		createdBefore: createdBefore,
+		unnamed:       unnamed,
+		role:          c.Query(roleParam),
+		rolePrefix:    c.Query(rolePrefixParam),
+		status:        c.Query(statusParam),
+		instanceType:  c.Query(instanceTypeParam),
+		providers:     parseListParam(c, providerParam),
+		regions:       parseListParam(c, regionParam),
+		states:        parseListParam(c, stateParam),
+		tags:          tags,
+	}, nil
+}
+
+// parseTagParam reads the repeatable 'tag' query param. Every value is a
+// "key:value" pair, split on its first colon, so the values can contain colons.
+//
+// Parameters:
+// - c: Gin context of the request.
+//
+// Returns:
+// - The requested tags, nil if the param was not specified.
+// - An error if any value doesn't have the "key:value" format.
+func parseTagParam(c *gin.Context) ([]inventory.Tag, error) {
+	var tags []inventory.Tag
+	for _, value := range c.QueryArray(tagParam) {
+		key, tagValue, found := strings.Cut(value, ":")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid '%s' value (%s). Expected 'key:value'", tagParam, value)
+		}
+		tags = append(tags, inventory.Tag{Key: key, Value: tagValue})
+	}
+	return tags, nil
+}
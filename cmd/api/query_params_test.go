@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestParseNameParam verifies the invalid names are rejected and the percent-encoded ones are decoded
+func TestParseNameParam(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/clusters/:cluster_id", func(c *gin.Context) {
+		name, err := parseNameParam(c, "cluster_id")
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, name)
+	})
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		wantName string
+	}{
+		{name: "Plain", path: "/clusters/prod-cluster-01", wantCode: http.StatusOK, wantName: "prod-cluster-01"},
+		{name: "Encoded dash", path: "/clusters/prod%2Dcluster", wantCode: http.StatusOK, wantName: "prod-cluster"},
+		{name: "Encoded space", path: "/clusters/prod%20cluster", wantCode: http.StatusOK, wantName: "prod cluster"},
+		{name: "Encoded unicode", path: "/clusters/cl%C3%BAster", wantCode: http.StatusOK, wantName: "clúster"},
+		{name: "Max length", path: "/clusters/" + strings.Repeat("a", maxNameParamLength), wantCode: http.StatusOK, wantName: strings.Repeat("a", maxNameParamLength)},
+		{name: "Blank", path: "/clusters/%20%20", wantCode: http.StatusBadRequest},
+		{name: "Encoded NUL", path: "/clusters/prod%00", wantCode: http.StatusBadRequest},
+		{name: "Encoded newline", path: "/clusters/prod%0Acluster", wantCode: http.StatusBadRequest},
+		{name: "Too long", path: "/clusters/" + strings.Repeat("a", maxNameParamLength+1), wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d (%s)", tt.wantCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode == http.StatusOK && rec.Body.String() != tt.wantName {
+				t.Errorf("expected name %q, got %q", tt.wantName, rec.Body.String())
+			}
+		})
+	}
+}
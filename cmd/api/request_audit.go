@@ -0,0 +1,119 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/events"
+	sqlclient "github.com/RHEcosystemAppEng/cluster-iq/internal/sql_client"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// anonymousActor identifies the requests without auth token on the audit trail
+	anonymousActor = "anonymous"
+	// auditActorIDLength is the number of hex characters of the token hash used as actor ID
+	auditActorIDLength = 12
+	// auditResourcesKey is the Gin context key holding the resources of the request body (see auditBodyResources)
+	auditResourcesKey = "audit_resources"
+	// auditSkippedKey is the Gin context key set on read-only requests with a mutating method (see skipAudit)
+	auditSkippedKey = "audit_skipped"
+)
+
+// requestAuditor appends every mutating request to the requests audit trail
+type requestAuditor struct {
+	sql    *sqlclient.SQLClient
+	logger *zap.Logger
+}
+
+// newRequestAuditor creates a new requestAuditor writing on the given DB
+func newRequestAuditor(sql *sqlclient.SQLClient, logger *zap.Logger) *requestAuditor {
+	return &requestAuditor{sql: sql, logger: logger}
+}
+
+// isMutatingMethod checks if the HTTP method modifies the inventory
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// auditActor identifies who sent the request. Tokens are never stored, only
+// the beginning of their SHA-256 hash, which is enough for correlating requests
+func auditActor(r *http.Request) string {
+	credentials := strings.TrimSpace(r.Header.Get("Authorization"))
+	if credentials == "" {
+		return anonymousActor
+	}
+	if _, token, found := strings.Cut(credentials, " "); found {
+		credentials = strings.TrimSpace(token)
+	}
+	sum := sha256.Sum256([]byte(credentials))
+	return hex.EncodeToString(sum[:])[:auditActorIDLength]
+}
+
+// auditResources returns the route params of the request, and the resources
+// of its body recorded by the handler, as "param=value"
+func auditResources(c *gin.Context) []string {
+	resources := make([]string, 0, len(c.Params))
+	for _, param := range c.Params {
+		resources = append(resources, param.Key+"="+param.Value)
+	}
+	return append(resources, c.GetStringSlice(auditResourcesKey)...)
+}
+
+// auditBodyResources records the resources affected by a request which are
+// identified on its body instead of its route, so they reach the audit trail.
+// Repeated values are recorded once
+func auditBodyResources(c *gin.Context, key string, values ...string) {
+	resources := c.GetStringSlice(auditResourcesKey)
+	for _, value := range values {
+		if resource := key + "=" + value; !slices.Contains(resources, resource) {
+			resources = append(resources, resource)
+		}
+	}
+	c.Set(auditResourcesKey, resources)
+}
+
+// skipAudit flags the request as read-only, so it's not audited even if its
+// method is a mutating one. Used as route middleware on the POST endpoints
+// which only take a body for describing a query
+func skipAudit(c *gin.Context) {
+	c.Set(auditSkippedKey, true)
+}
+
+// middleware returns a Gin middleware auditing the mutating requests once
+// they're served. Failing to write the audit entry doesn't fail the request
+func (r *requestAuditor) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if !isMutatingMethod(c.Request.Method) || c.GetBool(auditSkippedKey) {
+			return
+		}
+
+		entry := events.RequestAuditEntry{
+			Timestamp: time.Now().UTC(),
+			Actor:     auditActor(c.Request),
+			Method:    c.Request.Method,
+			Route:     c.FullPath(),
+			Path:      c.Request.URL.Path,
+			Resources: auditResources(c),
+			Status:    c.Writer.Status(),
+		}
+		if err := r.sql.AddRequestAuditEntry(entry); err != nil {
+			r.logger.Error("Failed to write request audit entry",
+				zap.String("method", entry.Method),
+				zap.String("path", entry.Path),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestAuditResources verifies the audited resources include the route params and the body resources recorded by the handler, and read-only routes are skipped
+func TestAuditResources(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var resources []string
+	var skipped bool
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Next()
+		resources = auditResources(c)
+		skipped = c.GetBool(auditSkippedKey)
+	})
+	engine.POST("/instances", func(c *gin.Context) {
+		auditBodyResources(c, "instance_id", "i-1", "i-2", "i-1")
+	})
+	engine.PATCH("/clusters/:cluster_id", func(*gin.Context) {})
+	engine.POST("/validate", skipAudit, func(*gin.Context) {})
+
+	tests := []struct {
+		name              string
+		method            string
+		path              string
+		expectedResources []string
+		expectedSkipped   bool
+	}{
+		{"Body resources", http.MethodPost, "/instances", []string{"instance_id=i-1", "instance_id=i-2"}, false},
+		{"Route params", http.MethodPatch, "/clusters/c1", []string{"cluster_id=c1"}, false},
+		{"Read-only route", http.MethodPost, "/validate", []string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+			if !slices.Equal(resources, tt.expectedResources) {
+				t.Errorf("expected resources %v, got %v", tt.expectedResources, resources)
+			}
+			if skipped != tt.expectedSkipped {
+				t.Errorf("expected skipped %v, got %v", tt.expectedSkipped, skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestStats keeps lightweight request counters of the API. Every counter is
+// updated atomically, so it's safe to be used by concurrent requests
+type requestStats struct {
+	since        time.Time    // When the counters started
+	total        atomic.Int64 // Total number of requests
+	clientErrors atomic.Int64 // Number of 4xx responses
+	serverErrors atomic.Int64 // Number of 5xx responses
+	routes       sync.Map     // Requests per route ("METHOD /path") as *atomic.Int64
+}
+
+// newRequestStats creates a new requestStats with every counter at zero
+func newRequestStats() *requestStats {
+	return &requestStats{since: time.Now()}
+}
+
+// record accounts a finished request
+//
+// Parameters:
+// - route: The matched route ("METHOD /path"). Empty for unmatched requests.
+// - status: The response status code.
+func (s *requestStats) record(route string, status int) {
+	s.total.Add(1)
+
+	switch {
+	case status >= http.StatusInternalServerError:
+		s.serverErrors.Add(1)
+	case status >= http.StatusBadRequest:
+		s.clientErrors.Add(1)
+	}
+
+	if route == "" {
+		return
+	}
+	counter, ok := s.routes.Load(route)
+	if !ok {
+		counter, _ = s.routes.LoadOrStore(route, new(atomic.Int64))
+	}
+	counter.(*atomic.Int64).Add(1)
+}
+
+// middleware returns a Gin middleware recording every request on the stats
+func (s *requestStats) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		route := ""
+		if path := c.FullPath(); path != "" {
+			route = c.Request.Method + " " + path
+		}
+		s.record(route, c.Writer.Status())
+	}
+}
+
+// snapshot returns the current value of the counters
+func (s *requestStats) snapshot() *RequestStatsResponse {
+	routes := make(map[string]int64)
+	s.routes.Range(func(key, value any) bool {
+		routes[key.(string)] = value.(*atomic.Int64).Load()
+		return true
+	})
+
+	return &RequestStatsResponse{
+		Since:         s.since,
+		TotalRequests: s.total.Load(),
+		ClientErrors:  s.clientErrors.Load(),
+		ServerErrors:  s.serverErrors.Load(),
+		Routes:        routes,
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// TestRequestStatsConcurrentRecord verifies the counters are consistent when updated concurrently
+func TestRequestStatsConcurrentRecord(t *testing.T) {
+	stats := newRequestStats()
+
+	const workers, requests = 16, 600
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < requests; i++ {
+				switch i % 3 {
+				case 0:
+					stats.record("GET /api/v1/clusters", http.StatusOK)
+				case 1:
+					stats.record("GET /api/v1/instances", http.StatusBadRequest)
+				default:
+					stats.record("", http.StatusInternalServerError)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	snapshot := stats.snapshot()
+	if snapshot.TotalRequests != workers*requests {
+		t.Errorf("expected %d total requests, got %d", workers*requests, snapshot.TotalRequests)
+	}
+
+	// Every kind of request is a third of the total
+	perKind := int64(workers * requests / 3)
+	if got := snapshot.Routes["GET /api/v1/clusters"]; got != perKind {
+		t.Errorf("expected %d requests on clusters route, got %d", perKind, got)
+	}
+	if snapshot.ClientErrors != perKind || snapshot.ServerErrors != perKind {
+		t.Errorf("expected %d client and server errors, got %d and %d", perKind, snapshot.ClientErrors, snapshot.ServerErrors)
+	}
+	if snapshot.ClientErrors+snapshot.ServerErrors+snapshot.Routes["GET /api/v1/clusters"] != snapshot.TotalRequests {
+		t.Errorf("counters don't add up: %+v", snapshot)
+	}
+	if _, ok := snapshot.Routes[""]; ok {
+		t.Errorf("unmatched requests must not be recorded per route")
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/robfig/cron/v3"
+)
+
+// BulkClusterScheduleRequest represents the request for scheduling the power
+// on/off of every cluster matching a selector
+type BulkClusterScheduleRequest struct {
+	// AccountName selects the clusters of an account.
+	AccountName string `json:"accountName"`
+	// NamePattern selects the clusters whose name matches a glob pattern (e.g. "dev-*").
+	NamePattern string `json:"namePattern"`
+	// Labels selects the clusters having every label, with the same key and value. The labels of a cluster are the tags of its instances.
+	Labels map[string]string `json:"labels"`
+	// PowerOnCronExp is the cron expression for powering on the clusters.
+	PowerOnCronExp string `json:"powerOnCronExp"`
+	// PowerOffCronExp is the cron expression for powering off the clusters.
+	PowerOffCronExp string `json:"powerOffCronExp"`
+}
+
+// Validate checks the request has at least one selector, at least one schedule, and that both are well formed.
+//
+// Returns:
+// - An error if the request is not valid.
+func (r BulkClusterScheduleRequest) Validate() error {
+	if r.AccountName == "" && r.NamePattern == "" && len(r.Labels) == 0 {
+		return errors.New("at least one selector (accountName, namePattern, labels) is required")
+	}
+	if r.NamePattern != "" {
+		if _, err := path.Match(r.NamePattern, ""); err != nil {
+			return fmt.Errorf("invalid namePattern '%s': %w", r.NamePattern, err)
+		}
+	}
+	if _, ok := r.Labels[""]; ok {
+		return errors.New("invalid labels: keys can't be empty")
+	}
+
+	if r.PowerOnCronExp == "" && r.PowerOffCronExp == "" {
+		return errors.New("at least one schedule (powerOnCronExp, powerOffCronExp) is required")
+	}
+	for _, expression := range []string{r.PowerOnCronExp, r.PowerOffCronExp} {
+		if expression == "" {
+			continue
+		}
+		if _, err := cron.ParseStandard(expression); err != nil {
+			return fmt.Errorf("invalid cron expression '%s': %w", expression, err)
+		}
+	}
+	return nil
+}
+
+// maxClusterBatchNames is the maximum number of cluster names of a ClusterBatchRequest
+const maxClusterBatchNames = 100
+
+// ClusterBatchRequest represents the request for looking up a set of clusters by name
+type ClusterBatchRequest struct {
+	// Names is the list of cluster names to look up.
+	Names []string `json:"names"`
+}
+
+// Validate checks the request has between one and maxClusterBatchNames names, and none of them is empty.
+//
+// Returns:
+// - An error if the request is not valid.
+func (r ClusterBatchRequest) Validate() error {
+	if len(r.Names) == 0 {
+		return errors.New("at least one cluster name (names) is required")
+	}
+	if len(r.Names) > maxClusterBatchNames {
+		return fmt.Errorf("too many cluster names (%d). The maximum is %d", len(r.Names), maxClusterBatchNames)
+	}
+	for _, name := range r.Names {
+		if name == "" {
+			return errors.New("cluster names (names) can't be empty")
+		}
+	}
+	return nil
+}
+
+// ClusterInstancesFilterRequest represents the request for looking up a set of
+// instances within a cluster
+type ClusterInstancesFilterRequest struct {
+	// InstanceIDs is the list of instance IDs to look up.
+	InstanceIDs []string `json:"instanceIDs"`
+}
+
+// Validate checks the request has at least one instance ID.
+//
+// Returns:
+// - An error if the request is not valid.
+func (r ClusterInstancesFilterRequest) Validate() error {
+	if len(r.InstanceIDs) == 0 {
+		return errors.New("at least one instance ID (instanceIDs) is required")
+	}
+	return nil
+}
+
+// InventoryValidationRequest represents a whole inventory, with the same
+// accounts, clusters and instances batches posted by a scanner, to be
+// validated without writing it
+type InventoryValidationRequest struct {
+	// Accounts is the accounts batch.
+	Accounts []inventory.Account `json:"accounts"`
+	// Clusters is the clusters batch.
+	Clusters []inventory.Cluster `json:"clusters"`
+	// Instances is the instances batch.
+	Instances []inventory.Instance `json:"instances"`
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+)
+
+// TestClusterBatchRequestValidate verifies the batch lookups require between one and maxClusterBatchNames non empty names
+func TestClusterBatchRequestValidate(t *testing.T) {
+	tooMany := make([]string, maxClusterBatchNames+1)
+	for i := range tooMany {
+		tooMany[i] = "cluster-" + strconv.Itoa(i)
+	}
+
+	tests := []struct {
+		name    string
+		names   []string
+		wantErr bool
+	}{
+		{name: "Valid", names: []string{"a", "b"}},
+		{name: "Maximum", names: tooMany[:maxClusterBatchNames]},
+		{name: "Empty", wantErr: true},
+		{name: "Too many", names: tooMany, wantErr: true},
+		{name: "Empty name", names: []string{"a", ""}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (ClusterBatchRequest{Names: tt.names}).Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestNewClusterBatchResponse verifies the requested names without clusters are reported once, on the requested order
+func TestNewClusterBatchResponse(t *testing.T) {
+	clusters := []inventory.Cluster{
+		{ID: "a-1", Name: "a", AccountName: "account-1"},
+		{ID: "a-2", Name: "a", AccountName: "account-2"},
+		{ID: "c-1", Name: "c", AccountName: "account-1"},
+	}
+
+	response := NewClusterBatchResponse([]string{"d", "a", "b", "c", "d"}, clusters)
+	if len(response.Clusters) != 3 || response.Total != 3 {
+		t.Errorf("expected the 3 clusters, got %+v", response.ClusterListResponse)
+	}
+	if want := []string{"d", "b"}; !slices.Equal(response.Missing, want) {
+		t.Errorf("expected missing %v, got %v", want, response.Missing)
+	}
+
+	if response := NewClusterBatchResponse([]string{"a"}, clusters[:2]); response.Missing == nil || len(response.Missing) != 0 {
+		t.Errorf("expected an empty missing list, got %#v", response.Missing)
+	}
+}
+
+// TestBulkClusterScheduleRequestValidate verifies the bulk schedules require a selector, which can be the labels alone, and a schedule
+func TestBulkClusterScheduleRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request BulkClusterScheduleRequest
+		wantErr bool
+	}{
+		{name: "Account", request: BulkClusterScheduleRequest{AccountName: "acc", PowerOffCronExp: "0 20 * * *"}},
+		{name: "Labels", request: BulkClusterScheduleRequest{Labels: map[string]string{"env": "dev"}, PowerOnCronExp: "0 8 * * *"}},
+		{name: "No selector", request: BulkClusterScheduleRequest{PowerOnCronExp: "0 8 * * *"}, wantErr: true},
+		{name: "Empty label key", request: BulkClusterScheduleRequest{Labels: map[string]string{"": "dev"}, PowerOnCronExp: "0 8 * * *"}, wantErr: true},
+		{name: "No schedule", request: BulkClusterScheduleRequest{Labels: map[string]string{"env": "dev"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.request.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/actions"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/events"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/models"
 )
 
 type ScheduledActionListResponse struct {
-	Count   int              `json:"count,omitempty"` // Number of actions omitted if empty.
-	Actions []actions.Action `json:"actions"`         // List of actions
+	Count     int              `json:"count,omitempty"`     // Number of actions omitted if empty.
+	Actions   []actions.Action `json:"actions"`             // List of actions
+	Truncated bool             `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
 }
 
 func NewScheduledActionListResponse(actionList []actions.Action) *ScheduledActionListResponse {
@@ -36,9 +42,11 @@ func NewScheduledActionListResponse(actionList []actions.Action) *ScheduledActio
 // GenericErrorResponse represents a generic error response returned by the API.
 //
 // This structure is used to provide a consistent error message format in the API responses.
-// It includes a single field, `Message`, that contains a descriptive error message.
+// It includes a descriptive error message, `Message`, and the HTTP status code of the response, `Code`.
 type GenericErrorResponse struct {
-	Message string `json:"message"`
+	Message   string `json:"message"`
+	Code      int    `json:"code"`
+	RequestID string `json:"requestID,omitempty"` // ID of the request (X-Request-ID), to be quoted on bug reports.
 }
 
 // NewGenericErrorResponse creates a new instance of GenericErrorResponse.
@@ -46,16 +54,23 @@ type GenericErrorResponse struct {
 // This function is a utility for initializing a GenericErrorResponse with a specified error message.
 //
 // Parameters:
+// - code: The HTTP status code of the response.
 // - message: The error message to include in the response.
 //
 // Returns:
 // - A pointer to a new GenericErrorResponse instance containing the provided message.
-func NewGenericErrorResponse(message string) *GenericErrorResponse {
+func NewGenericErrorResponse(code int, message string) *GenericErrorResponse {
 	return &GenericErrorResponse{
 		Message: message,
+		Code:    code,
 	}
 }
 
+// CountResponse represents the API response of the count only list requests
+type CountResponse struct {
+	Count int `json:"count"` // Number of items matching the request.
+}
+
 // HealthChecks represents the different health checks performed by the API.
 // It indicates the status of both the API and the database.
 type HealthChecks struct {
@@ -69,22 +84,78 @@ type HealthCheckResponse struct {
 	HealthChecks HealthChecks `json:"health_checks"` // Details of the health checks performed.
 }
 
+// Readiness checks names and timeout
+const (
+	readinessCheckDB        = "db"
+	readinessCheckInventory = "inventory_staleness"
+	// readinessPingTimeout is the maximum time for the DB to answer the readiness ping
+	readinessPingTimeout = 2 * time.Second
+)
+
+// ReadinessResponse represents the API response for the readiness check.
+// When the API is not ready, it reports the check that failed and why.
+type ReadinessResponse struct {
+	Ready             bool       `json:"ready"`                         // Indicates whether the API is ready to serve.
+	FailedCheck       string     `json:"failed_check,omitempty"`        // Name of the failed check (db, inventory_staleness).
+	Message           string     `json:"message,omitempty"`             // Description of the failure.
+	DBAddress         string     `json:"db_address"`                    // Address (host:port) of the DB.
+	LastScanTimestamp *time.Time `json:"last_scan_timestamp,omitempty"` // Last scan of the inventory, omitted if unknown.
+}
+
+// LivenessResponse represents the API response for the liveness check.
+type LivenessResponse struct {
+	Alive bool `json:"alive"` // Always true, as the process is able to respond.
+}
+
+// VersionResponse represents the API response containing the build metadata of the API.
+type VersionResponse struct {
+	Version   string `json:"version"`   // Version of the API.
+	Commit    string `json:"commit"`    // Git short-hash of the built source code.
+	GoVersion string `json:"goVersion"` // Go version the binary was built with.
+	BuildTime string `json:"buildTime"` // UTC time (RFC3339) when the binary was built.
+}
+
 // TagListResponse represents the API response containing a list of tags.
 type TagListResponse struct {
-	Count int             `json:"count,omitempty"` // Number of tags, omitted if empty.
-	Tags  []inventory.Tag `json:"tags"`            // List of tags.
+	Count     int             `json:"count,omitempty"`     // Number of tags, omitted if empty.
+	Tags      []inventory.Tag `json:"tags"`                // List of tags.
+	Truncated bool            `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
+}
+
+// TagKeyListResponse represents the API response containing the distinct tag keys of the inventory.
+type TagKeyListResponse struct {
+	Count int      `json:"count,omitempty"` // Number of keys, omitted if empty.
+	Keys  []string `json:"keys"`            // Tag keys sorted by name.
 }
 
 // EventsListResponse represents the API response containing a list of resource-specific audit events.
 type EventsListResponse struct {
-	Count  int                 `json:"count,omitempty"` // Number of events, omitted if empty.
-	Events []events.AuditEvent `json:"events"`          // List of events.
+	Count     int                 `json:"count,omitempty"`     // Number of events, omitted if empty.
+	Events    []events.AuditEvent `json:"events"`              // List of events.
+	Truncated bool                `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
 }
 
 // SystemEventsListResponse represents the API response containing a list of system-wide audit events.
 type SystemEventsListResponse struct {
-	Count  int                       `json:"count,omitempty"` // Number of events, omitted if empty.
-	Events []events.SystemAuditEvent `json:"events"`          // List of events.
+	Count     int                       `json:"count,omitempty"`     // Number of events, omitted if empty.
+	Events    []events.SystemAuditEvent `json:"events"`              // List of events.
+	Truncated bool                      `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
+}
+
+// NewTagKeyListResponse creates a new TagKeyListResponse instance.
+// It ensures that an empty array is returned if the input key list is empty.
+//
+// Parameters:
+// - keys: A slice of tag keys.
+//
+// Returns:
+// - A pointer to a TagKeyListResponse.
+func NewTagKeyListResponse(keys []string) *TagKeyListResponse {
+	// If there is no keys, an empty array is returned instead of null
+	if len(keys) == 0 {
+		keys = []string{}
+	}
+	return &TagKeyListResponse{Count: len(keys), Keys: keys}
 }
 
 // NewTagListResponse creates a new TagListResponse instance.
@@ -116,8 +187,9 @@ func NewTagListResponse(tags []inventory.Tag) *TagListResponse {
 
 // ExpenseListResponse represents the API response containing a list of expenses
 type ExpenseListResponse struct {
-	Count    int                 `json:"count,omitempty"` // Number of expenses, omitted if empty.
-	Expenses []inventory.Expense `json:"expenses"`        // List of expenses.
+	Count     int                 `json:"count,omitempty"`     // Number of expenses, omitted if empty.
+	Expenses  []inventory.Expense `json:"expenses"`            // List of expenses.
+	Truncated bool                `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
 }
 
 // NewExpenseListResponse creates a new ExpenseListResponse instance.
@@ -149,8 +221,10 @@ func NewExpenseListResponse(expenses []inventory.Expense) *ExpenseListResponse {
 
 // InstanceListResponse represents the API response containing a list of instances.
 type InstanceListResponse struct {
-	Count     int                  `json:"count,omitempty"` // Number of instances, omitted if empty.
-	Instances []inventory.Instance `json:"instances"`       // List of instances.
+	Count     int                  `json:"count,omitempty"`     // Number of instances, omitted if empty.
+	Instances []inventory.Instance `json:"instances"`           // List of instances.
+	Total     int                  `json:"total"`               // Number of instances matching the request across every page.
+	Truncated bool                 `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
 }
 
 // NewInstanceListResponse creates a new InstanceListResponse instance.
@@ -180,10 +254,90 @@ func NewInstanceListResponse(instances []inventory.Instance) *InstanceListRespon
 	return &response
 }
 
+// unassignedCluster groups the instances without cluster
+const unassignedCluster = "unassigned"
+
+// InstancesByClusterResponse represents the API response containing the instances indexed by cluster
+type InstancesByClusterResponse struct {
+	Count     int                             `json:"count"`               // Number of instances on every cluster.
+	Clusters  map[string][]inventory.Instance `json:"clusters"`            // Instances indexed by cluster ID, or "unassigned". Serialized sorted by key.
+	Total     int                             `json:"total"`               // Number of instances matching the request across every page.
+	Truncated bool                            `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
+}
+
+// NewInstancesByClusterResponse creates a new InstancesByClusterResponse
+// instance. Clusters are indexed by ID, as their names are only unique per
+// account. The instances keep their order within each cluster.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance.
+//
+// Returns:
+// - A pointer to an InstancesByClusterResponse.
+func NewInstancesByClusterResponse(instances []inventory.Instance) *InstancesByClusterResponse {
+	response := InstancesByClusterResponse{
+		Count:    len(instances),
+		Clusters: make(map[string][]inventory.Instance),
+	}
+	for _, instance := range instances {
+		key := cmp.Or(instance.ClusterID, unassignedCluster)
+		response.Clusters[key] = append(response.Clusters[key], instance)
+	}
+	return &response
+}
+
+// InstancesByAccountResponse represents the API response containing the instances indexed by account and cluster
+type InstancesByAccountResponse struct {
+	Count     int                                        `json:"count"`               // Number of instances on every account.
+	Accounts  map[string]map[string][]inventory.Instance `json:"accounts"`            // Instances indexed by account name and cluster name, or "unassigned". Serialized sorted by key.
+	Total     int                                        `json:"total"`               // Number of instances matching the request across every page.
+	Truncated bool                                       `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
+}
+
+// NewInstancesByAccountResponse creates a new InstancesByAccountResponse
+// instance. The account and name of the instances' clusters are resolved from
+// the clusters list, and the instances keep their order within each cluster.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance.
+// - clusters: The clusters of the instances.
+//
+// Returns:
+// - A pointer to an InstancesByAccountResponse.
+func NewInstancesByAccountResponse(instances []inventory.Instance, clusters []inventory.Cluster) *InstancesByAccountResponse {
+	byID := make(map[string]inventory.Cluster, len(clusters))
+	for _, cluster := range clusters {
+		byID[cluster.ID] = cluster
+	}
+
+	response := InstancesByAccountResponse{
+		Count:    len(instances),
+		Accounts: make(map[string]map[string][]inventory.Instance),
+	}
+	for _, instance := range instances {
+		account, clusterName := unassignedCluster, unassignedCluster
+		if cluster, ok := byID[instance.ClusterID]; ok {
+			account = cmp.Or(cluster.AccountName, unassignedCluster)
+			clusterName = cluster.Name
+		}
+
+		if response.Accounts[account] == nil {
+			response.Accounts[account] = make(map[string][]inventory.Instance)
+		}
+		response.Accounts[account][clusterName] = append(response.Accounts[account][clusterName], instance)
+	}
+	return &response
+}
+
 // ClusterListResponse represents the API response containing a list of clusters
 type ClusterListResponse struct {
-	Count    int                 `json:"count,omitempty"` // Number of clusters, omitted if empty.
-	Clusters []inventory.Cluster `json:"clusters"`        // List of clusters.
+	Count        int                                  `json:"count,omitempty"`          // Number of clusters, omitted if empty.
+	Clusters     []inventory.Cluster                  `json:"clusters"`                 // List of clusters.
+	Total        int                                  `json:"total"`                    // Number of clusters matching the request across every page.
+	Truncated    bool                                 `json:"truncated,omitempty"`      // Set if the list was capped by CIQ_MAX_RESULTS.
+	Currency     string                               `json:"currency"`                 // Currency of every cost.
+	TotalCost    float64                              `json:"total_cost"`               // Total cost of the listed clusters.
+	CostByStatus map[inventory.InstanceStatus]float64 `json:"cost_by_status,omitempty"` // Cost of the listed clusters' instances by their status. Omitted if the clusters have no breakdown.
 }
 
 // NewClusterListResponse creates a new ClusterListResponse instance.
@@ -204,19 +358,84 @@ func NewClusterListResponse(clusters []inventory.Cluster) *ClusterListResponse {
 
 	response := ClusterListResponse{
 		Clusters: clusters,
+		Currency: inventory.CostCurrency,
 	}
 	// If there is more than one cluster, the response contains a 'count' field
 	if numClusters > 1 {
 		response.Count = numClusters
 	}
 
+	costs := make([]map[inventory.InstanceStatus]float64, 0, numClusters)
+	for _, cluster := range clusters {
+		response.TotalCost += cluster.TotalCost
+		costs = append(costs, cluster.CostByStatus)
+	}
+	if breakdown := sumCostByStatus(costs...); len(breakdown) > 0 {
+		response.CostByStatus = breakdown
+	}
+
+	return &response
+}
+
+// ClusterCounts represents a lightweight view of a cluster including its instance counts instead of the instances list
+type ClusterCounts struct {
+	ID              string                           `json:"id"`              // The ID of the cluster.
+	Name            string                           `json:"name"`            // The name of the cluster.
+	Status          inventory.InstanceStatus         `json:"status"`          // The status of the cluster.
+	Region          string                           `json:"region"`          // The region where the cluster resides.
+	InstanceCount   int                              `json:"instanceCount"`   // Number of instances of the cluster.
+	StatusBreakdown map[inventory.InstanceStatus]int `json:"statusBreakdown"` // Number of instances on each status.
+}
+
+// ClusterCountsListResponse represents the API response containing a list of clusters with their instance counts.
+type ClusterCountsListResponse struct {
+	Count     int             `json:"count,omitempty"`     // Number of clusters, omitted if empty.
+	Clusters  []ClusterCounts `json:"clusters"`            // List of clusters.
+	Total     int             `json:"total"`               // Number of clusters matching the request across every page.
+	Truncated bool            `json:"truncated,omitempty"` // Set if the list was capped by CIQ_MAX_RESULTS.
+}
+
+// NewClusterCountsListResponse creates a new ClusterCountsListResponse instance.
+// The instances of every cluster must be already loaded for computing the counts.
+//
+// Parameters:
+// - clusters: A slice of inventory.Cluster.
+//
+// Returns:
+// - A pointer to a ClusterCountsListResponse.
+func NewClusterCountsListResponse(clusters []inventory.Cluster) *ClusterCountsListResponse {
+	clusterCounts := make([]ClusterCounts, 0, len(clusters))
+	for _, cluster := range clusters {
+		clusterCounts = append(clusterCounts, ClusterCounts{
+			ID:              cluster.ID,
+			Name:            cluster.Name,
+			Status:          cluster.Status,
+			Region:          cluster.Region,
+			InstanceCount:   len(cluster.Instances),
+			StatusBreakdown: cluster.InstanceStatusBreakdown(),
+		})
+	}
+
+	response := ClusterCountsListResponse{
+		Clusters: clusterCounts,
+	}
+	// If there is more than one cluster, the response contains a 'count' field
+	if len(clusterCounts) > 1 {
+		response.Count = len(clusterCounts)
+	}
+
 	return &response
 }
 
 // AccountListResponse represents the API response containing a list of accounts.
 type AccountListResponse struct {
-	Count    int                 `json:"count,omitempty"` // Number of accounts, omitted if empty.
-	Accounts []inventory.Account `json:"accounts"`        // List of accounts.
+	Count        int                                  `json:"count,omitempty"`          // Number of accounts, omitted if empty.
+	Accounts     []inventory.Account                  `json:"accounts"`                 // List of accounts.
+	Total        int                                  `json:"total"`                    // Number of accounts matching the request across every page.
+	Truncated    bool                                 `json:"truncated,omitempty"`      // Set if the list was capped by CIQ_MAX_RESULTS.
+	Currency     string                               `json:"currency"`                 // Currency of every cost.
+	TotalCost    float64                              `json:"total_cost"`               // Total cost of the listed accounts.
+	CostByStatus map[inventory.InstanceStatus]float64 `json:"cost_by_status,omitempty"` // Cost of the listed accounts' instances by their status. Omitted if the accounts have no breakdown.
 }
 
 // NewAccountListResponse creates a new AccountListResponse instance.
@@ -237,12 +456,22 @@ func NewAccountListResponse(accounts []inventory.Account) *AccountListResponse {
 
 	response := AccountListResponse{
 		Accounts: accounts,
+		Currency: inventory.CostCurrency,
 	}
 	// If there is more than one account, the response contains a 'count' field
 	if numAccounts > 1 {
 		response.Count = numAccounts
 	}
 
+	costs := make([]map[inventory.InstanceStatus]float64, 0, numAccounts)
+	for _, account := range accounts {
+		response.TotalCost += account.TotalCost
+		costs = append(costs, account.CostByStatus)
+	}
+	if breakdown := sumCostByStatus(costs...); len(breakdown) > 0 {
+		response.CostByStatus = breakdown
+	}
+
 	return &response
 }
 
@@ -256,6 +485,7 @@ type ClusterStatusChangeResponse struct {
 	Region      string                   `json:"availability_zone"` // The region where the cluster resides.
 	Status      inventory.InstanceStatus `json:"status"`            // The resulting status of the cluster.
 	Error       string                   `json:"error_msg"`         // Error message if any issue occurred.
+	Skipped     []string                 `json:"skipped_instances"` // List of protected instance IDs which were not affected.
 }
 
 // NewClusterStatusChangeResponse creates and returns a ClusterStatusChangeResponse instance.
@@ -267,14 +497,21 @@ type ClusterStatusChangeResponse struct {
 // - region: The region where the cluster resides.
 // - status: The status of the cluster.
 // - instances: A list of instance IDs in the cluster.
+// - skipped: A list of protected instance IDs that were not affected.
 // - err: An error, if any, during the operation.
 //
 // Returns:
 // - A pointer to a ClusterStatusChangeResponse.
-func NewClusterStatusChangeResponse(accountName string, clusterID string, region string, status inventory.InstanceStatus, instances []string, err error) *ClusterStatusChangeResponse {
+func NewClusterStatusChangeResponse(accountName string, clusterID string, region string, status inventory.InstanceStatus, instances []string, skipped []string, err error) *ClusterStatusChangeResponse {
 	if err == nil {
 		err = fmt.Errorf("")
 	}
+	if instances == nil {
+		instances = []string{}
+	}
+	if skipped == nil {
+		skipped = []string{}
+	}
 	return &ClusterStatusChangeResponse{
 		AccountName: accountName,
 		ClusterID:   clusterID,
@@ -282,6 +519,7 @@ func NewClusterStatusChangeResponse(accountName string, clusterID string, region
 		Status:      status,
 		Instances:   instances,
 		Error:       err.Error(),
+		Skipped:     skipped,
 	}
 }
 
@@ -302,3 +540,872 @@ func NewEventsListResponse(auditEvents []events.AuditEvent) *EventsListResponse
 	}
 	return &response
 }
+
+// AccountUtilizationResponse represents the API response containing the CPU
+// utilization rollup of every instance deployed on an account.
+// Averages are calculated using only running instances with a known CPU utilization.
+type AccountUtilizationResponse struct {
+	AccountName       string   `json:"account_name"`                  // The name of the account.
+	RunningInstances  int      `json:"running_instances"`             // Number of running instances.
+	MeasuredInstances int      `json:"measured_instances"`            // Number of running instances with a known CPU utilization.
+	StoppedInstances  int      `json:"stopped_instances"`             // Number of stopped instances, excluded from the averages.
+	IdleInstances     int      `json:"idle_instances"`                // Number of measured running instances considered idle.
+	AvgCPUUtilization *float64 `json:"avg_cpu_utilization,omitempty"` // Average CPU utilization of measured running instances. Omitted if none.
+	MinCPUUtilization *float64 `json:"min_cpu_utilization,omitempty"` // Minimum CPU utilization of measured running instances. Omitted if none.
+	MaxCPUUtilization *float64 `json:"max_cpu_utilization,omitempty"` // Maximum CPU utilization of measured running instances. Omitted if none.
+}
+
+// AccountSummaryResponse represents the API response containing the summary
+// of an account, without its nested clusters and instances
+type AccountSummaryResponse struct {
+	Name             string                           `json:"name"`             // The name of the account.
+	ClusterCount     int                              `json:"clusterCount"`     // Number of clusters on the account.
+	InstanceCount    int                              `json:"instanceCount"`    // Number of instances on the account.
+	InstancesByState map[inventory.InstanceStatus]int `json:"instancesByState"` // Number of instances indexed by status. Statuses without instances are omitted.
+	TotalCost        float64                          `json:"totalCost"`        // Total cost of the clusters of the account.
+	Currency         string                           `json:"currency"`         // Currency of the costs.
+}
+
+// NewAccountSummaryResponse creates a new AccountSummaryResponse instance.
+//
+// Parameters:
+// - accountName: The name of the account.
+// - clusters: A slice of inventory.Cluster belonging to the account.
+// - instances: A slice of inventory.Instance belonging to the account.
+//
+// Returns:
+// - A pointer to an AccountSummaryResponse.
+func NewAccountSummaryResponse(accountName string, clusters []inventory.Cluster, instances []inventory.Instance) *AccountSummaryResponse {
+	response := AccountSummaryResponse{
+		Name:             accountName,
+		ClusterCount:     len(clusters),
+		InstanceCount:    len(instances),
+		InstancesByState: make(map[inventory.InstanceStatus]int),
+		Currency:         inventory.CostCurrency,
+	}
+
+	for _, cluster := range clusters {
+		response.TotalCost += cluster.TotalCost
+	}
+	for _, instance := range instances {
+		response.InstancesByState[inventory.ProviderState(instance.Status).Status()]++
+	}
+
+	return &response
+}
+
+// NewAccountUtilizationResponse creates a new AccountUtilizationResponse instance.
+//
+// Parameters:
+// - accountName: The name of the account.
+// - instances: A slice of inventory.Instance belonging to the account.
+//
+// Returns:
+// - A pointer to an AccountUtilizationResponse.
+func NewAccountUtilizationResponse(accountName string, instances []inventory.Instance) *AccountUtilizationResponse {
+	response := AccountUtilizationResponse{
+		AccountName: accountName,
+	}
+
+	var totalUtilization, minUtilization, maxUtilization float64
+	for _, instance := range instances {
+		switch instance.Status {
+		case inventory.Stopped:
+			response.StoppedInstances++
+		case inventory.Running:
+			response.RunningInstances++
+			// Instances with an unknown utilization are left out of the rollup
+			if instance.CPUUtilization == nil {
+				continue
+			}
+			utilization := *instance.CPUUtilization
+			if response.MeasuredInstances == 0 || utilization < minUtilization {
+				minUtilization = utilization
+			}
+			if utilization > maxUtilization {
+				maxUtilization = utilization
+			}
+			if instance.IsIdle() {
+				response.IdleInstances++
+			}
+			totalUtilization += utilization
+			response.MeasuredInstances++
+		case inventory.Terminated:
+			continue
+		}
+	}
+
+	if response.MeasuredInstances > 0 {
+		avgUtilization := totalUtilization / float64(response.MeasuredInstances)
+		response.AvgCPUUtilization = &avgUtilization
+		response.MinCPUUtilization = &minUtilization
+		response.MaxCPUUtilization = &maxUtilization
+	}
+
+	return &response
+}
+
+// ScanCoverageResponse represents the API response comparing the expected accounts against the scanned ones
+type ScanCoverageResponse struct {
+	AllCovered         bool     `json:"all_covered"`         // True if every expected account was scanned and there are no unexpected accounts.
+	Message            string   `json:"message"`             // Human readable summary of the coverage.
+	MissingAccounts    []string `json:"missing_accounts"`    // Expected accounts not present in the inventory.
+	UnexpectedAccounts []string `json:"unexpected_accounts"` // Accounts present in the inventory but not expected.
+}
+
+// NewScanCoverageResponse creates a new ScanCoverageResponse instance.
+//
+// Parameters:
+// - expectedAccounts: Names of the accounts that should be scanned.
+// - accounts: A slice of inventory.Account present in the inventory.
+//
+// Returns:
+// - A pointer to a ScanCoverageResponse.
+func NewScanCoverageResponse(expectedAccounts []string, accounts []inventory.Account) *ScanCoverageResponse {
+	expected := make(map[string]bool, len(expectedAccounts))
+	for _, name := range expectedAccounts {
+		expected[name] = true
+	}
+
+	scanned := make(map[string]bool, len(accounts))
+	response := ScanCoverageResponse{
+		MissingAccounts:    []string{},
+		UnexpectedAccounts: []string{},
+	}
+	for _, account := range accounts {
+		scanned[account.Name] = true
+		if !expected[account.Name] {
+			response.UnexpectedAccounts = append(response.UnexpectedAccounts, account.Name)
+		}
+	}
+	for _, name := range expectedAccounts {
+		if !scanned[name] {
+			response.MissingAccounts = append(response.MissingAccounts, name)
+		}
+	}
+
+	response.AllCovered = len(response.MissingAccounts) == 0 && len(response.UnexpectedAccounts) == 0
+	if response.AllCovered {
+		response.Message = "all covered"
+	} else {
+		response.Message = fmt.Sprintf("%d missing and %d unexpected accounts", len(response.MissingAccounts), len(response.UnexpectedAccounts))
+	}
+
+	return &response
+}
+
+// SearchResponse represents the API response containing the clusters and instances matching a search query
+type SearchResponse struct {
+	Query     string               `json:"query"`           // Searched text.
+	Count     int                  `json:"count,omitempty"` // Number of results (clusters + instances), omitted if empty.
+	Clusters  []inventory.Cluster  `json:"clusters"`        // Matching clusters.
+	Instances []inventory.Instance `json:"instances"`       // Matching instances.
+}
+
+// NewSearchResponse creates a new SearchResponse instance.
+// It ensures that empty arrays are returned if there are no results.
+//
+// Parameters:
+// - query: The searched text.
+// - clusters: A slice of matching inventory.Cluster.
+// - instances: A slice of matching inventory.Instance.
+//
+// Returns:
+// - A pointer to a SearchResponse.
+func NewSearchResponse(query string, clusters []inventory.Cluster, instances []inventory.Instance) *SearchResponse {
+	if clusters == nil {
+		clusters = []inventory.Cluster{}
+	}
+	if instances == nil {
+		instances = []inventory.Instance{}
+	}
+
+	return &SearchResponse{
+		Query:     query,
+		Count:     len(clusters) + len(instances),
+		Clusters:  clusters,
+		Instances: instances,
+	}
+}
+
+// InventorySearchResponse represents the API response containing the accounts, clusters and instances matching a search
+type InventorySearchResponse struct {
+	Query     string               `json:"query"`               // Searched text.
+	Count     int                  `json:"count,omitempty"`     // Number of results (accounts + clusters + instances), omitted if empty.
+	Accounts  []inventory.Account  `json:"accounts"`            // Matching accounts.
+	Clusters  []inventory.Cluster  `json:"clusters"`            // Matching clusters.
+	Instances []inventory.Instance `json:"instances"`           // Matching instances.
+	Truncated bool                 `json:"truncated,omitempty"` // Set if any category was capped by the 'limit' param.
+}
+
+// NewInventorySearchResponse creates a new InventorySearchResponse instance.
+// Every category is capped to limit results, and empty arrays are returned
+// for the categories without results.
+//
+// Parameters:
+// - query: The searched text.
+// - limit: Maximum number of results of every category.
+// - accounts: A slice of matching inventory.Account.
+// - clusters: A slice of matching inventory.Cluster.
+// - instances: A slice of matching inventory.Instance.
+//
+// Returns:
+// - A pointer to an InventorySearchResponse.
+func NewInventorySearchResponse(query string, limit int, accounts []inventory.Account, clusters []inventory.Cluster, instances []inventory.Instance) *InventorySearchResponse {
+	truncated := len(accounts) > limit || len(clusters) > limit || len(instances) > limit
+	accounts = paginate(accounts, limit, 0)
+	clusters = paginate(clusters, limit, 0)
+	instances = paginate(instances, limit, 0)
+
+	if accounts == nil {
+		accounts = []inventory.Account{}
+	}
+	if clusters == nil {
+		clusters = []inventory.Cluster{}
+	}
+	if instances == nil {
+		instances = []inventory.Instance{}
+	}
+
+	return &InventorySearchResponse{
+		Query:     query,
+		Count:     len(accounts) + len(clusters) + len(instances),
+		Accounts:  accounts,
+		Clusters:  clusters,
+		Instances: instances,
+		Truncated: truncated,
+	}
+}
+
+// unassignedOwner groups the instances without Owner tag
+const unassignedOwner = "unassigned"
+
+// OwnerCostSummary represents the number of instances and their total cost for a single owner
+type OwnerCostSummary struct {
+	Owner         string  `json:"owner"`          // Value of the Owner tag, or "unassigned".
+	InstanceCount int     `json:"instance_count"` // Number of instances of the owner.
+	TotalCost     float64 `json:"total_cost"`     // Total cost (US Dollars) of the owner's instances.
+}
+
+// ClusterCostResponse represents the API response containing the cost of a cluster on a billing window
+type ClusterCostResponse struct {
+	Cluster     string                `json:"cluster"`        // Cluster ID.
+	From        *time.Time            `json:"from,omitempty"` // First day of the window (included). Omitted if unlimited.
+	To          *time.Time            `json:"to,omitempty"`   // Last day of the window (excluded). Omitted if unlimited.
+	Currency    string                `json:"currency"`       // Currency of every cost.
+	TotalCost   float64               `json:"totalCost"`      // Cost of every instance of the cluster on the window.
+	PerInstance []models.InstanceCost `json:"perInstance"`    // Cost of each instance, sorted by name.
+}
+
+// NewClusterCostResponse creates a new ClusterCostResponse instance.
+// It ensures that an empty array is returned if the cluster has no instances.
+//
+// Parameters:
+// - clusterID: The ID of the cluster.
+// - from: First day of the window, nil if unlimited.
+// - to: Last day of the window, nil if unlimited.
+// - costs: The cost of every instance of the cluster.
+//
+// Returns:
+// - A pointer to a ClusterCostResponse.
+func NewClusterCostResponse(clusterID string, from, to *time.Time, costs []models.InstanceCost) *ClusterCostResponse {
+	// If there is no instances, an empty array is returned instead of null
+	if len(costs) == 0 {
+		costs = []models.InstanceCost{}
+	}
+
+	response := ClusterCostResponse{
+		Cluster:     clusterID,
+		From:        from,
+		To:          to,
+		Currency:    inventory.CostCurrency,
+		PerInstance: costs,
+	}
+	for _, cost := range costs {
+		response.TotalCost += cost.Cost
+	}
+	return &response
+}
+
+// ProviderListResponse represents the API response containing the cloud providers found on the inventory
+type ProviderListResponse struct {
+	Count     int                     `json:"count,omitempty"` // Number of providers, omitted if empty.
+	Providers []models.ProviderCounts `json:"providers"`       // Providers sorted by name.
+}
+
+// NewProviderListResponse creates a new ProviderListResponse instance.
+// It ensures that an empty array is returned if the input provider list is empty.
+//
+// Parameters:
+// - providers: A slice of models.ProviderCounts.
+//
+// Returns:
+// - A pointer to a ProviderListResponse.
+func NewProviderListResponse(providers []models.ProviderCounts) *ProviderListResponse {
+	// If there is no providers, an empty array is returned instead of null
+	if len(providers) == 0 {
+		providers = []models.ProviderCounts{}
+	}
+	return &ProviderListResponse{Count: len(providers), Providers: providers}
+}
+
+// InstancesByOwnerResponse represents the API response containing the instances count and cost per owner
+type InstancesByOwnerResponse struct {
+	Count  int                `json:"count,omitempty"` // Number of owners, omitted if empty.
+	Owners []OwnerCostSummary `json:"owners"`          // Owners sorted by total cost descending.
+}
+
+// NewInstancesByOwnerResponse creates a new InstancesByOwnerResponse instance.
+// Instances are grouped by the Owner tag, and those without it are grouped
+// under "unassigned". Owners are sorted by total cost descending.
+//
+// Parameters:
+// - instances: A slice of inventory.Instance including their tags.
+//
+// Returns:
+// - A pointer to an InstancesByOwnerResponse.
+func NewInstancesByOwnerResponse(instances []inventory.Instance) *InstancesByOwnerResponse {
+	summaries := make(map[string]*OwnerCostSummary)
+	for _, instance := range instances {
+		owner := strings.TrimSpace(inventory.GetOwnerFromTags(instance.Tags))
+		if owner == "" {
+			owner = unassignedOwner
+		}
+
+		summary, ok := summaries[owner]
+		if !ok {
+			summary = &OwnerCostSummary{Owner: owner}
+			summaries[owner] = summary
+		}
+		summary.InstanceCount++
+		summary.TotalCost += instance.TotalCost
+	}
+
+	owners := make([]OwnerCostSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		owners = append(owners, *summary)
+	}
+	slices.SortFunc(owners, func(a, b OwnerCostSummary) int {
+		if c := cmp.Compare(b.TotalCost, a.TotalCost); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Owner, b.Owner)
+	})
+
+	response := InstancesByOwnerResponse{
+		Owners: owners,
+	}
+	// If there is more than one owner, the response contains a 'count' field
+	if len(owners) > 1 {
+		response.Count = len(owners)
+	}
+
+	return &response
+}
+
+// RequestStatsResponse represents the API response containing the internal request counters
+type RequestStatsResponse struct {
+	Since         time.Time        `json:"since"`          // When the counters started.
+	TotalRequests int64            `json:"total_requests"` // Total number of requests.
+	ClientErrors  int64            `json:"client_errors"`  // Number of 4xx responses.
+	ServerErrors  int64            `json:"server_errors"`  // Number of 5xx responses.
+	Routes        map[string]int64 `json:"routes"`         // Number of requests per route ("METHOD /path").
+}
+
+// CostOutlier represents an instance whose cost is anomalously high compared to the instances of its account
+type CostOutlier struct {
+	Instance          inventory.Instance `json:"instance"`             // The outlier instance.
+	AccountName       string             `json:"account_name"`         // Account of the instance.
+	AccountMeanCost   float64            `json:"account_mean_cost"`    // Mean instance cost of the account.
+	AccountCostStdDev float64            `json:"account_cost_std_dev"` // Standard deviation of the instance costs of the account.
+	Deviation         float64            `json:"deviation"`            // Number of standard deviations above the mean.
+}
+
+// CostOutliersResponse represents the API response containing the instances cost outliers
+type CostOutliersResponse struct {
+	Count      int           `json:"count,omitempty"` // Number of outliers, omitted if empty.
+	Deviations float64       `json:"deviations"`      // Threshold used, in standard deviations above the mean.
+	Outliers   []CostOutlier `json:"outliers"`        // Outliers sorted by deviation descending.
+}
+
+// NewCostOutliersResponse creates a new CostOutliersResponse instance.
+//
+// Parameters:
+// - deviations: Threshold used for finding the outliers.
+// - outliers: A slice of CostOutlier.
+//
+// Returns:
+// - A pointer to a CostOutliersResponse.
+func NewCostOutliersResponse(deviations float64, outliers []CostOutlier) *CostOutliersResponse {
+	if outliers == nil {
+		outliers = []CostOutlier{}
+	}
+
+	response := CostOutliersResponse{
+		Deviations: deviations,
+		Outliers:   outliers,
+	}
+	// If there is more than one outlier, the response contains a 'count' field
+	if len(outliers) > 1 {
+		response.Count = len(outliers)
+	}
+
+	return &response
+}
+
+// ClusterInstancesFilterResponse represents the API response of the instances lookup within a cluster
+type ClusterInstancesFilterResponse struct {
+	ClusterID string               `json:"cluster_id"`      // Cluster ID.
+	Count     int                  `json:"count,omitempty"` // Number of instances, omitted if empty.
+	Instances []inventory.Instance `json:"instances"`       // Requested instances belonging to the cluster.
+	Excluded  []string             `json:"excluded"`        // Requested instance IDs not belonging to the cluster.
+}
+
+// NewClusterInstancesFilterResponse creates a new ClusterInstancesFilterResponse instance.
+// Every requested ID not found on the instances is reported as excluded,
+// keeping the requested order and ignoring duplicates.
+//
+// Parameters:
+// - clusterID: Cluster ID.
+// - requested: Requested instance IDs.
+// - instances: Requested instances belonging to the cluster.
+//
+// Returns:
+// - A pointer to a ClusterInstancesFilterResponse.
+func NewClusterInstancesFilterResponse(clusterID string, requested []string, instances []inventory.Instance) *ClusterInstancesFilterResponse {
+	if instances == nil {
+		instances = []inventory.Instance{}
+	}
+
+	seen := make(map[string]bool, len(requested))
+	for _, instance := range instances {
+		seen[instance.ID] = true
+	}
+	excluded := make([]string, 0)
+	for _, id := range requested {
+		if !seen[id] {
+			seen[id] = true
+			excluded = append(excluded, id)
+		}
+	}
+
+	response := ClusterInstancesFilterResponse{
+		ClusterID: clusterID,
+		Instances: instances,
+		Excluded:  excluded,
+	}
+	// If there is more than one instance, the response contains a 'count' field
+	if len(instances) > 1 {
+		response.Count = len(instances)
+	}
+
+	return &response
+}
+
+// ClusterBatchResponse represents the API response of the clusters lookup by name
+type ClusterBatchResponse struct {
+	ClusterListResponse
+	Missing []string `json:"missing"` // Requested names without any cluster.
+}
+
+// NewClusterBatchResponse creates a new ClusterBatchResponse instance.
+// Every requested name not found on the clusters is reported as missing,
+// keeping the requested order and ignoring duplicates.
+//
+// Parameters:
+// - requested: Requested cluster names.
+// - clusters: Clusters named like any of the requested names.
+//
+// Returns:
+// - A pointer to a ClusterBatchResponse.
+func NewClusterBatchResponse(requested []string, clusters []inventory.Cluster) *ClusterBatchResponse {
+	seen := make(map[string]bool, len(requested))
+	for _, cluster := range clusters {
+		seen[cluster.Name] = true
+	}
+	missing := make([]string, 0)
+	for _, name := range requested {
+		if !seen[name] {
+			seen[name] = true
+			missing = append(missing, name)
+		}
+	}
+
+	response := ClusterBatchResponse{
+		ClusterListResponse: *NewClusterListResponse(clusters),
+		Missing:             missing,
+	}
+	response.Total = len(clusters)
+
+	return &response
+}
+
+// InventoryValidationResponse represents the API response containing the issues of a validated inventory
+type InventoryValidationResponse struct {
+	Valid  bool     `json:"valid"`  // Set if the inventory has no issues.
+	Issues []string `json:"issues"` // Issues found on the inventory.
+}
+
+// NewInventoryValidationResponse creates a new InventoryValidationResponse instance.
+//
+// Parameters:
+// - issues: The issues found on the inventory.
+//
+// Returns:
+// - A pointer to an InventoryValidationResponse.
+func NewInventoryValidationResponse(issues []string) *InventoryValidationResponse {
+	// If there are no issues, an empty array is returned instead of null
+	if issues == nil {
+		issues = []string{}
+	}
+
+	return &InventoryValidationResponse{
+		Valid:  len(issues) == 0,
+		Issues: issues,
+	}
+}
+
+// InstanceStateStats represents the number and total cost of a group of instances
+type InstanceStateStats struct {
+	Count     int     `json:"count"`     // Number of instances.
+	TotalCost float64 `json:"totalCost"` // Total cost of the instances.
+}
+
+// InstanceStateStatsResponse represents the API response containing the instances stats by status
+type InstanceStateStatsResponse struct {
+	States map[inventory.InstanceStatus]InstanceStateStats `json:"states"` // Stats indexed by status. Statuses without instances are omitted.
+	Total  InstanceStateStats                              `json:"total"`  // Stats of all the instances.
+}
+
+// NewInstanceStateStatsResponse creates a new InstanceStateStatsResponse instance.
+//
+// Parameters:
+// - states: The stats indexed by status.
+// - total: The stats of all the instances.
+//
+// Returns:
+// - A pointer to an InstanceStateStatsResponse.
+func NewInstanceStateStatsResponse(states map[inventory.InstanceStatus]InstanceStateStats, total InstanceStateStats) *InstanceStateStatsResponse {
+	if states == nil {
+		states = map[inventory.InstanceStatus]InstanceStateStats{}
+	}
+
+	return &InstanceStateStatsResponse{
+		States: states,
+		Total:  total,
+	}
+}
+
+// RegionStats represents the instances and costs of a region
+type RegionStats struct {
+	Region           string  `json:"region"`            // Region name. Empty for the instances without availability zone.
+	Instances        int     `json:"instances"`         // Number of instances.
+	RunningInstances int     `json:"running_instances"` // Number of running instances.
+	TotalCost        float64 `json:"total_cost"`        // Total cost of the instances.
+}
+
+// RegionStatsResponse represents the API response containing the per region stats
+type RegionStatsResponse struct {
+	Count   int           `json:"count,omitempty"` // Number of regions, omitted if empty.
+	Regions []RegionStats `json:"regions"`         // Regions sorted by total cost descending.
+}
+
+// NewRegionStatsResponse creates a new RegionStatsResponse instance.
+//
+// Parameters:
+// - regions: A slice of RegionStats.
+//
+// Returns:
+// - A pointer to a RegionStatsResponse.
+func NewRegionStatsResponse(regions []RegionStats) *RegionStatsResponse {
+	if regions == nil {
+		regions = []RegionStats{}
+	}
+
+	response := RegionStatsResponse{
+		Regions: regions,
+	}
+	// If there is more than one region, the response contains a 'count' field
+	if len(regions) > 1 {
+		response.Count = len(regions)
+	}
+
+	return &response
+}
+
+// RegionSummary represents the instances and costs of a region of a provider
+type RegionSummary struct {
+	Region        string                  `json:"region"`        // Region name. "unknown" for the instances without availability zone.
+	Provider      inventory.CloudProvider `json:"provider"`      // Provider of the region.
+	InstanceCount int                     `json:"instanceCount"` // Number of instances.
+	RunningCount  int                     `json:"runningCount"`  // Number of running instances.
+	TotalCost     float64                 `json:"totalCost"`     // Total cost of the instances.
+}
+
+// RegionListResponse represents the API response containing the regions of the inventory
+type RegionListResponse struct {
+	Count   int             `json:"count,omitempty"` // Number of regions, omitted if empty.
+	Regions []RegionSummary `json:"regions"`         // Regions sorted by name and provider.
+}
+
+// NewRegionListResponse creates a new RegionListResponse instance.
+//
+// Parameters:
+// - regions: A slice of RegionSummary.
+//
+// Returns:
+// - A pointer to a RegionListResponse.
+func NewRegionListResponse(regions []RegionSummary) *RegionListResponse {
+	// If there is no regions, an empty array is returned instead of null
+	if regions == nil {
+		regions = []RegionSummary{}
+	}
+
+	response := RegionListResponse{
+		Regions: regions,
+	}
+	// If there is more than one region, the response contains a 'count' field
+	if len(regions) > 1 {
+		response.Count = len(regions)
+	}
+
+	return &response
+}
+
+// CostDimensionsResponse represents the API response containing the configured cost dimensions
+type CostDimensionsResponse struct {
+	// Tag key of every dimension, indexed by dimension and provider.
+	Dimensions inventory.CostDimensions `json:"dimensions"`
+}
+
+// DimensionCost represents the aggregated cost of a cost dimension value
+type DimensionCost struct {
+	Value     string  `json:"value"`      // Dimension value. Empty for the instances without it.
+	TotalCost float64 `json:"total_cost"` // Total cost of the instances with this value.
+	Instances int     `json:"instances"`  // Number of instances with this value.
+}
+
+// CostByDimensionResponse represents the API response containing the cost aggregated by a cost dimension
+type CostByDimensionResponse struct {
+	Dimension string          `json:"dimension"`       // Name of the dimension.
+	Currency  string          `json:"currency"`        // Currency of every cost.
+	Count     int             `json:"count,omitempty"` // Number of values, omitted if empty.
+	Values    []DimensionCost `json:"values"`          // Costs sorted by total cost descending.
+}
+
+// NewCostByDimensionResponse creates a new CostByDimensionResponse instance.
+//
+// Parameters:
+// - dimension: Name of the dimension.
+// - values: A slice of DimensionCost.
+//
+// Returns:
+// - A pointer to a CostByDimensionResponse.
+func NewCostByDimensionResponse(dimension string, values []DimensionCost) *CostByDimensionResponse {
+	if values == nil {
+		values = []DimensionCost{}
+	}
+
+	response := CostByDimensionResponse{
+		Dimension: dimension,
+		Currency:  inventory.CostCurrency,
+		Values:    values,
+	}
+	// If there is more than one value, the response contains a 'count' field
+	if len(values) > 1 {
+		response.Count = len(values)
+	}
+
+	return &response
+}
+
+// InstanceCostExport represents the costs of an instance on the cost export
+type InstanceCostExport struct {
+	ID           string  `json:"id"`            // Instance ID.
+	Name         string  `json:"name"`          // Instance name.
+	InstanceType string  `json:"instance_type"` // Instance type/size.
+	TotalCost    float64 `json:"total_cost"`    // Total cost of the instance.
+	DailyCost    float64 `json:"daily_cost"`    // Average daily cost of the instance.
+	// Values of the requested tag columns, indexed by tag key. Missing tags are omitted.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// ClusterCostExport represents the costs of a cluster and its instances on the cost export
+type ClusterCostExport struct {
+	ID                    string               `json:"id"`                        // Cluster ID.
+	Name                  string               `json:"name"`                      // Cluster name.
+	TotalCost             float64              `json:"total_cost"`                // Total cost of the cluster.
+	Last15DaysCost        float64              `json:"last_15_days_cost"`         // Cost of the last 15 days.
+	LastMonthCost         float64              `json:"last_month_cost"`           // Cost of the last month.
+	CurrentMonthSoFarCost float64              `json:"current_month_so_far_cost"` // Cost of the current month so far.
+	Instances             []InstanceCostExport `json:"instances"`                 // Costs of the cluster instances.
+}
+
+// AccountCostExport represents the costs of an account and its clusters on the cost export
+type AccountCostExport struct {
+	Name                  string              `json:"name"`                      // Account name.
+	Provider              string              `json:"provider"`                  // Cloud provider of the account.
+	TotalCost             float64             `json:"total_cost"`                // Total cost of the account.
+	Last15DaysCost        float64             `json:"last_15_days_cost"`         // Cost of the last 15 days.
+	LastMonthCost         float64             `json:"last_month_cost"`           // Cost of the last month.
+	CurrentMonthSoFarCost float64             `json:"current_month_so_far_cost"` // Cost of the current month so far.
+	Clusters              []ClusterCostExport `json:"clusters"`                  // Costs of the account clusters.
+}
+
+// CostExportResponse represents the API response containing the costs of every account, rolled up by cluster and instance
+type CostExportResponse struct {
+	Currency   string              `json:"currency"`              // Currency of every cost on the export.
+	TagColumns []string            `json:"tag_columns,omitempty"` // Tag keys exported for every instance.
+	Count      int                 `json:"count,omitempty"`       // Number of accounts, omitted if empty.
+	Accounts   []AccountCostExport `json:"accounts"`              // Accounts with their costs.
+}
+
+// NewCostExportResponse creates a new CostExportResponse instance.
+// Clusters are linked to their account by AccountName and instances to their
+// cluster by ClusterID. Resources whose parent is not on the export are ignored.
+//
+// Parameters:
+// - accounts: A slice of inventory.Account.
+// - clusters: A slice of inventory.Cluster.
+// - instances: A slice of inventory.Instance.
+//
+// Returns:
+// - A pointer to a CostExportResponse.
+func NewCostExportResponse(accounts []inventory.Account, clusters []inventory.Cluster, instances []inventory.Instance) *CostExportResponse {
+	instancesByCluster := make(map[string][]InstanceCostExport)
+	for _, instance := range instances {
+		instancesByCluster[instance.ClusterID] = append(instancesByCluster[instance.ClusterID], InstanceCostExport{
+			ID:           instance.ID,
+			Name:         instance.Name,
+			InstanceType: instance.InstanceType,
+			TotalCost:    instance.TotalCost,
+			DailyCost:    instance.DailyCost,
+		})
+	}
+
+	clustersByAccount := make(map[string][]ClusterCostExport)
+	for _, cluster := range clusters {
+		clusterInstances := instancesByCluster[cluster.ID]
+		if clusterInstances == nil {
+			clusterInstances = []InstanceCostExport{}
+		}
+		clustersByAccount[cluster.AccountName] = append(clustersByAccount[cluster.AccountName], ClusterCostExport{
+			ID:                    cluster.ID,
+			Name:                  cluster.Name,
+			TotalCost:             cluster.TotalCost,
+			Last15DaysCost:        cluster.Last15DaysCost,
+			LastMonthCost:         cluster.LastMonthCost,
+			CurrentMonthSoFarCost: cluster.CurrentMonthSoFarCost,
+			Instances:             clusterInstances,
+		})
+	}
+
+	exported := make([]AccountCostExport, 0, len(accounts))
+	for _, account := range accounts {
+		accountClusters := clustersByAccount[account.Name]
+		if accountClusters == nil {
+			accountClusters = []ClusterCostExport{}
+		}
+		exported = append(exported, AccountCostExport{
+			Name:                  account.Name,
+			Provider:              string(account.Provider),
+			TotalCost:             account.TotalCost,
+			Last15DaysCost:        account.Last15DaysCost,
+			LastMonthCost:         account.LastMonthCost,
+			CurrentMonthSoFarCost: account.CurrentMonthSoFarCost,
+			Clusters:              accountClusters,
+		})
+	}
+
+	response := CostExportResponse{
+		Currency: inventory.CostCurrency,
+		Accounts: exported,
+	}
+	// If there is more than one account, the response contains a 'count' field
+	if len(exported) > 1 {
+		response.Count = len(exported)
+	}
+
+	return &response
+}
+
+// SetTagColumns includes the values of the given tag keys on every instance of the export.
+//
+// Parameters:
+// - keys: Tag keys to export.
+// - tags: Instance tags, linked to their instance by InstanceID.
+func (r *CostExportResponse) SetTagColumns(keys []string, tags []inventory.Tag) {
+	values := make(map[string]map[string]string)
+	for _, tag := range tags {
+		if values[tag.InstanceID] == nil {
+			values[tag.InstanceID] = make(map[string]string)
+		}
+		values[tag.InstanceID][tag.Key] = tag.Value
+	}
+
+	r.TagColumns = keys
+	for _, account := range r.Accounts {
+		for _, cluster := range account.Clusters {
+			for i := range cluster.Instances {
+				cluster.Instances[i].Tags = values[cluster.Instances[i].ID]
+			}
+		}
+	}
+}
+
+// AccountScanInfoResponse represents the API response containing the timing and rate limiting context of the last scan of an account
+type AccountScanInfoResponse struct {
+	AccountName       string    `json:"account_name"`       // Name of the account.
+	ScanStart         time.Time `json:"scan_start"`         // Timestamp when the last scan started.
+	ScanEnd           time.Time `json:"scan_end"`           // Timestamp when the last scan finished.
+	DurationSeconds   float64   `json:"duration_seconds"`   // Duration of the last scan. Zero if the timing is unknown.
+	Throttled         bool      `json:"throttled"`          // Whether the provider API throttled any request.
+	ThrottledRequests int       `json:"throttled_requests"` // Number of throttled requests.
+}
+
+// NewAccountScanInfoResponse creates a new AccountScanInfoResponse instance.
+//
+// Parameters:
+// - account: Account to report.
+//
+// Returns:
+// - A pointer to an AccountScanInfoResponse.
+func NewAccountScanInfoResponse(account inventory.Account) *AccountScanInfoResponse {
+	return &AccountScanInfoResponse{
+		AccountName:       account.Name,
+		ScanStart:         account.ScanStartTimestamp,
+		ScanEnd:           account.ScanEndTimestamp,
+		DurationSeconds:   account.LastScanDuration().Seconds(),
+		Throttled:         account.ThrottledRequests > 0,
+		ThrottledRequests: account.ThrottledRequests,
+	}
+}
+
+// RequestAuditResponse represents the API response containing the most recent entries of the requests audit trail
+type RequestAuditResponse struct {
+	Count   int                        `json:"count,omitempty"` // Number of entries, omitted if empty.
+	Entries []events.RequestAuditEntry `json:"entries"`         // Audit entries, newest first.
+}
+
+// NewRequestAuditResponse creates a new RequestAuditResponse instance.
+//
+// Parameters:
+// - entries: A slice of events.RequestAuditEntry.
+//
+// Returns:
+// - A pointer to a RequestAuditResponse.
+func NewRequestAuditResponse(entries []events.RequestAuditEntry) *RequestAuditResponse {
+	if entries == nil {
+		entries = []events.RequestAuditEntry{}
+	}
+
+	response := RequestAuditResponse{
+		Entries: entries,
+	}
+	// If there is more than one entry, the response contains a 'count' field
+	if len(entries) > 1 {
+		response.Count = len(entries)
+	}
+
+	return &response
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+)
+
+// TestNewAccountUtilizationResponse verifies the instances with an unknown CPU utilization are left out of the rollup and the idle count
+func TestNewAccountUtilizationResponse(t *testing.T) {
+	utilization := func(u float64) *float64 { return &u }
+	instances := []inventory.Instance{
+		{ID: "i-1", Status: inventory.Running, CPUUtilization: utilization(2.0)},
+		{ID: "i-2", Status: inventory.Running, CPUUtilization: utilization(40.0)},
+		{ID: "i-3", Status: inventory.Running},
+		{ID: "i-4", Status: inventory.Stopped, CPUUtilization: utilization(0.0)},
+	}
+
+	response := NewAccountUtilizationResponse("acc", instances)
+	if response.RunningInstances != 3 || response.MeasuredInstances != 2 || response.StoppedInstances != 1 {
+		t.Errorf("expected 3 running, 2 measured and 1 stopped instances, got %d, %d and %d",
+			response.RunningInstances, response.MeasuredInstances, response.StoppedInstances)
+	}
+	if response.IdleInstances != 1 {
+		t.Errorf("expected 1 idle instance, got %d", response.IdleInstances)
+	}
+	for name, tt := range map[string]struct {
+		value    *float64
+		expected float64
+	}{
+		"avg": {response.AvgCPUUtilization, 21.0},
+		"min": {response.MinCPUUtilization, 2.0},
+		"max": {response.MaxCPUUtilization, 40.0},
+	} {
+		if tt.value == nil || *tt.value != tt.expected {
+			t.Errorf("expected %s CPU utilization %v, got %v", name, tt.expected, tt.value)
+		}
+	}
+
+	// Without measured instances there's no rollup, and no idle instances
+	response = NewAccountUtilizationResponse("acc", instances[2:])
+	if response.AvgCPUUtilization != nil || response.MinCPUUtilization != nil || response.MaxCPUUtilization != nil {
+		t.Errorf("expected no CPU utilization rollup, got %v/%v/%v",
+			response.AvgCPUUtilization, response.MinCPUUtilization, response.MaxCPUUtilization)
+	}
+	if response.IdleInstances != 0 {
+		t.Errorf("expected no idle instances, got %d", response.IdleInstances)
+	}
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"net/http/pprof"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,6 +20,18 @@ func NewRouter(api *APIServer) *Router {
 }
 
 func (r *Router) SetupRoutes() {
+	// Prometheus metrics, served on the root as expected by the scrapers
+	r.engine.GET("/metrics", r.api.HandlerMetrics)
+
+	// OpenAPI spec and its interactive documentation
+	r.engine.GET("/openapi.json", r.api.HandlerOpenAPI)
+	r.engine.GET("/docs", r.api.HandlerDocs)
+
+	// Go runtime profiles, only for debugging
+	if r.api.cfg.EnablePprof {
+		r.setupPprofRoutes()
+	}
+
 	// API Endpoints
 	baseGroup := r.engine.Group("/api/v1")
 	r.setupHealthcheckRoutes(baseGroup)
@@ -27,12 +42,52 @@ func (r *Router) SetupRoutes() {
 	r.setupAccountsRoutes(baseGroup)
 	r.setupEventsRoutes(baseGroup)
 	r.setupOverviewRoutes(baseGroup)
+	r.setupProvidersRoutes(baseGroup)
+	r.setupRegionsRoutes(baseGroup)
+	r.setupTagsRoutes(baseGroup)
+	r.setupSearchRoutes(baseGroup)
 	r.setupInventoryRoutes(baseGroup)
+	r.setupScanRoutes(baseGroup)
+	r.setupExportRoutes(baseGroup)
+	r.setupStatsRoutes(baseGroup)
+	r.setupDebugRoutes(baseGroup)
+	r.setupAuditRoutes(baseGroup)
+}
+
+// pprofRoutesPattern matches the routes of the Go runtime profiles
+const pprofRoutesPattern = "/debug/pprof/*"
+
+// setupPprofRoutes serves the net/http/pprof handlers under /debug/pprof/, on
+// the root as expected by 'go tool pprof'
+func (r *Router) setupPprofRoutes() {
+	pprofGroup := r.engine.Group("/debug/pprof")
+	pprofGroup.GET("/", gin.WrapF(pprof.Index))
+	pprofGroup.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	pprofGroup.GET("/profile", gin.WrapF(pprof.Profile))
+	pprofGroup.GET("/symbol", gin.WrapF(pprof.Symbol))
+	pprofGroup.POST("/symbol", skipAudit, gin.WrapF(pprof.Symbol))
+	pprofGroup.GET("/trace", gin.WrapF(pprof.Trace))
+	for _, profile := range []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"} {
+		pprofGroup.GET("/"+profile, gin.WrapH(pprof.Handler(profile)))
+	}
+}
+
+// listCache returns the Cache-Control middleware of the list endpoints
+func (r *Router) listCache() gin.HandlerFunc {
+	return middleware.CacheControl(r.api.cfg.HTTPCacheMaxAge, r.api.isInventoryStale)
+}
+
+// inventoryAge returns the X-Inventory-Age middleware of the list endpoints
+func (r *Router) inventoryAge() gin.HandlerFunc {
+	return middleware.InventoryAge(r.api.sql.GetScannerLastScanTimestamp)
 }
 
 func (r *Router) setupHealthcheckRoutes(baseGroup *gin.RouterGroup) {
 	healthcheckGroup := baseGroup.Group("/healthcheck")
 	healthcheckGroup.GET("", r.api.HandlerHealthCheck)
+	baseGroup.GET("/healthz", r.api.HandlerLiveness)
+	baseGroup.GET("/readyz", r.api.HandlerReadiness)
+	baseGroup.GET("/version", r.api.HandlerVersion)
 }
 
 func (r *Router) setupScheduledActionsRoutes(baseGroup *gin.RouterGroup) {
@@ -49,29 +104,45 @@ func (r *Router) setupScheduledActionsRoutes(baseGroup *gin.RouterGroup) {
 
 func (r *Router) setupExpensesRoutes(baseGroup *gin.RouterGroup) {
 	expensesGroup := baseGroup.Group("/expenses")
-	expensesGroup.GET("", r.api.HandlerGetExpenses)
+	expensesGroup.GET("", r.listCache(), r.inventoryAge(), middleware.ETag(), r.api.HandlerGetExpenses)
+	expensesGroup.GET("/dimensions", r.api.HandlerGetCostDimensions)
+	expensesGroup.GET("/dimensions/:dimension", r.api.HandlerGetCostByDimension)
 	expensesGroup.GET("/:instance_id", r.api.HandlerGetExpensesByInstance)
 	expensesGroup.POST("", r.api.HandlerPostExpense)
 }
 
 func (r *Router) setupInstancesRoutes(baseGroup *gin.RouterGroup) {
 	instancesGroup := baseGroup.Group("/instances")
-	instancesGroup.GET("", r.api.HandlerGetInstances)
+	instancesGroup.Use(middleware.SparseFields(fieldsParam, excludeParam))
+	instancesGroup.GET("", r.listCache(), r.inventoryAge(), middleware.ETag(), r.api.HandlerGetInstances)
+	instancesGroup.HEAD("", r.listCache(), r.inventoryAge(), r.api.HandlerGetInstances)
 	instancesGroup.GET("/expense_update", r.api.HandlerGetInstancesForBillingUpdate)
+	instancesGroup.GET("/by-owner", r.api.HandlerGetInstancesByOwner)
+	instancesGroup.GET("/cost-outliers", r.api.HandlerGetInstancesCostOutliers)
+	instancesGroup.GET("/stats", r.api.HandlerGetInstancesStats)
+	instancesGroup.GET("/older-than/:days", r.listCache(), r.inventoryAge(), middleware.ETag(), r.api.HandlerGetInstancesOlderThan)
 	instancesGroup.GET("/:instance_id", r.api.HandlerGetInstanceByID)
 	instancesGroup.POST("", r.api.HandlerPostInstance)
 	instancesGroup.DELETE("/:instance_id", r.api.HandlerDeleteInstance)
 	instancesGroup.PATCH("/:instance_id", r.api.HandlerPatchInstance)
+	instancesGroup.PUT("/:instance_id/protect", r.api.HandlerProtectInstance)
+	instancesGroup.PUT("/:instance_id/unprotect", r.api.HandlerUnprotectInstance)
 }
 
 func (r *Router) setupClustersRoutes(baseGroup *gin.RouterGroup) {
 	clustersGroup := baseGroup.Group("/clusters")
-	clustersGroup.GET("", r.api.HandlerGetClusters)
+	clustersGroup.Use(middleware.SparseFields(fieldsParam, excludeParam))
+	clustersGroup.GET("", r.listCache(), r.inventoryAge(), middleware.ETag(), r.api.HandlerGetClusters)
+	clustersGroup.HEAD("", r.listCache(), r.inventoryAge(), r.api.HandlerGetClusters)
 	clustersGroup.GET("/:cluster_id", r.api.HandlerGetClustersByID)
-	clustersGroup.GET("/:cluster_id/instances", r.api.HandlerGetInstancesOnCluster)
+	clustersGroup.GET("/:cluster_id/instances", r.listCache(), r.inventoryAge(), middleware.ETag(), r.api.HandlerGetInstancesOnCluster)
 	clustersGroup.GET("/:cluster_id/tags", r.api.HandlerGetClusterTags)
+	clustersGroup.GET("/:cluster_id/cost", r.api.HandlerGetClusterCost)
 	clustersGroup.GET("/:cluster_id/events", r.api.HandlerGetClusterEvents)
 	clustersGroup.POST("", r.api.HandlerPostCluster)
+	clustersGroup.POST("/schedule", r.api.HandlerPostClustersSchedule)
+	clustersGroup.POST("/batch", skipAudit, r.api.HandlerPostClustersBatch)
+	clustersGroup.POST("/:cluster_id/instances/filter", skipAudit, r.api.HandlerFilterInstancesOnCluster)
 	clustersGroup.POST("/:cluster_id/power_on", r.api.HandlerPowerOnCluster)
 	clustersGroup.POST("/:cluster_id/power_off", r.api.HandlerPowerOffCluster)
 	clustersGroup.DELETE("/:cluster_id", r.api.HandlerDeleteCluster)
@@ -80,9 +151,14 @@ func (r *Router) setupClustersRoutes(baseGroup *gin.RouterGroup) {
 
 func (r *Router) setupAccountsRoutes(baseGroup *gin.RouterGroup) {
 	accountsGroup := baseGroup.Group("/accounts")
-	accountsGroup.GET("", r.api.HandlerGetAccounts)
+	accountsGroup.GET("", r.listCache(), r.inventoryAge(), middleware.ETag(), r.api.HandlerGetAccounts)
+	accountsGroup.HEAD("", r.listCache(), r.inventoryAge(), r.api.HandlerGetAccounts)
 	accountsGroup.GET("/:account_name", r.api.HandlerGetAccountsByName)
-	accountsGroup.GET("/:account_name/clusters", r.api.HandlerGetClustersOnAccount)
+	accountsGroup.GET("/:account_name/clusters", r.listCache(), r.inventoryAge(), middleware.ETag(), r.api.HandlerGetClustersOnAccount)
+	accountsGroup.GET("/:account_name/summary", r.api.HandlerGetAccountSummary)
+	accountsGroup.GET("/:account_name/utilization", r.api.HandlerGetAccountUtilization)
+	accountsGroup.GET("/:account_name/search", r.api.HandlerSearchOnAccount)
+	accountsGroup.GET("/:account_name/scan-info", r.api.HandlerGetAccountScanInfo)
 	accountsGroup.POST("", r.api.HandlerPostAccount)
 	accountsGroup.DELETE("/:account_name", r.api.HandlerDeleteAccount)
 	accountsGroup.PATCH("/:account_name", r.api.HandlerPatchAccount)
@@ -91,11 +167,63 @@ func (r *Router) setupAccountsRoutes(baseGroup *gin.RouterGroup) {
 func (r *Router) setupOverviewRoutes(baseGroup *gin.RouterGroup) {
 	overviewGroup := baseGroup.Group("/overview")
 	overviewGroup.GET("", r.api.HandlerGetInventoryOverview)
+	if r.api.overviewStream != nil {
+		baseGroup.GET("/stream", r.api.HandlerStreamInventoryOverview)
+	}
+}
+
+func (r *Router) setupProvidersRoutes(baseGroup *gin.RouterGroup) {
+	providersGroup := baseGroup.Group("/providers")
+	providersGroup.GET("", r.api.HandlerGetProviders)
+}
+
+func (r *Router) setupRegionsRoutes(baseGroup *gin.RouterGroup) {
+	regionsGroup := baseGroup.Group("/regions")
+	regionsGroup.GET("", r.api.HandlerGetRegions)
+}
+
+func (r *Router) setupTagsRoutes(baseGroup *gin.RouterGroup) {
+	tagsGroup := baseGroup.Group("/tags")
+	tagsGroup.GET("", r.api.HandlerGetTagKeys)
+}
+
+func (r *Router) setupSearchRoutes(baseGroup *gin.RouterGroup) {
+	baseGroup.GET("/search", r.api.HandlerSearch)
 }
 
 func (r *Router) setupInventoryRoutes(baseGroup *gin.RouterGroup) {
 	inventoryGroup := baseGroup.Group("/inventory")
 	inventoryGroup.POST("/refresh", r.api.HandlerRefreshInventory)
+	baseGroup.POST("/validate", skipAudit, r.api.HandlerValidateInventory)
+}
+
+func (r *Router) setupScanRoutes(baseGroup *gin.RouterGroup) {
+	scanGroup := baseGroup.Group("/scan")
+	scanGroup.GET("/coverage", r.api.HandlerGetScanCoverage)
+}
+
+func (r *Router) setupExportRoutes(baseGroup *gin.RouterGroup) {
+	exportGroup := baseGroup.Group("/export")
+	exportGroup.GET("/dot", r.api.HandlerExportDOT)
+	exportGroup.GET("/cost", r.api.HandlerExportCost)
+}
+
+func (r *Router) setupStatsRoutes(baseGroup *gin.RouterGroup) {
+	statsGroup := baseGroup.Group("/stats")
+	statsGroup.GET("/regions", r.api.HandlerGetRegionStats)
+}
+
+func (r *Router) setupDebugRoutes(baseGroup *gin.RouterGroup) {
+	debugGroup := baseGroup.Group("/debug")
+	debugGroup.GET("/stats", r.api.HandlerGetDebugStats)
+}
+
+func (r *Router) setupAuditRoutes(baseGroup *gin.RouterGroup) {
+	auditGroup := baseGroup.Group("/audit")
+	if token := r.api.cfg.AuditToken; token != "" {
+		auditGroup.Use(middleware.RequireBearerToken(token))
+	}
+	auditGroup.GET("", r.api.HandlerGetRequestAudit)
 }
 
 func (r *Router) setupEventsRoutes(baseGroup *gin.RouterGroup) {
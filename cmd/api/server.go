@@ -4,29 +4,31 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
+	// Embedded timezone database, the runtime images don't ship it
+	_ "time/tzdata"
 
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/config"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/events"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
 	ciqLogger "github.com/RHEcosystemAppEng/cluster-iq/internal/logger"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/models"
 	sqlclient "github.com/RHEcosystemAppEng/cluster-iq/internal/sql_client"
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
-)
-
-const (
-	// APITimeoutSeconds defines the default timeout in seconds for the API connection.
-	// This value is used for graceful shutdowns and other timeout-related operations.
-	APITimeoutSeconds = 60
+	"go.uber.org/zap/zapcore"
 )
 
 var (
@@ -37,6 +39,19 @@ var (
 	// commit reflects the git short-hash of the compiled version.
 	// It provides traceability for the exact source code version used to build the binary.
 	commit string
+
+	// buildTime reflects the UTC time (RFC3339) when the binary was built.
+	// It is populated at build time using build flags.
+	buildTime string
+)
+
+const (
+	// inventoryUnavailableMessage is the error message of the requests failed because the DB can't be reached
+	inventoryUnavailableMessage = "inventory backend unavailable"
+	// inventoryTimeoutMessage is the error message of the requests failed because a query exceeded CIQ_DB_TIMEOUT
+	inventoryTimeoutMessage = "inventory backend timed out"
+	// inventoryNotPopulatedMessage is the error message of the list requests served before the first scan
+	inventoryNotPopulatedMessage = "inventory not yet populated"
 )
 
 // APIServer represents the API server, including configuration, logger, router, and clients for gRPC and SQL.
@@ -48,6 +63,20 @@ type APIServer struct {
 	grpc         *APIGRPCClient          // gRPC client for communication with external services
 	sql          *sqlclient.SQLClient    // SQL client for database operations
 	eventService *events.EventService    // Service for handling audit logs
+	stats        *requestStats           // Internal request counters
+	metrics      *apiMetrics             // Prometheus metrics
+	instances    *instancesCache         // In-memory copy of the instances list
+	location     *time.Location          // Default location for the date filters
+	// costDimensions maps the cost dimensions to the tag key of every provider
+	costDimensions inventory.CostDimensions
+	// emptyInventoryOnce ensures the "no inventory data yet" message is logged only once
+	emptyInventoryOnce *sync.Once
+	// stopRefresher stops the background refresh of the instances list and waits for it. nil if not running
+	stopRefresher func()
+	// overviewStream broadcasts the inventory overview changes to the /stream clients. nil if disabled
+	overviewStream *overviewStream
+	// stopOverviewStream stops checking the inventory overview changes and waits for it. nil if not running
+	stopOverviewStream func()
 }
 
 // NewAPIServer initializes a new instance of the APIServer.
@@ -60,8 +89,56 @@ type APIServer struct {
 // Returns:
 // - Pointer to the newly created APIServer.
 func NewAPIServer(cfg *config.APIServerConfig, logger *zap.Logger) (*APIServer, error) {
+	// Parsing disabled endpoints
+	disabledEndpoints, err := middleware.ParseEndpointPatterns(cfg.DisabledEndpoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse disabled endpoints: %w", err)
+	}
+
+	// Parsing the endpoints served without the API token. The audit trail has its own token
+	publicEndpoints, err := middleware.ParseEndpointPatterns(cfg.APIPublicEndpoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public endpoints: %w", err)
+	}
+	if cfg.AuditToken != "" {
+		publicEndpoints = append(publicEndpoints, middleware.EndpointPattern{Method: http.MethodGet, Route: "/api/v1/audit"})
+	}
+	// The profiles are fetched by 'go tool pprof', which can't send the API token
+	if cfg.EnablePprof {
+		publicEndpoints = append(publicEndpoints, middleware.EndpointPattern{Route: pprofRoutesPattern})
+		logger.Warn("CIQ_ENABLE_PPROF is set. The Go runtime profiles are served without authentication on /debug/pprof/")
+	}
+
+	// Configuring instances display name resolution
+	if err := inventory.SetDisplayNameOrder(cfg.InstanceDisplayNameOrder); err != nil {
+		return nil, fmt.Errorf("failed to configure instance display name order: %w", err)
+	}
+
+	// Loading default timezone for the date filters
+	location, err := time.LoadLocation(cfg.DefaultTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default timezone: %w", err)
+	}
+
+	// Loading cost dimensions mapping
+	costDimensions := make(inventory.CostDimensions)
+	if cfg.CostDimensionsFile != "" {
+		if costDimensions, err = inventory.ReadCostDimensions(cfg.CostDimensionsFile); err != nil {
+			return nil, fmt.Errorf("failed to read cost dimensions file: %w", err)
+		}
+	}
+
 	// Configuring GIN engine
-	engine := setupGin(logger)
+	engine, err := setupGin(cfg, logger, disabledEndpoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure trusted proxies: %w", err)
+	}
+
+	// Request counters must be registered before the routes
+	stats := newRequestStats()
+	engine.Use(stats.middleware())
+	metrics := newAPIMetrics()
+	engine.Use(metrics.middleware())
 
 	// Creating gRPC client
 	gRPCClient, err := NewAPIGRPCClient(cfg.AgentURL, logger)
@@ -70,10 +147,37 @@ func NewAPIServer(cfg *config.APIServerConfig, logger *zap.Logger) (*APIServer,
 	}
 
 	// Creating DB client
-	sqlCli, err := sqlclient.NewSQLClient(cfg.DBURL, logger)
+	dbURL, err := sqlclient.WithTimeouts(cfg.DBURL, cfg.DBTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure DB timeouts: %w", err)
+	}
+	if dbURL, err = sqlclient.WithUser(dbURL, cfg.DBUser); err != nil {
+		return nil, fmt.Errorf("failed to configure DB user: %w", err)
+	}
+	if cfg.DBTLS {
+		if dbURL, err = sqlclient.WithTLS(dbURL, cfg.DBCAFile, cfg.DBTLSSkipVerify); err != nil {
+			return nil, fmt.Errorf("failed to configure DB TLS: %w", err)
+		}
+	}
+	sqlCli, err := connectDB(dbURL, cfg.DBConnectAttempts, cfg.DBConnectBaseDelay, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SQL client: %w", err)
 	}
+	sqlCli.SetPoolSize(cfg.DBPoolSize)
+
+	// Every mutating request is audited. Must be registered before the routes
+	engine.Use(newRequestAuditor(sqlCli, logger).middleware())
+
+	// Instances list cache, dropped after every write. Must be registered before the routes
+	instances := newInstancesCache(cfg.CacheTTL, sqlCli.GetInstances)
+	engine.Use(instances.middleware())
+
+	// Every request requires the API token, if configured. Must be registered before the routes
+	if cfg.APIToken != "" {
+		engine.Use(middleware.RequireBearerTokenExcept(cfg.APIToken, publicEndpoints))
+	} else {
+		logger.Warn("CIQ_API_TOKEN is not set. The API is served without authentication")
+	}
 
 	// Creating Event Service
 	eventService := events.NewEventService(sqlCli, logger)
@@ -87,33 +191,227 @@ func NewAPIServer(cfg *config.APIServerConfig, logger *zap.Logger) (*APIServer,
 			Addr:    cfg.ListenURL,
 			Handler: engine,
 		},
-		grpc:         gRPCClient,
-		sql:          sqlCli,
-		eventService: eventService,
+		grpc:               gRPCClient,
+		sql:                sqlCli,
+		eventService:       eventService,
+		stats:              stats,
+		metrics:            metrics,
+		instances:          instances,
+		location:           location,
+		costDimensions:     costDimensions,
+		emptyInventoryOnce: &sync.Once{},
+	}
+
+	if cfg.PublicURL != "" {
+		setOpenAPIPublicURL(cfg.PublicURL)
+	}
+	if cfg.StreamMaxConnections > 0 {
+		apiServer.overviewStream = newOverviewStream(cfg.StreamMaxConnections)
+		// The stream clients never end their requests, so they are disconnected on shutdown
+		apiServer.server.RegisterOnShutdown(apiServer.overviewStream.close)
 	}
 
 	// Initialize routes
 	router := NewRouter(apiServer)
 	router.SetupRoutes()
+	apiServer.logDisabledEndpoints(disabledEndpoints)
 
 	return apiServer, nil
 }
 
-func setupGin(logger *zap.Logger) *gin.Engine {
+// rejectUnpopulatedInventory is called when a list endpoint returns no data.
+// If the scanner never populated the inventory (fresh deployments), the
+// request is answered by writeUnpopulatedInventory. It returns true if the
+// response was already written. A failed last scan lookup is not an error
+// here, so the empty list is served.
+func (a APIServer) rejectUnpopulatedInventory(c *gin.Context) bool {
+	lastScan, err := a.sql.GetScannerLastScanTimestamp()
+	if err != nil || lastScan != nil {
+		return false
+	}
+	return a.writeUnpopulatedInventory(c)
+}
+
+// writeUnpopulatedInventory answers a list request served before the first
+// scan with 503 Service Unavailable, so the clients can tell a fresh
+// deployment from an empty inventory. With CIQ_SERVE_EMPTY_INVENTORY, the
+// empty list is served instead and a one-time info message is logged. It
+// returns true if the response was written.
+func (a APIServer) writeUnpopulatedInventory(c *gin.Context) bool {
+	if a.cfg.ServeEmptyInventory {
+		a.emptyInventoryOnce.Do(func() {
+			a.logger.Info("No inventory data yet. Lists will be empty until the first scan finishes")
+		})
+		return false
+	}
+
+	a.requestLogger(c).Warn("Inventory not yet populated")
+	respondError(c, http.StatusServiceUnavailable, inventoryNotPopulatedMessage)
+	return true
+}
+
+// respondError writes a GenericErrorResponse with the status code on both the
+// response and its body, so the clients don't need to parse the status line
+func respondError(c *gin.Context, code int, message string) {
+	response := NewGenericErrorResponse(code, message)
+	response.RequestID = middleware.GetRequestID(c)
+	writeJSON(c, code, response)
+}
+
+// requestLogger returns the API logger with the ID and the client IP of the
+// request, so every line logged while serving it can be correlated with its
+// X-Request-ID
+func (a APIServer) requestLogger(c *gin.Context) *zap.Logger {
+	fields := []zap.Field{zap.String("client_ip", c.ClientIP())}
+	if id := middleware.GetRequestID(c); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	return a.logger.With(fields...)
+}
+
+// writeJSON writes a JSON response with an explicit UTF-8 Content-Type. The
+// HTML characters (<, > and &) of the body are escaped unless disabled by
+// CIQ_JSON_ESCAPE, so the inventory values can't inject markup into a
+// browser rendering them
+func writeJSON(c *gin.Context, code int, obj any) {
+	c.Header("Content-Type", middleware.MIMEJSONUTF8)
+	if middleware.IsPureJSON(c) {
+		c.PureJSON(code, obj)
+		return
+	}
+	c.JSON(code, obj)
+}
+
+// rejectInvalidBatch writes a 400 Bad Request listing the issues of an
+// invalid batch of posted resources, so it's not written and the previous
+// inventory keeps being served. It returns false if there are no issues.
+func (a APIServer) rejectInvalidBatch(c *gin.Context, resource string, issues []string) bool {
+	if len(issues) == 0 {
+		return false
+	}
+	a.requestLogger(c).Warn("Rejected invalid batch", zap.String("resource", resource), zap.Strings("issues", issues))
+	respondError(c, http.StatusBadRequest, fmt.Sprintf("invalid %s: %s", resource, strings.Join(issues, "; ")))
+	return true
+}
+
+// writeInventoryError writes the error response of a failed DB query. If the
+// query exceeded CIQ_DB_TIMEOUT, it responds 504 Gateway Timeout. If the DB
+// can't be reached, it responds 503 Service Unavailable, so the clients don't
+// take it as an empty inventory. Otherwise it responds 500.
+func (a APIServer) writeInventoryError(c *gin.Context, err error) {
+	if sqlclient.IsTimeoutError(err) {
+		respondError(c, http.StatusGatewayTimeout, inventoryTimeoutMessage)
+		return
+	}
+	if sqlclient.IsUnavailableError(err) {
+		respondError(c, http.StatusServiceUnavailable, inventoryUnavailableMessage)
+		return
+	}
+	respondError(c, http.StatusInternalServerError, err.Error())
+}
+
+// writeAccountLookupError writes the error response of a failed account
+// lookup: 404 Not Found if the account (or alias) doesn't exist, or the
+// inventory error otherwise.
+func (a APIServer) writeAccountLookupError(c *gin.Context, accountName string, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, fmt.Sprintf("account '%s' not found", accountName))
+		return
+	}
+	a.writeInventoryError(c, err)
+}
+
+// writeClusterLookupError writes the error response of a failed cluster
+// lookup: 404 Not Found if the cluster doesn't exist, or the inventory error
+// otherwise, so a DB outage isn't reported as a missing cluster.
+func (a APIServer) writeClusterLookupError(c *gin.Context, clusterID string, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(c, http.StatusNotFound, fmt.Sprintf("cluster '%s' not found", clusterID))
+		return
+	}
+	a.writeInventoryError(c, err)
+}
+
+// isInventoryStale reports if the last scan is older than CIQ_MAX_INVENTORY_STALENESS.
+// If the last scan can't be retrieved, the inventory is considered stale.
+func (a APIServer) isInventoryStale() bool {
+	if a.cfg.MaxInventoryStaleness <= 0 {
+		return false
+	}
+
+	lastScan, err := a.sql.GetScannerLastScanTimestamp()
+	if err != nil || lastScan == nil {
+		return true
+	}
+	return time.Since(*lastScan) > a.cfg.MaxInventoryStaleness
+}
+
+// logDisabledEndpoints logs the registered routes that won't be served because of CIQ_DISABLED_ENDPOINTS
+func (a APIServer) logDisabledEndpoints(patterns []middleware.EndpointPattern) {
+	if len(patterns) == 0 {
+		return
+	}
+
+	for _, route := range a.router.Routes() {
+		if middleware.IsEndpointDisabled(patterns, route.Method, route.Path) {
+			a.logger.Info("Endpoint disabled", zap.String("method", route.Method), zap.String("path", route.Path))
+		}
+	}
+}
+
+// rateLimitExemptEndpoints are the routes never rate limited, so the Kubernetes probes aren't throttled
+var rateLimitExemptEndpoints = []middleware.EndpointPattern{
+	{Method: http.MethodGet, Route: "/api/v1/healthz"},
+	{Method: http.MethodGet, Route: "/api/v1/readyz"},
+}
+
+func setupGin(cfg *config.APIServerConfig, logger *zap.Logger, disabledEndpoints []middleware.EndpointPattern) (*gin.Engine, error) {
 	// TODO. Configure via env vars
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
+	// X-Forwarded-For is only honored from the trusted proxies, so the clients
+	// can't spoof their IP. Gin trusts every proxy by default, so none is
+	// trusted when CIQ_TRUSTED_PROXIES is empty
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
 	// Configure default middleware
-	router.Use()
-	router.Use(middleware.SetCommonHeaders())
+	router.Use(middleware.RequestID())
+	// Registered right after RequestID, so the panics of the other middlewares
+	// are recovered too, and logged with the request ID
+	router.Use(middleware.Recovery(logger))
+	router.Use(middleware.CORS(cfg.CORSAllowedOrigins))
+	if cfg.RateLimit > 0 {
+		router.Use(middleware.RateLimit(cfg.RateLimit, cfg.RateBurst, rateLimitExemptEndpoints))
+	}
+	if cfg.GzipMinSize >= 0 {
+		router.Use(middleware.Gzip(cfg.GzipMinSize))
+	}
+	// text/event-stream is always sent by the browsers' EventSource (/stream)
+	router.Use(middleware.NegotiateContentType(middleware.MIMEJSON, middleware.MIMECSV, middleware.MIMENDJSON, middleware.MIMEYAML, middleware.MIMEEventStream))
+	router.Use(middleware.YAML(formatParam))
+	if cfg.ServerTiming {
+		router.Use(middleware.ServerTiming())
+	}
+	if len(disabledEndpoints) > 0 {
+		router.Use(middleware.DisableEndpoints(disabledEndpoints))
+	}
+	if !cfg.JSONEscape {
+		router.Use(middleware.PureJSON())
+	}
+	if len(cfg.HiddenFields) > 0 {
+		router.Use(middleware.HiddenFields(cfg.HiddenFields))
+	}
 	// Configure Gin to use Zap
 	router.Use(ginzap.GinzapWithConfig(logger, &ginzap.Config{
 		TimeFormat: time.RFC3339,
 		UTC:        true,
-		SkipPaths:  []string{"/api/v1/healthcheck"},
+		SkipPaths:  cfg.LogSkipPaths,
+		Context: func(c *gin.Context) []zapcore.Field {
+			return []zapcore.Field{zap.String("request_id", middleware.GetRequestID(c))}
+		},
 	}))
-	router.Use(gin.Recovery())
-	return router
+	return router, nil
 }
 
 // Start starts the HTTP server in a goroutine
@@ -121,10 +419,26 @@ func (a *APIServer) Start() error {
 	a.logger.Info("==================== Starting ClusterIQ API ====================",
 		zap.String("version", version),
 		zap.String("commit", commit),
-		zap.String("api_url", a.cfg.ListenURL),
+		zap.String("build_time", buildTime),
+		zap.String("listen_url", a.cfg.ListenURL),
+		zap.String("public_url", a.cfg.PublicURL),
+		zap.Strings("trusted_proxies", a.cfg.TrustedProxies),
 		zap.String("db_url", a.cfg.DBURL),
 		zap.String("agent_url", a.cfg.AgentURL))
 
+	// Background refresh of the instances list, so the requests don't wait for the DB
+	if a.cfg.BackgroundRefresh {
+		if a.cfg.CacheTTL > 0 {
+			a.startRefresher()
+		} else {
+			a.logger.Warn("CIQ_BACKGROUND_REFRESH is ignored, as the instances cache is disabled (CIQ_CACHE_TTL)")
+		}
+	}
+
+	if a.overviewStream != nil {
+		a.startOverviewStream()
+	}
+
 	// Start API
 	go func() {
 		if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -136,6 +450,40 @@ func (a *APIServer) Start() error {
 	return nil
 }
 
+// startRefresher starts refreshing the instances list every
+// CIQ_BACKGROUND_REFRESH_INTERVAL in background, until stopRefresher is called
+func (a *APIServer) startRefresher() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.instances.runRefresher(ctx, a.cfg.BackgroundRefreshInterval, a.logger)
+	}()
+
+	a.stopRefresher = func() {
+		cancel()
+		<-done
+	}
+	a.logger.Info("Instances list background refresh started", zap.Duration("interval", a.cfg.BackgroundRefreshInterval))
+}
+
+// startOverviewStream starts checking the inventory overview changes every
+// CIQ_STREAM_INTERVAL in background, until stopOverviewStream is called
+func (a *APIServer) startOverviewStream() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		load := func() (models.OverviewSummary, error) { return a.getInventoryOverview(a.cfg.ExcludeTag) }
+		a.overviewStream.run(ctx, a.cfg.StreamInterval, load, a.cfg.HiddenFields, a.logger)
+	}()
+
+	a.stopOverviewStream = func() {
+		cancel()
+		<-done
+	}
+}
+
 // Run starts the server and handles graceful shutdown
 func (a *APIServer) Run() error {
 	if err := a.Start(); err != nil {
@@ -157,7 +505,10 @@ func (a *APIServer) Run() error {
 }
 
 // signalHandler handles OS signals for graceful server shutdown.  It shuts
-// down the server when a SIGTERM signal is received. This function was included
+// down the server when a SIGTERM signal is received, waiting up to
+// CIQ_SHUTDOWN_GRACE_PERIOD for the in-flight requests, and then closes the
+// gRPC and DB clients. The clients are closed even if the grace period expires,
+// and the shutdown error is returned once they are. This function was included
 // for better integration on K8s/OCP
 //
 // Parameters:
@@ -169,16 +520,42 @@ func (a APIServer) signalHandler(signal os.Signal) error {
 		a.logger.Warn("Shutting down server...", zap.String("signal", signal.String()))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), APITimeoutSeconds*time.Second)
+	// Waiting for the in-flight requests before closing the clients they use
+	a.logger.Info("Waiting for in-flight requests", zap.Duration("grace_period", a.cfg.ShutdownGracePeriod))
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.ShutdownGracePeriod)
 	defer cancel()
 
-	if err := a.server.Shutdown(ctx); err != nil {
-		a.logger.Fatal("API Shutdown error", zap.Error(err))
-		return err
+	shutdownErr := a.server.Shutdown(ctx)
+	if shutdownErr != nil {
+		a.logger.Error("API Shutdown error. In-flight requests were dropped", zap.Error(shutdownErr))
+		_ = a.server.Close()
+	} else {
+		a.logger.Info("HTTP server stopped")
+	}
+
+	// The refresher and the overview stream use the DB client, so they are stopped before closing it
+	if a.stopRefresher != nil {
+		a.stopRefresher()
+		a.logger.Info("Instances list background refresh stopped")
+	}
+	if a.stopOverviewStream != nil {
+		a.stopOverviewStream()
+	}
+
+	if err := a.grpc.Close(); err != nil {
+		a.logger.Error("Failed to close gRPC client", zap.Error(err))
+	} else {
+		a.logger.Info("gRPC client closed")
+	}
+
+	if err := a.sql.Close(); err != nil {
+		a.logger.Error("Failed to close DB connection", zap.Error(err))
+	} else {
+		a.logger.Info("DB connection closed")
 	}
 
 	a.logger.Info("API server stopped")
-	return nil
+	return shutdownErr
 }
 
 //	@title			ClusterIQ API
@@ -197,6 +574,10 @@ func (a APIServer) signalHandler(signal os.Signal) error {
 
 //	@securityDefinitions.basic	BasicAuth
 
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+
 // @externalDocs.description	OpenAPI
 // @externalDocs.url			https://swagger.io/resources/open-api/
 func main() {
@@ -209,7 +590,7 @@ func main() {
 	// Loading APIServer config
 	cfg, err := config.LoadAPIServerConfig()
 	if err != nil {
-		logger.Error("Error loading APIServer config", zap.Error(err))
+		logger.Fatal("Error loading APIServer config", zap.Error(err))
 		return
 	}
 
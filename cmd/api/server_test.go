@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/config"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/middleware"
+	sqlclient "github.com/RHEcosystemAppEng/cluster-iq/internal/sql_client"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TestWriteJSONEscaping verifies the HTML characters of a cluster name are escaped unless PureJSON is enabled
+func TestWriteJSONEscaping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const clusterName = "<script>alert('x')</script> & co"
+
+	tests := []struct {
+		name        string
+		middlewares []gin.HandlerFunc
+		wantEscaped bool
+	}{
+		{name: "Escaped", wantEscaped: true},
+		{name: "Escaped with hidden fields", middlewares: []gin.HandlerFunc{middleware.HiddenFields([]string{"totalCost"})}, wantEscaped: true},
+		{name: "Pure", middlewares: []gin.HandlerFunc{middleware.PureJSON()}, wantEscaped: false},
+		{name: "Pure with hidden fields", middlewares: []gin.HandlerFunc{middleware.PureJSON(), middleware.HiddenFields([]string{"totalCost"})}, wantEscaped: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.Use(tt.middlewares...)
+			engine.GET("/clusters", func(c *gin.Context) {
+				writeJSON(c, http.StatusOK, NewClusterListResponse([]inventory.Cluster{{Name: clusterName}}))
+			})
+
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clusters", nil))
+
+			if got := rec.Header().Get("Content-Type"); got != middleware.MIMEJSONUTF8 {
+				t.Errorf("expected Content-Type %q, got %q", middleware.MIMEJSONUTF8, got)
+			}
+			body := rec.Body.String()
+			if escaped := !strings.ContainsAny(body, "<>&"); escaped != tt.wantEscaped {
+				t.Errorf("expected escaped=%v, got body %s", tt.wantEscaped, body)
+			}
+			if tt.wantEscaped && !strings.Contains(body, `\u003cscript\u003e`) {
+				t.Errorf("expected the unicode escaped name on the body, got %s", body)
+			}
+
+			var response ClusterListResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("can't decode body: %v", err)
+			}
+			if len(response.Clusters) != 1 || response.Clusters[0].Name != clusterName {
+				t.Errorf("expected the cluster name %q after decoding, got %+v", clusterName, response.Clusters)
+			}
+		})
+	}
+}
+
+// TestWriteJSONAsYAML verifies the responses requested as YAML keep the empty lists instead of null
+func TestWriteJSONAsYAML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.NegotiateContentType(middleware.MIMEJSON, middleware.MIMEYAML))
+	engine.Use(middleware.YAML(formatParam))
+	engine.GET("/clusters", func(c *gin.Context) {
+		writeJSON(c, http.StatusOK, NewClusterListResponse(nil))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/clusters", nil)
+	req.Header.Set("Accept", middleware.MIMEYAML)
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != middleware.MIMEYAMLUTF8 {
+		t.Errorf("expected Content-Type %q, got %q", middleware.MIMEYAMLUTF8, got)
+	}
+	if body := rec.Body.String(); !strings.HasPrefix(body, "clusters: []\n") || strings.Contains(body, "null") {
+		t.Errorf("expected an empty clusters list, got %s", body)
+	}
+}
+
+// TestRespondErrorContentType verifies the error responses set the JSON Content-Type explicitly and carry the request ID
+func TestRespondErrorContentType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(middleware.RequestID())
+	engine.GET("/clusters/:cluster_id", func(c *gin.Context) {
+		respondError(c, http.StatusNotFound, "cluster '"+c.Param("cluster_id")+"' not found")
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clusters/%3Cb%3E", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != middleware.MIMEJSONUTF8 {
+		t.Errorf("expected Content-Type %q, got %q", middleware.MIMEJSONUTF8, got)
+	}
+	if body := rec.Body.String(); strings.ContainsAny(body, "<>") {
+		t.Errorf("expected the escaped cluster ID on the error, got %s", body)
+	}
+
+	var response GenericErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("can't decode body: %v", err)
+	}
+	if id := rec.Header().Get(middleware.RequestIDHeader); id == "" || response.RequestID != id {
+		t.Errorf("expected the request ID %q on the error, got %q", id, response.RequestID)
+	}
+}
+
+// TestWriteUnpopulatedInventory verifies the lists served before the first scan are answered with 503, unless CIQ_SERVE_EMPTY_INVENTORY is set
+func TestWriteUnpopulatedInventory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		serveEmpty  bool
+		wantWritten bool
+		wantCode    int
+	}{
+		{name: "Unavailable", wantWritten: true, wantCode: http.StatusServiceUnavailable},
+		{name: "Served empty", serveEmpty: true, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := APIServer{
+				cfg:                &config.APIServerConfig{ServeEmptyInventory: tt.serveEmpty},
+				logger:             zap.NewNop(),
+				emptyInventoryOnce: &sync.Once{},
+			}
+			engine := gin.New()
+			engine.GET("/clusters", func(c *gin.Context) {
+				if written := api.writeUnpopulatedInventory(c); written != tt.wantWritten {
+					t.Errorf("expected written=%v, got %v", tt.wantWritten, written)
+				}
+				if !c.Writer.Written() {
+					writeJSON(c, http.StatusOK, NewClusterListResponse(nil))
+				}
+			})
+
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clusters", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+			if tt.wantWritten {
+				var response GenericErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+					t.Fatalf("can't decode body: %v", err)
+				}
+				if response.Message != inventoryNotPopulatedMessage {
+					t.Errorf("expected message %q, got %q", inventoryNotPopulatedMessage, response.Message)
+				}
+			}
+		})
+	}
+}
+
+// TestSetupGinTrustedProxies verifies X-Forwarded-For only sets the client IP when sent by a trusted proxy
+func TestSetupGinTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		want           string
+	}{
+		{name: "None trusted", want: "10.0.0.1"},
+		{name: "Trusted", trustedProxies: []string{"10.0.0.0/8"}, want: "203.0.113.7"},
+		{name: "Other proxy trusted", trustedProxies: []string{"192.168.0.1"}, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := setupGin(&config.APIServerConfig{GzipMinSize: -1, TrustedProxies: tt.trustedProxies}, zap.NewNop(), nil)
+			if err != nil {
+				t.Fatalf("can't setup gin: %v", err)
+			}
+			var got string
+			engine.GET("/ip", func(c *gin.Context) { got = c.ClientIP() })
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = "10.0.0.1:41234"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			engine.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("expected client IP %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSetupGinRecovery verifies Recovery is registered right after RequestID, so the panics of the middlewares are recovered with the request ID
+func TestSetupGinRecovery(t *testing.T) {
+	engine, err := setupGin(&config.APIServerConfig{GzipMinSize: 0, HiddenFields: []string{"totalCost"}}, zap.NewNop(), nil)
+	if err != nil {
+		t.Fatalf("can't setup gin: %v", err)
+	}
+	var names []string
+	for _, handler := range engine.Handlers {
+		names = append(names, runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name())
+	}
+	if len(names) < 2 || !strings.Contains(names[0], "middleware.RequestID") || !strings.Contains(names[1], "middleware.Recovery") {
+		t.Fatalf("expected RequestID and Recovery as the first middlewares, got %v", names)
+	}
+
+	engine.Use(func(c *gin.Context) {
+		panic("middleware failure")
+	})
+	engine.GET("/clusters", func(c *gin.Context) {
+		writeJSON(c, http.StatusOK, NewClusterListResponse(nil))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/clusters", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	var response GenericErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("can't decode body %q: %v", rec.Body.String(), err)
+	}
+	if response.RequestID != "req-1" {
+		t.Errorf("expected the request ID on the error, got %q", response.RequestID)
+	}
+}
+
+// TestWriteJSONDeterministic verifies two consecutive responses of the same inventory snapshot are byte-identical
+func TestWriteJSONDeterministic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	inv := inventory.NewInventory()
+	for a := range 5 {
+		account := &inventory.Account{Name: "account-" + strconv.Itoa(a), Clusters: make(map[string]*inventory.Cluster)}
+		for c := range 5 {
+			id := account.Name + "-cluster-" + strconv.Itoa(c)
+			account.Clusters[id] = &inventory.Cluster{
+				ID:           id,
+				Name:         "cluster",
+				AccountName:  account.Name,
+				CostByStatus: map[inventory.InstanceStatus]float64{inventory.Running: float64(c), inventory.Stopped: 1},
+			}
+		}
+		if err := inv.AddAccount(account); err != nil {
+			t.Fatalf("can't add account: %v", err)
+		}
+	}
+
+	engine := gin.New()
+	engine.GET("/accounts", func(c *gin.Context) {
+		var accounts []inventory.Account
+		for _, account := range inv.SortedAccounts() {
+			accounts = append(accounts, *account)
+		}
+		writeJSON(c, http.StatusOK, NewAccountListResponse(accounts))
+	})
+	engine.GET("/clusters", func(c *gin.Context) {
+		var clusters []inventory.Cluster
+		for _, account := range inv.SortedAccounts() {
+			for _, cluster := range account.SortedClusters() {
+				clusters = append(clusters, *cluster)
+			}
+		}
+		writeJSON(c, http.StatusOK, NewClusterListResponse(clusters))
+	})
+
+	for _, path := range []string{"/accounts", "/clusters"} {
+		t.Run(path, func(t *testing.T) {
+			render := func() []byte {
+				rec := httptest.NewRecorder()
+				engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+				return rec.Body.Bytes()
+			}
+
+			first, second := render(), render()
+			if !bytes.Equal(first, second) {
+				t.Errorf("expected identical responses\nfirst:  %s\nsecond: %s", first, second)
+			}
+			// The fields not loaded are omitted instead of null
+			if bytes.Contains(first, []byte("null")) {
+				t.Errorf("expected no null fields, got %s", first)
+			}
+		})
+	}
+}
+
+// TestWriteClusterLookupError verifies only the missing clusters are answered with 404, and the DB failures keep their status
+func TestWriteClusterLookupError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "Not found", err: fmt.Errorf("query: %w", sql.ErrNoRows), wantCode: http.StatusNotFound},
+		{name: "Timeout", err: context.DeadlineExceeded, wantCode: http.StatusGatewayTimeout},
+		{name: "Other", err: errors.New("syntax error"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.GET("/clusters/:cluster_id", func(c *gin.Context) {
+				APIServer{logger: zap.NewNop()}.writeClusterLookupError(c, c.Param("cluster_id"), tt.err)
+			})
+
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clusters/c1", nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+		})
+	}
+}
+
+// TestSignalHandlerShutdownTimeout verifies the background tasks are stopped and the clients are closed even if the in-flight requests outlive the grace period
+func TestSignalHandlerShutdownTimeout(t *testing.T) {
+	// A request which doesn't finish until the test does
+	release := make(chan struct{})
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	go func() {
+		if resp, err := http.Get(server.URL); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	conn, err := grpc.NewClient("passthrough:///localhost:0", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create the gRPC client: %v", err)
+	}
+	db := sql.OpenDB(fakeDB{})
+
+	var refresherStopped, overviewStreamStopped bool
+	api := APIServer{
+		cfg:                &config.APIServerConfig{ShutdownGracePeriod: 10 * time.Millisecond},
+		logger:             zap.NewNop(),
+		server:             server.Config,
+		grpc:               &APIGRPCClient{Cancel: func() {}, conn: conn},
+		sql:                sqlclient.NewSQLClientFromDB(db, zap.NewNop()),
+		stopRefresher:      func() { refresherStopped = true },
+		stopOverviewStream: func() { overviewStreamStopped = true },
+	}
+
+	if err := api.signalHandler(syscall.SIGTERM); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the grace period to expire, got %v", err)
+	}
+	if !refresherStopped || !overviewStreamStopped {
+		t.Errorf("expected the background tasks to be stopped, got refresher %v and overview stream %v", refresherStopped, overviewStreamStopped)
+	}
+	if state := conn.GetState(); state != connectivity.Shutdown {
+		t.Errorf("expected the gRPC client to be closed, got %s", state)
+	}
+	if err := db.Ping(); err == nil {
+		t.Error("expected the DB client to be closed")
+	}
+}
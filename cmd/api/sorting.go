@@ -0,0 +1,92 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// sortOrderAsc sorts the results in ascending order (default)
+	sortOrderAsc = "asc"
+	// sortOrderDesc sorts the results in descending order
+	sortOrderDesc = "desc"
+)
+
+// instanceSortCostPerHour is the 'sort' value for the instances cost per hour,
+// which is computed from their status history
+const instanceSortCostPerHour = "costPerHour"
+
+// instanceSortFields maps the supported 'sort' values for instances to their comparison functions
+var instanceSortFields = map[string]func(a, b inventory.Instance) int{
+	instanceSortCostPerHour: func(a, b inventory.Instance) int { return cmp.Compare(a.CostPerHour, b.CostPerHour) },
+	"cost":                  func(a, b inventory.Instance) int { return cmp.Compare(a.TotalCost, b.TotalCost) },
+	"name":                  func(a, b inventory.Instance) int { return cmp.Compare(a.DisplayName(), b.DisplayName()) },
+	"region":                func(a, b inventory.Instance) int { return cmp.Compare(a.Region(), b.Region()) },
+}
+
+// clusterSortFields maps the supported 'sort' values for clusters to their comparison functions
+var clusterSortFields = map[string]func(a, b inventory.Cluster) int{
+	"cost":          func(a, b inventory.Cluster) int { return cmp.Compare(a.TotalCost, b.TotalCost) },
+	"instanceCount": func(a, b inventory.Cluster) int { return cmp.Compare(a.InstanceCount, b.InstanceCount) },
+	"name":          func(a, b inventory.Cluster) int { return cmp.Compare(a.Name, b.Name) },
+	"region":        func(a, b inventory.Cluster) int { return cmp.Compare(a.Region, b.Region) },
+}
+
+// accountSortFields maps the supported 'sort' values for accounts to their comparison functions
+var accountSortFields = map[string]func(a, b inventory.Account) int{
+	"clusterCount": func(a, b inventory.Account) int { return cmp.Compare(a.ClusterCount, b.ClusterCount) },
+	"cost":         func(a, b inventory.Account) int { return cmp.Compare(a.TotalCost, b.TotalCost) },
+	"name":         func(a, b inventory.Account) int { return cmp.Compare(a.Name, b.Name) },
+}
+
+// sortList sorts the items as requested by the 'sort' and 'order' query
+// params. Without 'sort', the items keep the order of the inventory queries
+// (by name), so the responses are reproducible.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - items: Slice to sort in place.
+// - fields: Supported sort fields and their comparison functions.
+//
+// Returns:
+// - An error if the requested field or order are not supported.
+func sortList[T any](c *gin.Context, items []T, fields map[string]func(a, b T) int) error {
+	field := c.Query(sortParam)
+	if field == "" {
+		return nil
+	}
+	return sortItems(items, fields, field, c.Query(orderParam))
+}
+
+// sortItems sorts the items by the given field and order using the supported
+// comparison functions. The sort is stable, so items with the same value keep
+// their previous order.
+//
+// Parameters:
+// - items: Slice to sort in place.
+// - fields: Supported sort fields and their comparison functions.
+// - field: Requested sort field.
+// - order: Requested sort order (asc/desc). Empty means ascending.
+//
+// Returns:
+// - An error if the field or the order are not supported.
+func sortItems[T any](items []T, fields map[string]func(a, b T) int, field string, order string) error {
+	compare, ok := fields[field]
+	if !ok {
+		return fmt.Errorf("invalid '%s' value (%s)", sortParam, field)
+	}
+
+	switch order {
+	case "", sortOrderAsc:
+		slices.SortStableFunc(items, compare)
+	case sortOrderDesc:
+		slices.SortStableFunc(items, func(a, b T) int { return compare(b, a) })
+	default:
+		return fmt.Errorf("invalid '%s' value (%s). Expected '%s' or '%s'", orderParam, order, sortOrderAsc, sortOrderDesc)
+	}
+	return nil
+}
@@ -2,21 +2,27 @@ package main
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
 	sqlclient "github.com/RHEcosystemAppEng/cluster-iq/internal/sql_client"
 )
 
 // ClusterStatusChangeRequest represents the request to the gRPC Agent for powering on/off clusters.
 // It includes details such as the account name, region, cluster ID, and the list of instance IDs associated with the cluster.
 type ClusterStatusChangeRequest struct {
-	AccountName     string   // The name of the account associated with the cluster.
-	Region          string   // The AWS region where the cluster is located.
-	ClusterID       string   // The unique identifier of the cluster.
-	InstancesIdList []string // A list of instance IDs belonging to the cluster.
+	AccountName            string   // The name of the account associated with the cluster.
+	Region                 string   // The AWS region where the cluster is located.
+	ClusterID              string   // The unique identifier of the cluster.
+	InstancesIdList        []string // A list of instance IDs belonging to the cluster.
+	SkippedInstancesIdList []string // A list of protected instance IDs belonging to the cluster, excluded from the request.
+	instances              []inventory.Instance
 }
 
 // NewClusterStatusChangeRequest creates a new ClusterStatusChangeRequest instance.
 // It retrieves the account name, region, and instance IDs for the given cluster ID from the SQL client.
+// Protected instances are not included on the request, and are reported as skipped. When every
+// instance is protected, the request has an empty InstancesIdList.
 //
 // Parameters:
 // - sql: Pointer to the APISQLClient for database interactions.
@@ -50,15 +56,34 @@ func NewClusterStatusChangeRequest(sql *sqlclient.SQLClient, clusterID string) (
 	}
 
 	// Creating an array of InstancesIDs
-	var instancesIDs []string
+	var instancesIDs, skippedIDs []string
 	for _, instance := range instances {
+		if instance.Protected {
+			skippedIDs = append(skippedIDs, instance.ID)
+			continue
+		}
 		instancesIDs = append(instancesIDs, instance.ID)
 	}
 
 	return &ClusterStatusChangeRequest{
-		AccountName:     accountName,
-		Region:          region,
-		ClusterID:       clusterID,
-		InstancesIdList: instancesIDs,
+		AccountName:            accountName,
+		Region:                 region,
+		ClusterID:              clusterID,
+		InstancesIdList:        instancesIDs,
+		SkippedInstancesIdList: skippedIDs,
+		instances:              instances,
 	}, nil
 }
+
+// ResultingStatus returns the status the cluster will have once the requested instances are on the
+// given status, and the protected ones remain on their current status.
+func (r ClusterStatusChangeRequest) ResultingStatus(status inventory.InstanceStatus) inventory.InstanceStatus {
+	cluster := inventory.Cluster{Instances: slices.Clone(r.instances)}
+	for i := range cluster.Instances {
+		if !cluster.Instances[i].Protected {
+			cluster.Instances[i].Status = status
+		}
+	}
+	cluster.UpdateStatus()
+	return cluster.Status
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/events"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/models"
+	sqlclient "github.com/RHEcosystemAppEng/cluster-iq/internal/sql_client"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// fakeEventClient stores nothing, and gives every event the same ID
+type fakeEventClient struct{}
+
+func (fakeEventClient) AddEvent(models.AuditLog) (int64, error) { return 1, nil }
+func (fakeEventClient) UpdateEventStatus(int64, string) error   { return nil }
+
+// newStatusChangeTestDB returns a fakeDB serving a cluster with the given instances
+func newStatusChangeTestDB(instances [][]driver.Value) *sql.DB {
+	return sql.OpenDB(fakeDB{
+		sqlclient.SelectClusterAccountNameQuery: {
+			columns: []string{"account_name"},
+			values:  [][]driver.Value{{"acc"}},
+		},
+		sqlclient.SelectClusterRegionQuery: {
+			columns: []string{"region"},
+			values:  [][]driver.Value{{"eu-west-1"}},
+		},
+		sqlclient.SelectInstancesOnClusterQuery: {
+			columns: []string{"id", "cluster_id", "status", "protected"},
+			values:  instances,
+		},
+	})
+}
+
+// TestNewClusterStatusChangeRequest verifies protected instances are skipped, and kept on their status for the resulting cluster status
+func TestNewClusterStatusChangeRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		instances     [][]driver.Value
+		expectedIDs   []string
+		expectedSkip  []string
+		expectedOn    inventory.InstanceStatus
+		expectedOff   inventory.InstanceStatus
+		expectedError bool
+	}{
+		{
+			name: "No protected instances",
+			instances: [][]driver.Value{
+				{"i-1", "c1", "Running", false},
+				{"i-2", "c1", "Stopped", false},
+			},
+			expectedIDs: []string{"i-1", "i-2"},
+			expectedOn:  inventory.Running,
+			expectedOff: inventory.Stopped,
+		},
+		{
+			name: "Protected instance running",
+			instances: [][]driver.Value{
+				{"i-1", "c1", "Running", true},
+				{"i-2", "c1", "Running", false},
+			},
+			expectedIDs:  []string{"i-2"},
+			expectedSkip: []string{"i-1"},
+			expectedOn:   inventory.Running,
+			expectedOff:  inventory.Running,
+		},
+		{
+			name: "Only protected instances",
+			instances: [][]driver.Value{
+				{"i-1", "c1", "Stopped", true},
+				{"i-2", "c1", "Stopped", true},
+			},
+			expectedSkip: []string{"i-1", "i-2"},
+			expectedOn:   inventory.Stopped,
+			expectedOff:  inventory.Stopped,
+		},
+		{
+			name:          "No instances",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newStatusChangeTestDB(tt.instances)
+			defer db.Close()
+
+			cscr, err := NewClusterStatusChangeRequest(sqlclient.NewSQLClientFromDB(db, zap.NewNop()), "c1")
+			if tt.expectedError {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(cscr.InstancesIdList, tt.expectedIDs) {
+				t.Errorf("expected instances %v, got %v", tt.expectedIDs, cscr.InstancesIdList)
+			}
+			if !slices.Equal(cscr.SkippedInstancesIdList, tt.expectedSkip) {
+				t.Errorf("expected skipped instances %v, got %v", tt.expectedSkip, cscr.SkippedInstancesIdList)
+			}
+			if status := cscr.ResultingStatus(inventory.Running); status != tt.expectedOn {
+				t.Errorf("expected status %s after powering on, got %s", tt.expectedOn, status)
+			}
+			if status := cscr.ResultingStatus(inventory.Stopped); status != tt.expectedOff {
+				t.Errorf("expected status %s after powering off, got %s", tt.expectedOff, status)
+			}
+		})
+	}
+}
+
+// TestPowerClusterOnlyProtected verifies a cluster with only protected instances is left untouched, and reported with its skipped instances
+func TestPowerClusterOnlyProtected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newStatusChangeTestDB([][]driver.Value{
+		{"i-1", "c1", "Running", true},
+		{"i-2", "c1", "Stopped", true},
+	})
+	defer db.Close()
+
+	// No gRPC client nor DB updates: the handlers must not reach them
+	api := APIServer{
+		logger:       zap.NewNop(),
+		sql:          sqlclient.NewSQLClientFromDB(db, zap.NewNop()),
+		eventService: events.NewEventService(fakeEventClient{}, zap.NewNop()),
+	}
+	engine := gin.New()
+	engine.POST("/clusters/:cluster_id/power_on", api.HandlerPowerOnCluster)
+	engine.POST("/clusters/:cluster_id/power_off", api.HandlerPowerOffCluster)
+
+	for _, action := range []string{"power_on", "power_off"} {
+		t.Run(action, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/clusters/c1/"+action, strings.NewReader(`{"triggered_by":"test"}`)))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var resp struct {
+				Status    inventory.InstanceStatus `json:"status"`
+				Instances []string                 `json:"instance_id"`
+				Skipped   []string                 `json:"skipped_instances"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Instances == nil || len(resp.Instances) != 0 {
+				t.Errorf("expected an empty instances list, got %v", resp.Instances)
+			}
+			if !slices.Equal(resp.Skipped, []string{"i-1", "i-2"}) {
+				t.Errorf("expected skipped instances [i-1 i-2], got %v", resp.Skipped)
+			}
+			if resp.Status != inventory.Running {
+				t.Errorf("expected status %s, got %s", inventory.Running, resp.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ResultTruncatedHeader is set on the list responses capped by CIQ_MAX_RESULTS
+	ResultTruncatedHeader = "X-Result-Truncated"
+	// TotalCountHeader is set on the count only list responses with the number of matching items
+	TotalCountHeader = "X-Total-Count"
+)
+
+// truncateResults caps the items of a list response. When the cap is hit, the
+// X-Result-Truncated header is set on the response and true is returned, so
+// the response body can signal it too. As it's applied on the final list,
+// filters and sorting are evaluated over every item before truncating.
+//
+// Parameters:
+// - c: Gin context of the request.
+// - items: Items of the response.
+// - maxResults: Maximum number of items. Unlimited if zero or negative.
+//
+// Returns:
+// - The first maxResults items.
+// - True if any item was removed.
+func truncateResults[T any](c *gin.Context, items []T, maxResults int) ([]T, bool) {
+	if maxResults <= 0 || len(items) <= maxResults {
+		return items, false
+	}
+
+	c.Header(ResultTruncatedHeader, "true")
+	return items[:maxResults], true
+}
+
+// paginate returns the page of items starting at offset with, at most, limit
+// items. Offsets beyond the last item return an empty page.
+//
+// Parameters:
+// - items: Every item of the list.
+// - limit: Page size.
+// - offset: Number of items skipped.
+//
+// Returns:
+// - The items of the page.
+func paginate[T any](items []T, limit int, offset int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
+
+// isCountOnly checks if a list request only wants the number of matching
+// items: HEAD requests and the ones with 'count=true'.
+//
+// Parameters:
+// - c: Gin context of the request.
+//
+// Returns:
+// - True if the items must not be sent.
+// - An error if the 'count' param is not a boolean.
+func isCountOnly(c *gin.Context) (bool, error) {
+	if c.Request.Method == http.MethodHead {
+		return true, nil
+	}
+	count, err := parseBoolParam(c, countParam)
+	if err != nil {
+		return false, err
+	}
+	return count != nil && *count, nil
+}
+
+// writeCount answers a count only list request with the number of matching
+// items on the X-Total-Count header. GET requests receive it on a CountResponse
+// body too, while HEAD requests are answered without body.
+func writeCount(c *gin.Context, count int) {
+	c.Header(TotalCountHeader, strconv.Itoa(count))
+	if c.Request.Method == http.MethodHead {
+		c.Status(http.StatusOK)
+		return
+	}
+	writeJSON(c, http.StatusOK, CountResponse{Count: count})
+}
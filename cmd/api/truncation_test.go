@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestWriteCount verifies the count only list requests receive the number of items without the items
+func TestWriteCount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	handler := func(c *gin.Context) {
+		countOnly, err := isCountOnly(c)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if countOnly {
+			writeCount(c, 3)
+			return
+		}
+		writeJSON(c, http.StatusOK, NewClusterListResponse(nil))
+	}
+	engine.GET("/clusters", handler)
+	engine.HEAD("/clusters", handler)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantCode   int
+		wantHeader string
+		wantBody   string
+	}{
+		{name: "Count", method: http.MethodGet, path: "/clusters?count=true", wantCode: http.StatusOK, wantHeader: "3", wantBody: `{"count":3}`},
+		{name: "HEAD", method: http.MethodHead, path: "/clusters", wantCode: http.StatusOK, wantHeader: "3", wantBody: ""},
+		{name: "Not count", method: http.MethodGet, path: "/clusters?count=false", wantCode: http.StatusOK, wantHeader: ""},
+		{name: "Invalid", method: http.MethodGet, path: "/clusters?count=maybe", wantCode: http.StatusBadRequest, wantHeader: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+			if got := rec.Header().Get(TotalCountHeader); got != tt.wantHeader {
+				t.Errorf("expected %s %q, got %q", TotalCountHeader, tt.wantHeader, got)
+			}
+			if tt.wantHeader != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
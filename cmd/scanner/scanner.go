@@ -15,6 +15,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/RHEcosystemAppEng/cluster-iq/internal/apiclient"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/config"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/credentials"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
@@ -74,7 +75,7 @@ func NewScanner(cfg *config.ScannerConfig, logger *zap.Logger) *Scanner {
 		inventory:     *inventory.NewInventory(),
 		stockers:      make([]stocker.Stocker, 0),
 		cfg:           cfg,
-		client:        http.Client{Transport: tr},
+		client:        http.Client{Transport: apiclient.WithBearerToken(tr, cfg.APIToken)},
 		APIURL:        cfg.APIURL,
 		logger:        logger,
 		credsFileHash: credsFileHash,
@@ -103,6 +104,7 @@ func (s *Scanner) readCloudProviderAccounts() error {
 			account.User,
 			account.Key,
 		)
+		newAccount.Aliases = account.Aliases
 		// Getting billing enabled flag from config
 		if account.BillingEnabled {
 			newAccount.EnableBilling()
@@ -121,7 +123,7 @@ func (s *Scanner) readCloudProviderAccounts() error {
 func (s *Scanner) createStockers() error {
 	var skippedAccounts int
 	var validStockers []stocker.Stocker
-	for _, account := range s.inventory.Accounts {
+	for _, account := range s.inventory.SortedAccounts() {
 		switch account.Provider {
 		case inventory.AWSProvider:
 			s.logger.Info("Processing AWS account", zap.String("account", account.Name))
@@ -282,7 +284,7 @@ func flatternAccount(account inventory.Account) ([]inventory.Cluster, []inventor
 	var clusters []inventory.Cluster
 	var instances []inventory.Instance
 	var expenses []inventory.Expense
-	for _, cluster := range account.Clusters {
+	for _, cluster := range account.SortedClusters() {
 		for _, instance := range cluster.Instances {
 			expenses = append(expenses, instance.Expenses...)
 			instances = append(instances, instance)
@@ -330,7 +332,7 @@ func (s *Scanner) postScannerInventory() error {
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(s.inventory.Accounts))
 
-	for _, account := range s.inventory.Accounts {
+	for _, account := range s.inventory.SortedAccounts() {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -399,7 +401,7 @@ func (s *Scanner) getInstancesForBillingUpdate() ([]inventory.Instance, error) {
 
 	requestURL := fmt.Sprintf("%s%s", s.cfg.APIURL, APIInstanceEndpoint+"/expense_update")
 
-	resp, err := http.Get(requestURL)
+	resp, err := s.client.Get(requestURL)
 	if err != nil {
 		s.logger.Error("Failed to get last expenses from API", zap.Error(err))
 		return nil, err
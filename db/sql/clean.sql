@@ -13,8 +13,11 @@ DROP FUNCTION update_instance_daily_costs_after_delete;
 DROP FUNCTION update_cluster_total_costs;
 
 -- Drop tables
+DROP TABLE request_audit_log;
 DROP TABLE tags;
 DROP TABLE expenses;
+DROP TABLE cluster_status_history;
+DROP TABLE instance_status_history;
 DROP TABLE instances;
 DROP TABLE clusters;
 DROP TABLE accounts;
@@ -39,7 +39,8 @@ INSERT INTO
 VALUES
   ('Running'),
   ('Stopped'),
-  ('Terminated')
+  ('Terminated'),
+  ('Unknown')
 ;
 
 
@@ -53,7 +54,11 @@ CREATE TABLE IF NOT EXISTS accounts (
   total_cost NUMERIC(12,2) DEFAULT 0.0,
   last_15_days_cost NUMERIC(12,2) DEFAULT 0.0,
   last_month_cost NUMERIC(12,2) DEFAULT 0.0,
-  current_month_so_far_cost NUMERIC(12,2) DEFAULT 0.0
+  current_month_so_far_cost NUMERIC(12,2) DEFAULT 0.0,
+  scan_start_timestamp TIMESTAMP WITH TIME ZONE DEFAULT '0001-01-01 00:00:00+00',
+  scan_end_timestamp TIMESTAMP WITH TIME ZONE DEFAULT '0001-01-01 00:00:00+00',
+  throttled_requests INTEGER DEFAULT 0,
+  aliases TEXT[] NOT NULL DEFAULT '{}'
 );
 
 
@@ -88,12 +93,16 @@ CREATE TABLE IF NOT EXISTS instances (
   instance_type TEXT,
   availability_zone TEXT,
   status TEXT REFERENCES status(value),
+  provider_state TEXT,
   cluster_id TEXT REFERENCES clusters(id) ON DELETE CASCADE,
+  iam_role TEXT,
   last_scan_timestamp TIMESTAMP WITH TIME ZONE,
   creation_timestamp TIMESTAMP WITH TIME ZONE,
   age INT,
   daily_cost NUMERIC(12,2) DEFAULT 0.0,
-  total_cost NUMERIC(12,2) DEFAULT 0.0
+  total_cost NUMERIC(12,2) DEFAULT 0.0,
+  cpu_utilization NUMERIC(5,2),
+  protected BOOLEAN NOT NULL DEFAULT false
 );
 
 
@@ -106,6 +115,22 @@ CREATE TABLE IF NOT EXISTS tags (
 );
 
 
+-- Clusters status history
+CREATE TABLE IF NOT EXISTS cluster_status_history (
+  cluster_id TEXT REFERENCES clusters(id) ON DELETE CASCADE,
+  status TEXT REFERENCES status(value),
+  timestamp TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+-- Instances status history
+CREATE TABLE IF NOT EXISTS instance_status_history (
+  instance_id TEXT REFERENCES instances(id) ON DELETE CASCADE,
+  status TEXT REFERENCES status(value),
+  timestamp TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
 -- Instances expenses
 CREATE TABLE IF NOT EXISTS expenses (
   instance_id TEXT REFERENCES instances(id) ON DELETE CASCADE,
@@ -170,6 +195,18 @@ CREATE TABLE IF NOT EXISTS audit_logs (
   CONSTRAINT audit_logs_resource_type_check CHECK ((resource_type = ANY (ARRAY['cluster'::TEXT, 'instance'::TEXT])))
 );
 
+-- Append-only audit trail of the mutating API requests
+CREATE TABLE IF NOT EXISTS request_audit_log (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  timestamp TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  actor TEXT NOT NULL,
+  method TEXT NOT NULL,
+  route TEXT NOT NULL,
+  path TEXT NOT NULL,
+  resources TEXT[] DEFAULT '{}',
+  status INTEGER NOT NULL
+);
+
 -- ## Functions ##
 -- Updates the total cost of an instance after a new expense record is inserted
 CREATE OR REPLACE FUNCTION update_instance_total_costs_after_insert()
@@ -323,6 +360,36 @@ BEGIN
 END;
 $$;
 
+-- Records the cluster status when it's created or when its status changes
+CREATE OR REPLACE FUNCTION record_cluster_status_change()
+  RETURNS TRIGGER
+  LANGUAGE PLPGSQL
+  AS
+$$
+BEGIN
+  IF TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM NEW.status THEN
+    INSERT INTO cluster_status_history (cluster_id, status)
+    VALUES (NEW.id, NEW.status);
+  END IF;
+  RETURN NEW;
+END;
+$$;
+
+-- Records the instance status when it's created or when its status changes
+CREATE OR REPLACE FUNCTION record_instance_status_change()
+  RETURNS TRIGGER
+  LANGUAGE PLPGSQL
+  AS
+$$
+BEGIN
+  IF TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM NEW.status THEN
+    INSERT INTO instance_status_history (instance_id, status)
+    VALUES (NEW.id, NEW.status);
+  END IF;
+  RETURN NEW;
+END;
+$$;
+
 -- ## Maintenance Functions ##
 -- Marks instances as 'Terminated' if they haven't been scanned in the last 24 hours
 CREATE OR REPLACE FUNCTION check_terminated_instances()
@@ -402,3 +469,17 @@ AFTER UPDATE
 ON clusters
 FOR EACH ROW
   EXECUTE PROCEDURE update_account_cost_info();
+
+-- Trigger to record the cluster status history
+CREATE TRIGGER record_cluster_status_change
+AFTER INSERT OR UPDATE
+ON clusters
+FOR EACH ROW
+  EXECUTE PROCEDURE record_cluster_status_change();
+
+-- Trigger to record the instance status history
+CREATE TRIGGER record_instance_status_change
+AFTER INSERT OR UPDATE
+ON instances
+FOR EACH ROW
+  EXECUTE PROCEDURE record_instance_status_change();
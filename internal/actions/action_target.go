@@ -14,6 +14,9 @@ type ActionTarget struct {
 
 	// Instances is a list of instance IDs associated with the target cluster.
 	Instances []string `db:"instances" json:"instances"`
+
+	// SkippedInstances is the list of protected instance IDs of the target cluster, which are excluded from the action.
+	SkippedInstances []string `db:"skipped_instances" json:"skippedInstances,omitempty"`
 }
 
 // NewActionTarget creates and returns a new instance of ActionTarget.
@@ -66,3 +69,11 @@ func (at *ActionTarget) GetClusterID() string {
 func (at *ActionTarget) GetInstances() []string {
 	return at.Instances
 }
+
+// GetSkippedInstances returns the list of protected instance IDs excluded from the action.
+//
+// Returns:
+// - A slice of strings containing the skipped instance IDs.
+func (at *ActionTarget) GetSkippedInstances() []string {
+	return at.SkippedInstances
+}
@@ -0,0 +1,31 @@
+// Package apiclient contains the helpers shared by the ClusterIQ API clients (scanner and agent)
+package apiclient
+
+import "net/http"
+
+// bearerTokenTransport adds the API token to every request sent by the wrapped transport
+type bearerTokenTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+// RoundTrip sends the request with the "Authorization: Bearer <token>" header.
+// The request is cloned, as RoundTrippers must not modify it
+func (t bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// WithBearerToken wraps the transport for authenticating the requests with the
+// API token (CIQ_API_TOKEN). The transport is returned as it is if the token
+// is empty, as the API doesn't require authentication then
+func WithBearerToken(base http.RoundTripper, token string) http.RoundTripper {
+	if token == "" {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return bearerTokenTransport{token: token, base: base}
+}
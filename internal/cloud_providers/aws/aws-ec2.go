@@ -228,10 +228,11 @@ func EC2InstanceToInventoryInstance(instance *ec2.Instance) *inventory.Instance
 	provider := inventory.AWSProvider
 	instanceType := *instance.InstanceType
 	availabilityZone := *instance.Placement.AvailabilityZone
-	status := inventory.AsInstanceStatus(*instance.State.Name)
+	providerState := inventory.ProviderState(*instance.State.Name)
+	status := providerState.Status()
 	clusterID := inventory.GetClusterIDFromTags(tags)
 	creationTimestamp := getInstanceCreationTimestamp(*instance)
-	return inventory.NewInstance(
+	newInstance := inventory.NewInstance(
 		id,
 		name,
 		provider,
@@ -242,6 +243,15 @@ func EC2InstanceToInventoryInstance(instance *ec2.Instance) *inventory.Instance
 		tags,
 		creationTimestamp,
 	)
+
+	newInstance.ProviderState = providerState
+
+	// Instance Profile attached to the instance (if any)
+	if instance.IamInstanceProfile != nil && instance.IamInstanceProfile.Arn != nil {
+		newInstance.IAMRole = *instance.IamInstanceProfile.Arn
+	}
+
+	return newInstance
 }
 
 // getInstanceCreationTimestamp retrieves the creation timestamp of an EC2 instance.
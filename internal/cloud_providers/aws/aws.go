@@ -2,9 +2,11 @@ package cloudprovider
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sts"
 )
@@ -36,6 +38,8 @@ type AWSConnection struct {
 	user         string
 	password     string
 	region       string
+	// throttledRequests counts the requests throttled by the AWS API limits since the connection was created
+	throttledRequests int64
 }
 
 // AWSConnectionOption defines the options for creating different sets of AWS services connections
@@ -108,6 +112,13 @@ func (conn *AWSConnection) newAWSession() error {
 		return err
 	}
 
+	// Counting throttled requests. Sessions are re-created on every region switch, so the counter lives in the connection
+	conn.awsSession.Handlers.Retry.PushBack(func(r *request.Request) {
+		if request.IsErrorThrottle(r.Error) {
+			atomic.AddInt64(&conn.throttledRequests, 1)
+		}
+	})
+
 	return nil
 }
 
@@ -122,6 +133,11 @@ func (conn *AWSConnection) SetRegion(region string) error {
 	return conn.Connect()
 }
 
+// GetThrottledRequests returns how many requests were throttled by the AWS API limits on this connection
+func (conn *AWSConnection) GetThrottledRequests() int {
+	return int(atomic.LoadInt64(&conn.throttledRequests))
+}
+
 // GetAccountID returns the accountID obtained from AWS for the account on the current AWSConnection
 func (conn *AWSConnection) GetAccountID() string {
 	return conn.accountID
@@ -7,7 +7,9 @@ import env "github.com/caarlos0/env/v11"
 type ExecutorAgentServiceConfig struct {
 	// APIURL refers to the ClusterIQ API Endpoint
 	APIURL string `env:"CIQ_API_URL,required"`
-	DBURL  string `env:"CIQ_DB_URL,required"`
+	// APIToken is the bearer token for authenticating on the API, if required
+	APIToken string `env:"CIQ_API_TOKEN"`
+	DBURL    string `env:"CIQ_DB_URL,required"`
 	// Credentials for accessing the cloud providers accounts
 	Credentials CloudCredentialsConfig
 }
@@ -21,6 +23,8 @@ type InstantAgentServiceConfig struct {
 type ScheduleAgentServiceConfig struct {
 	// APIURL refers to the ClusterIQ API Endpoint
 	APIURL string `env:"CIQ_API_URL,required"`
+	// APIToken is the bearer token for authenticating on the API, if required
+	APIToken string `env:"CIQ_API_TOKEN"`
 	// PollingInterval defines the amount of time between Schedule refreshes (polling frecuency)
 	PollingInterval int `env:"CIQ_AGENT_POLLING_SECONDS_INTERVAL,required"`
 }
@@ -30,7 +34,7 @@ type AgentConfig struct {
 	ExecutorAgentServiceConfig
 	ScheduleAgentServiceConfig
 	InstantAgentServiceConfig
-	LogLevel string `env:"CIQ_LOG_LEVEL,required"`
+	LogLevel string `env:"CIQ_LOG_LEVEL" envDefault:"INFO"`
 }
 
 // LoadAgentConfig evaluates and return the AgentConfig object
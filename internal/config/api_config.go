@@ -1,21 +1,225 @@
 package config
 
-import env "github.com/caarlos0/env/v11"
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	env "github.com/caarlos0/env/v11"
+)
 
 // APIServerConfig defines the config parameters for the ClusterIQ API
 type APIServerConfig struct {
-	ListenURL string `env:"CIQ_API_LISTEN_URL,required"`
-	AgentURL  string `env:"CIQ_AGENT_URL,required"`
-	DBURL     string `env:"CIQ_DB_URL,required"`
-	LogLevel  string `env:"CIQ_LOG_LEVEL,required"`
+	// ListenURL is the address the API is bound to. Should bind every interface
+	// on containers, regardless of the address the clients reach it at
+	ListenURL string `env:"CIQ_API_LISTEN_URL,notEmpty" envDefault:"0.0.0.0:8080"`
+	// PublicURL is the URL the clients reach the API at (e.g. through a route or
+	// ingress). Only used on the logs and the OpenAPI spec, never for binding
+	PublicURL string `env:"CIQ_API_PUBLIC_URL"`
+	AgentURL  string `env:"CIQ_AGENT_URL,required,notEmpty"`
+	DBURL     string `env:"CIQ_DB_URL,required,notEmpty"`
+	// LogLevel is the logs verbosity (debug, info, warn or error)
+	LogLevel string `env:"CIQ_LOG_LEVEL" envDefault:"INFO"`
+	// LogSkipPaths is the list of request paths not logged (e.g. the probes and the metrics scraping)
+	LogSkipPaths []string `env:"CIQ_LOG_SKIP_PATHS" envSeparator:"," envDefault:"/api/v1/healthcheck,/api/v1/healthz,/api/v1/readyz,/metrics"`
+	// APIToken is the bearer token required on every request. The API doesn't require authentication if empty
+	APIToken string `env:"CIQ_API_TOKEN"`
+	// APIPublicEndpoints is the list of route patterns ("[METHOD ]<route>") served without the API token
+	APIPublicEndpoints []string `env:"CIQ_API_PUBLIC_ENDPOINTS" envSeparator:"," envDefault:"/api/v1/healthz,/api/v1/readyz"`
+	// CORSAllowedOrigins is the list of origins allowed to call the API from a browser. Every origin is allowed with "*"
+	CORSAllowedOrigins []string `env:"CIQ_CORS_ALLOWED_ORIGINS" envSeparator:"," envDefault:"*"`
+	// DBUser is the DB user, replacing the one on CIQ_DB_URL. The one on CIQ_DB_URL is used if empty
+	DBUser string `env:"CIQ_DB_USER"`
+	// DBTLS enables TLS on the DB connections, replacing the TLS settings on CIQ_DB_URL
+	DBTLS bool `env:"CIQ_DB_TLS" envDefault:"false"`
+	// DBTLSSkipVerify encrypts the DB connections without verifying the server certificate
+	DBTLSSkipVerify bool `env:"CIQ_DB_TLS_SKIP_VERIFY" envDefault:"false"`
+	// DBCAFile is the path of the PEM CA bundle of the DB server certificate. The system roots are used if empty
+	DBCAFile string `env:"CIQ_DB_CA_FILE"`
+	// DBPoolSize is the maximum number of open DB connections. Unlimited if zero
+	DBPoolSize int `env:"CIQ_DB_POOL_SIZE" envDefault:"0"`
+	// DBConnectAttempts is the maximum number of DB connection attempts at startup
+	DBConnectAttempts int `env:"CIQ_DB_CONNECT_ATTEMPTS" envDefault:"5"`
+	// DBConnectBaseDelay is the wait after the first failed DB connection attempt, doubled on every retry
+	DBConnectBaseDelay time.Duration `env:"CIQ_DB_CONNECT_BASE_DELAY" envDefault:"1s"`
+	// DBTimeout is the maximum duration of every DB statement and connection attempt. Disabled if zero
+	DBTimeout time.Duration `env:"CIQ_DB_TIMEOUT" envDefault:"5s"`
+	// ExpectedAccounts is the list of accounts that the scanner should cover
+	ExpectedAccounts []string `env:"CIQ_EXPECTED_ACCOUNTS" envSeparator:","`
+	// EnablePprof serves the Go runtime profiles (net/http/pprof) under /debug/pprof/
+	EnablePprof bool `env:"CIQ_ENABLE_PPROF" envDefault:"false"`
+	// ServerTiming enables the Server-Timing header on the responses
+	ServerTiming bool `env:"CIQ_SERVER_TIMING" envDefault:"false"`
+	// DisabledEndpoints is the list of route patterns ("[METHOD ]<route>") that won't be served
+	DisabledEndpoints []string `env:"CIQ_DISABLED_ENDPOINTS" envSeparator:","`
+	// JSONEscape escapes the HTML characters (<, > and &) of the JSON responses
+	JSONEscape bool `env:"CIQ_JSON_ESCAPE" envDefault:"true"`
+	// HiddenFields is the list of JSON fields removed from every response, and their columns from the CSV exports
+	HiddenFields []string `env:"CIQ_HIDDEN_FIELDS" envSeparator:","`
+	// InstanceDisplayNameOrder is the precedence of the sources (tag, name, id) used for resolving the instances display name
+	InstanceDisplayNameOrder []string `env:"CIQ_INSTANCE_DISPLAY_NAME_ORDER" envSeparator:"," envDefault:"tag,name,id"`
+	// DefaultTimezone is the IANA timezone used for the date filters when the requests don't specify one
+	DefaultTimezone string `env:"CIQ_DEFAULT_TZ" envDefault:"UTC"`
+	// AuditToken is the bearer token required for reading the requests audit trail. The audit endpoint is disabled if empty
+	AuditToken string `env:"CIQ_AUDIT_TOKEN"`
+	// HTTPCacheMaxAge is the max-age of the Cache-Control header of the list endpoints. Disabled if zero
+	HTTPCacheMaxAge time.Duration `env:"CIQ_HTTP_CACHE_MAX_AGE" envDefault:"60s"`
+	// GzipMinSize is the minimum size (bytes) of the response bodies compressed with gzip. Compression is disabled if negative
+	GzipMinSize int `env:"CIQ_GZIP_MIN_SIZE" envDefault:"1024"`
+	// CacheTTL is the freshness window of the in-memory instances list. Disabled if zero
+	CacheTTL time.Duration `env:"CIQ_CACHE_TTL" envDefault:"30s"`
+	// BackgroundRefresh refreshes the in-memory instances list in background, so the requests don't load it
+	BackgroundRefresh bool `env:"CIQ_BACKGROUND_REFRESH" envDefault:"false"`
+	// BackgroundRefreshInterval is the time between the background refreshes. Should be shorter than CacheTTL
+	BackgroundRefreshInterval time.Duration `env:"CIQ_BACKGROUND_REFRESH_INTERVAL" envDefault:"20s"`
+	// ExcludeTag is the tag key of the instances filtered from every list response unless requested
+	ExcludeTag string `env:"CIQ_EXCLUDE_TAG"`
+	// MaxResults is the maximum number of items returned by the list endpoints. Unlimited if zero
+	MaxResults int `env:"CIQ_MAX_RESULTS" envDefault:"10000"`
+	// RateLimit is the maximum sustained requests per second of a client on every route. Disabled if zero
+	RateLimit float64 `env:"CIQ_RATE_LIMIT" envDefault:"0"`
+	// RateBurst is the maximum requests of a client on a route at once. Defaults to CIQ_RATE_LIMIT if zero
+	RateBurst int `env:"CIQ_RATE_BURST" envDefault:"0"`
+	// TrustedProxies is the list of proxy CIDRs (or IPs) whose X-Forwarded-For header sets the client IP. No proxy is trusted if empty
+	TrustedProxies []string `env:"CIQ_TRUSTED_PROXIES" envSeparator:","`
+	// MaxInventoryStaleness is the maximum age of the last scan before the API is reported as not ready. Disabled if zero
+	MaxInventoryStaleness time.Duration `env:"CIQ_MAX_INVENTORY_STALENESS"`
+	// StreamMaxConnections is the maximum number of /stream clients at once. /stream is disabled if zero
+	StreamMaxConnections int `env:"CIQ_STREAM_MAX_CONNECTIONS" envDefault:"100"`
+	// StreamInterval is the time between the checks of the inventory overview changes for the /stream clients
+	StreamInterval time.Duration `env:"CIQ_STREAM_INTERVAL" envDefault:"5s"`
+	// StreamKeepAlive is the time between the keep-alive comments sent to the /stream clients
+	StreamKeepAlive time.Duration `env:"CIQ_STREAM_KEEPALIVE" envDefault:"15s"`
+	// ServeEmptyInventory serves empty lists instead of 503 Service Unavailable before the first scan
+	ServeEmptyInventory bool `env:"CIQ_SERVE_EMPTY_INVENTORY" envDefault:"false"`
+	// ShutdownGracePeriod is the time waited for the in-flight requests on shutdown
+	ShutdownGracePeriod time.Duration `env:"CIQ_SHUTDOWN_GRACE_PERIOD" envDefault:"10s"`
+	// CostDimensionsFile is the path of the file mapping the cost dimensions to the tag key of every provider
+	CostDimensionsFile string `env:"CIQ_COST_DIMENSIONS_FILE"`
+	// CostOutlierDeviations is the default number of standard deviations above the account's mean cost for the cost outliers
+	CostOutlierDeviations float64 `env:"CIQ_COST_OUTLIER_DEVIATIONS" envDefault:"2"`
 }
 
-// LoadAPIServerConfig evaluates and return the APIServerConfig Object
+// LoadAPIServerConfig evaluates and return the APIServerConfig Object. Every
+// missing or empty required variable is reported at once, so they can be
+// fixed in a single deployment
 func LoadAPIServerConfig() (*APIServerConfig, error) {
 	cfg := &APIServerConfig{}
-	err := env.Parse(cfg)
-	if err != nil {
+	if err := errors.Join(env.Parse(cfg), cfg.validate()); err != nil {
 		return nil, err
 	}
 	return cfg, nil
 }
+
+// validate checks the format of the API, Agent and DB addresses. Empty values
+// are skipped, as they are already reported by the env parsing
+func (c APIServerConfig) validate() error {
+	var errs []error
+	if c.ListenURL != "" {
+		if err := validateHostPort(c.ListenURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid CIQ_API_LISTEN_URL '%s': %w", c.ListenURL, err))
+		}
+	}
+	if c.PublicURL != "" {
+		if err := validatePublicURL(c.PublicURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid CIQ_API_PUBLIC_URL '%s': %w", c.PublicURL, err))
+		}
+	}
+	// gRPC targets with a name resolver scheme (e.g. "dns:///agent:50051") are not checked
+	if c.AgentURL != "" && !strings.Contains(c.AgentURL, "://") {
+		if err := validateHostPort(c.AgentURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid CIQ_AGENT_URL '%s': %w", c.AgentURL, err))
+		}
+	}
+	if err := validateDBURL(c.DBURL); err != nil {
+		errs = append(errs, fmt.Errorf("invalid CIQ_DB_URL: %w", err))
+	}
+	if c.BackgroundRefresh && c.BackgroundRefreshInterval <= 0 {
+		errs = append(errs, fmt.Errorf("invalid CIQ_BACKGROUND_REFRESH_INTERVAL '%s': must be positive", c.BackgroundRefreshInterval))
+	}
+	if c.StreamMaxConnections < 0 {
+		errs = append(errs, fmt.Errorf("invalid CIQ_STREAM_MAX_CONNECTIONS (%d): can't be negative", c.StreamMaxConnections))
+	}
+	if c.StreamMaxConnections > 0 && (c.StreamInterval <= 0 || c.StreamKeepAlive <= 0) {
+		errs = append(errs, fmt.Errorf("invalid CIQ_STREAM_INTERVAL '%s' or CIQ_STREAM_KEEPALIVE '%s': must be positive", c.StreamInterval, c.StreamKeepAlive))
+	}
+	if c.RateLimit < 0 || c.RateBurst < 0 {
+		errs = append(errs, fmt.Errorf("invalid CIQ_RATE_LIMIT (%g) or CIQ_RATE_BURST (%d): can't be negative", c.RateLimit, c.RateBurst))
+	}
+	for _, proxy := range c.TrustedProxies {
+		if err := validateTrustedProxy(proxy); err != nil {
+			errs = append(errs, fmt.Errorf("invalid CIQ_TRUSTED_PROXIES entry '%s': %w", proxy, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateTrustedProxy checks the proxy is a CIDR or a single IP
+func validateTrustedProxy(proxy string) error {
+	if strings.Contains(proxy, "/") {
+		_, _, err := net.ParseCIDR(proxy)
+		return err
+	}
+	if net.ParseIP(proxy) == nil {
+		return errors.New("must be a CIDR or an IP")
+	}
+	return nil
+}
+
+// validateHostPort checks the address is "[host]:port" with a valid port number
+func validateHostPort(address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	return validatePort(port)
+}
+
+// validatePublicURL checks the URL is an absolute HTTP(S) URL
+func validatePublicURL(publicURL string) error {
+	u, err := url.Parse(publicURL)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// validateDBURL checks the DB connection URL. The port is checked only if it's
+// set, and the key/value connection strings ("host=... port=...") are not checked
+func validateDBURL(dbURL string) error {
+	if !strings.Contains(dbURL, "://") {
+		return nil
+	}
+
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		// The parse error includes the URL, which can contain the DB password
+		return errors.New("malformed URL")
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("missing host")
+	}
+	if port := u.Port(); port != "" {
+		return validatePort(port)
+	}
+	return nil
+}
+
+// validatePort checks the port is a number between 1 and 65535
+func validatePort(port string) error {
+	number, err := strconv.Atoi(port)
+	if err != nil || number < 1 || number > 65535 {
+		return fmt.Errorf("invalid port '%s'", port)
+	}
+	return nil
+}
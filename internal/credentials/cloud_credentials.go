@@ -8,6 +8,7 @@ import (
 type AccountConfig struct {
 	Name           string
 	Provider       inventory.CloudProvider
+	Aliases        []string
 	User           string
 	Key            string
 	BillingEnabled bool
@@ -29,6 +30,7 @@ func ReadCloudAccounts(credsFile string) ([]AccountConfig, error) {
 		account := AccountConfig{
 			Name:           section.Name(),
 			Provider:       inventory.GetCloudProvider(section.Key("provider").String()),
+			Aliases:        section.Key("aliases").Strings(","),
 			User:           section.Key("user").String(),
 			Key:            section.Key("key").String(),
 			BillingEnabled: section.Key("billing_enabled").MustBool(),
@@ -5,6 +5,7 @@ import (
 
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/actions"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/models"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -69,6 +70,26 @@ type AuditEvent struct {
 	TriggeredBy string `json:"triggered_by"`
 }
 
+// RequestAuditEntry is an entry of the append-only audit trail of the mutating API requests
+type RequestAuditEntry struct {
+	// Unique identifier for the entry.
+	ID int64 `db:"id" json:"id"`
+	// UTC timestamp of when the request was served.
+	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+	// Identifier of the auth token used by the request, or "anonymous".
+	Actor string `db:"actor" json:"actor"`
+	// HTTP method of the request.
+	Method string `db:"method" json:"method"`
+	// Matched API route (e.g. "/api/v1/clusters/:cluster_id/power_off").
+	Route string `db:"route" json:"route"`
+	// Requested path.
+	Path string `db:"path" json:"path"`
+	// Affected resources, as "param=value" from the route params.
+	Resources pq.StringArray `db:"resources" json:"resources"`
+	// Response status code.
+	Status int `db:"status" json:"status"`
+}
+
 // SystemAuditEvent extends AuditEvent with system-specific fields.
 type SystemAuditEvent struct {
 	AuditEvent
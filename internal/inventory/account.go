@@ -1,8 +1,12 @@
 package inventory
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // Account defines an infrastructure provider account
@@ -17,6 +21,10 @@ type Account struct {
 	// name can't belong to same Inventory
 	Name string `db:"name" json:"name"`
 
+	// Friendlier names of the account. They resolve to the account when no
+	// account is named like them, as the exact name always wins
+	Aliases pq.StringArray `db:"aliases" json:"aliases,omitempty"`
+
 	// Infrastructure provider identifier.
 	Provider CloudProvider `db:"provider" json:"provider"`
 
@@ -29,6 +37,15 @@ type Account struct {
 	// Last scan timestamp of the account
 	LastScanTimestamp time.Time `db:"last_scan_timestamp" json:"lastScanTimestamp"`
 
+	// Timestamp when the last scan of the account started
+	ScanStartTimestamp time.Time `db:"scan_start_timestamp" json:"scanStartTimestamp"`
+
+	// Timestamp when the last scan of the account finished
+	ScanEndTimestamp time.Time `db:"scan_end_timestamp" json:"scanEndTimestamp"`
+
+	// Number of provider API requests throttled (rate limited) during the last scan
+	ThrottledRequests int `db:"throttled_requests" json:"throttledRequests"`
+
 	// Account's username
 	user string
 
@@ -47,6 +64,9 @@ type Account struct {
 	// Current month so far cost
 	CurrentMonthSoFarCost float64 `db:"current_month_so_far_cost" json:"currentMonthSoFarCost"`
 
+	// Total cost (US Dollars) of the account's instances by their status. Computed from its instances
+	CostByStatus map[InstanceStatus]float64 `db:"-" json:"costByStatus,omitempty"`
+
 	// Billing information flag
 	billingEnabled bool
 }
@@ -86,6 +106,22 @@ func (a Account) IsClusterOnAccount(id string) bool {
 	return ok
 }
 
+// SortedClusters returns the account's clusters sorted by name and ID, so
+// every walk of the clusters map follows the same order
+func (a Account) SortedClusters() []*Cluster {
+	clusters := make([]*Cluster, 0, len(a.Clusters))
+	for _, cluster := range a.Clusters {
+		clusters = append(clusters, cluster)
+	}
+	slices.SortFunc(clusters, func(x, y *Cluster) int {
+		if c := cmp.Compare(x.Name, y.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.ID, y.ID)
+	})
+	return clusters
+}
+
 // AddCluster adds a cluster to the stock
 func (a *Account) AddCluster(cluster *Cluster) error {
 	if a.IsClusterOnAccount(cluster.ID) {
@@ -97,6 +133,25 @@ func (a *Account) AddCluster(cluster *Cluster) error {
 	return nil
 }
 
+// MatchesSearch checks if the account's ID, name or any of its aliases contains the query (case insensitive)
+func (a Account) MatchesSearch(query string) bool {
+	return matchesSearch(query, append([]string{a.ID, a.Name}, a.Aliases...)...)
+}
+
+// ModifiedSince checks if the account was scanned after the given time.
+// Accounts without a scan timestamp are always considered modified
+func (a Account) ModifiedSince(since time.Time) bool {
+	return isModifiedSince(since, a.LastScanTimestamp)
+}
+
+// LastScanDuration returns how long the last scan of the account took. Zero if the scan timing is unknown
+func (a Account) LastScanDuration() time.Duration {
+	if a.ScanStartTimestamp.IsZero() || a.ScanEndTimestamp.Before(a.ScanStartTimestamp) {
+		return 0
+	}
+	return a.ScanEndTimestamp.Sub(a.ScanStartTimestamp)
+}
+
 // EnableBilling enables the billing information scanner for this account
 func (a *Account) EnableBilling() {
 	a.billingEnabled = true
@@ -116,7 +171,7 @@ func (a Account) IsBillingEnabled() bool {
 func (a Account) PrintAccount() {
 	fmt.Printf("\tAccount: %s[%s] #Clusters: %d\n", a.Name, a.ID, len(a.Clusters))
 
-	for _, cluster := range a.Clusters {
+	for _, cluster := range a.SortedClusters() {
 		cluster.PrintCluster()
 	}
 }
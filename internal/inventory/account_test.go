@@ -2,6 +2,7 @@ package inventory
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -156,3 +157,59 @@ func TestPrintAccount(t *testing.T) {
 	acc.PrintAccount()
 
 }
+
+// TestAccountModifiedSince verifies that the last scan timestamp governs account modifications
+func TestAccountModifiedSince(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+
+	account := NewAccount("0000-11A", "testAccount", AWSProvider, "user", "password")
+	assert.True(t, account.ModifiedSince(since))
+
+	account.LastScanTimestamp = since.Add(-1 * time.Hour)
+	assert.False(t, account.ModifiedSince(since))
+
+	// Accounts without timestamps are always returned
+	assert.True(t, Account{}.ModifiedSince(since))
+}
+
+// TestAccountLastScanDuration verifies the scan duration calculation from the scan timestamps
+func TestAccountLastScanDuration(t *testing.T) {
+	start := time.Now().Add(-1 * time.Hour)
+
+	account := NewAccount("0000-11A", "testAccount", AWSProvider, "user", "password")
+	assert.Equal(t, time.Duration(0), account.LastScanDuration())
+
+	account.ScanStartTimestamp = start
+	account.ScanEndTimestamp = start.Add(90 * time.Second)
+	assert.Equal(t, 90*time.Second, account.LastScanDuration())
+
+	// Scans still running (end before start) have unknown duration
+	account.ScanEndTimestamp = start.Add(-1 * time.Minute)
+	assert.Equal(t, time.Duration(0), account.LastScanDuration())
+}
+
+// TestAccountMatchesSearch verifies the account fields used by the search
+func TestAccountMatchesSearch(t *testing.T) {
+	account := Account{ID: "123456789012", Name: "production-account", Aliases: []string{"prod"}}
+
+	assert.True(t, account.MatchesSearch("Production"))
+	assert.True(t, account.MatchesSearch("PROD"))
+	assert.True(t, account.MatchesSearch("3456"))
+	assert.False(t, account.MatchesSearch("staging"))
+}
+
+// TestSortedClusters verifies the account's clusters are walked by name and ID
+func TestSortedClusters(t *testing.T) {
+	account := Account{Clusters: map[string]*Cluster{
+		"c-2": {ID: "c-2", Name: "dev"},
+		"c-3": {ID: "c-3", Name: "prod"},
+		"c-1": {ID: "c-1", Name: "dev"},
+	}}
+
+	var ids []string
+	for _, cluster := range account.SortedClusters() {
+		ids = append(ids, cluster.ID)
+	}
+	assert.Equal(t, []string{"c-1", "c-2", "c-3"}, ids)
+	assert.Empty(t, Account{}.SortedClusters())
+}
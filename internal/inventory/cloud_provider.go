@@ -1,6 +1,10 @@
 package inventory
 
-import "strings"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 // CloudProvider defines the cloud provider of the instance
 type CloudProvider string
@@ -9,16 +13,16 @@ const (
 	// AWSProvider - Amazon Web Services Cloud Provider
 	AWSProvider CloudProvider = "AWS"
 	// AzureProvider - Microsoft Azure Cloud Provider
-	AzureProvider = "Azure"
+	AzureProvider CloudProvider = "Azure"
 	// GCPProvider - Google Cloud Platform Cloud Provider
-	GCPProvider = "GCP"
+	GCPProvider CloudProvider = "GCP"
 	// UnknownProvider - Unknown Platform Cloud Provider
-	UnknownProvider = "UNKNOWN"
+	UnknownProvider CloudProvider = "UNKNOWN"
 )
 
 // GetCloudProvider checks a incoming string and returns the corresponding inventory.CloudProvider value
 func GetCloudProvider(provider string) CloudProvider {
-	switch strings.ToUpper(provider) {
+	switch strings.ToUpper(strings.TrimSpace(provider)) {
 	case "AWS":
 		return AWSProvider
 	case "GCP":
@@ -29,3 +33,31 @@ func GetCloudProvider(provider string) CloudProvider {
 		return UnknownProvider
 	}
 }
+
+// UnmarshalJSON reads the provider of the posted resources case insensitively,
+// so every provider is stored with its canonical name. Unrecognized
+// providers are stored as UnknownProvider
+func (p *CloudProvider) UnmarshalJSON(data []byte) error {
+	var provider string
+	if err := json.Unmarshal(data, &provider); err != nil {
+		return err
+	}
+	*p = GetCloudProvider(provider)
+	return nil
+}
+
+// Scan implements sql.Scanner, so the providers stored before being normalized
+// are read with their canonical name too. NULL is read as UnknownProvider
+func (p *CloudProvider) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*p = UnknownProvider
+	case string:
+		*p = GetCloudProvider(value)
+	case []byte:
+		*p = GetCloudProvider(string(value))
+	default:
+		return fmt.Errorf("can't scan %T into CloudProvider", src)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package inventory
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -15,6 +16,7 @@ func TestGetCloudProvider(t *testing.T) {
 		{"GCP", GCPProvider},
 		{"azure", AzureProvider},
 		{"AZURE", AzureProvider},
+		{" Azure ", AzureProvider},
 		{"unknown", UnknownProvider},
 		{"", UnknownProvider},
 		{"DigitalOcean", UnknownProvider},
@@ -29,3 +31,53 @@ func TestGetCloudProvider(t *testing.T) {
 		})
 	}
 }
+
+// TestCloudProviderUnmarshalJSON verifies the posted providers are normalized to their canonical name
+func TestCloudProviderUnmarshalJSON(t *testing.T) {
+	var accounts []Account
+	body := `[{"name":"a1","provider":"aws"},{"name":"a2","provider":"AZURE"},{"name":"a3","provider":"gcp"},{"name":"a4","provider":"DigitalOcean"}]`
+	if err := json.Unmarshal([]byte(body), &accounts); err != nil {
+		t.Fatalf("can't decode accounts: %v", err)
+	}
+
+	expected := []CloudProvider{AWSProvider, AzureProvider, GCPProvider, UnknownProvider}
+	for i, account := range accounts {
+		if account.Provider != expected[i] {
+			t.Errorf("account %s provider = %v; want %v", account.Name, account.Provider, expected[i])
+		}
+	}
+
+	var provider CloudProvider
+	if err := json.Unmarshal([]byte(`42`), &provider); err == nil {
+		t.Errorf("expected an error decoding a non string provider")
+	}
+}
+
+// TestCloudProviderScan verifies the stored providers are read with their canonical name
+func TestCloudProviderScan(t *testing.T) {
+	tests := []struct {
+		src      any
+		expected CloudProvider
+	}{
+		{"aws", AWSProvider},
+		{[]byte("Azure"), AzureProvider},
+		{"GCP", GCPProvider},
+		{"other", UnknownProvider},
+		{nil, UnknownProvider},
+	}
+
+	for _, tt := range tests {
+		var provider CloudProvider
+		if err := provider.Scan(tt.src); err != nil {
+			t.Fatalf("Scan(%v) failed: %v", tt.src, err)
+		}
+		if provider != tt.expected {
+			t.Errorf("Scan(%v) = %v; want %v", tt.src, provider, tt.expected)
+		}
+	}
+
+	var provider CloudProvider
+	if err := provider.Scan(42); err == nil {
+		t.Errorf("expected an error scanning a non string provider")
+	}
+}
@@ -60,8 +60,34 @@ type Cluster struct {
 	// Current month so far cost
 	CurrentMonthSoFarCost float64 `db:"current_month_so_far_cost" json:"currentMonthSoFarCost"`
 
-	// Cluster's instance (nodes) lists
-	Instances []Instance
+	// Percentage of time the cluster was Running since its status is tracked, until its last scan or status change. Computed from its status history
+	UptimePercent float64 `db:"-" json:"uptimePercent"`
+
+	// Total cost (US Dollars) of the cluster's instances by their status. Computed from its instances
+	CostByStatus map[InstanceStatus]float64 `db:"-" json:"costByStatus,omitempty"`
+
+	// Number of cluster's instances on the inventory, and how many of them are Running or Stopped. Computed from its instances
+	TotalInstances   int `db:"-" json:"totalInstances"`
+	RunningInstances int `db:"-" json:"runningInstances"`
+	StoppedInstances int `db:"-" json:"stoppedInstances"`
+
+	// Idle is set when the cluster has instances and every one of them is Stopped. Computed from its instances
+	Idle bool `db:"-" json:"idle"`
+
+	// Descriptor of the cluster's account. Only embedded on demand
+	Account *ClusterAccount `db:"-" json:"account,omitempty"`
+
+	// Cluster's instance (nodes) lists. Omitted if not loaded
+	Instances []Instance `json:"Instances,omitempty"`
+}
+
+// ClusterAccount is the short descriptor of the account of a cluster
+type ClusterAccount struct {
+	// Account's name
+	Name string `json:"name"`
+
+	// Infrastructure provider identifier
+	Provider CloudProvider `json:"provider"`
 }
 
 // NewCluster creates a new cluster instance
@@ -110,6 +136,66 @@ func (c Cluster) IsClusterRunning() bool {
 	return false
 }
 
+// ModifiedSince checks if the cluster was created or scanned after the given
+// time. Clusters without any timestamp are always considered modified
+func (c Cluster) ModifiedSince(since time.Time) bool {
+	return isModifiedSince(since, c.CreationTimestamp, c.LastScanTimestamp)
+}
+
+// CreatedBetween checks if the cluster was created in the [after, before) range. nil limits are ignored
+func (c Cluster) CreatedBetween(after, before *time.Time) bool {
+	return isCreatedBetween(c.CreationTimestamp, after, before)
+}
+
+// MatchesSearch checks if the cluster's ID, name, infraID, region or owner contains the query (case insensitive)
+func (c Cluster) MatchesSearch(query string) bool {
+	return matchesSearch(query, c.ID, c.Name, c.InfraID, c.Region, c.Owner)
+}
+
+// InstanceStatusBreakdown returns the number of cluster's instances on each status
+func (c Cluster) InstanceStatusBreakdown() map[InstanceStatus]int {
+	breakdown := make(map[InstanceStatus]int)
+	for _, instance := range c.Instances {
+		breakdown[instance.Status]++
+	}
+	return breakdown
+}
+
+// UpdateInstanceCounts counts the given cluster's instances by their status,
+// and flags the cluster as Idle if every one of them is Stopped. The
+// instances are received apart, so the Instances list doesn't need to be
+// attached to the cluster.
+func (c *Cluster) UpdateInstanceCounts(instances []Instance) {
+	c.TotalInstances = len(instances)
+	c.RunningInstances = 0
+	c.StoppedInstances = 0
+	for _, instance := range instances {
+		switch ProviderState(instance.Status).Status() {
+		case Running:
+			c.RunningInstances++
+		case Stopped:
+			c.StoppedInstances++
+		}
+	}
+	c.Idle = c.TotalInstances > 0 && c.StoppedInstances == c.TotalInstances
+}
+
+// UpdateUptimePercent calculates the percentage of time the cluster was
+// Running from its first tracked status change until now. The history must be
+// sorted by timestamp. Clusters without history are considered to be on their
+// current status since ever.
+func (c *Cluster) UpdateUptimePercent(history []StatusChange, now time.Time) {
+	if len(history) == 0 || !history[0].Timestamp.Before(now) {
+		c.UptimePercent = 0.0
+		if c.Status == Running {
+			c.UptimePercent = 100.0
+		}
+		return
+	}
+
+	c.UptimePercent = float64(runningDuration(history, now)) / float64(now.Sub(history[0].Timestamp)) * 100
+}
+
 // UpdateClusterInfo as a update function wrapper
 func (c *Cluster) Update() error {
 	var err error
@@ -368,3 +368,98 @@ func TestPrintCluster(t *testing.T) {
 	}
 	c.PrintCluster()
 }
+
+// TestClusterModifiedSince verifies that creation and scan timestamps are both considered
+func TestClusterModifiedSince(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+
+	cluster := Cluster{
+		CreationTimestamp: since.Add(1 * time.Hour),
+		LastScanTimestamp: since.Add(-1 * time.Hour),
+	}
+	assert.True(t, cluster.ModifiedSince(since))
+
+	cluster.CreationTimestamp = since.Add(-2 * time.Hour)
+	assert.False(t, cluster.ModifiedSince(since))
+
+	// Clusters without timestamps are always returned
+	assert.True(t, Cluster{}.ModifiedSince(since))
+}
+
+// TestInstanceStatusBreakdown verifies that instances are counted by their status
+func TestInstanceStatusBreakdown(t *testing.T) {
+	cluster := Cluster{
+		Instances: []Instance{
+			{ID: "i1", Status: Running},
+			{ID: "i2", Status: Running},
+			{ID: "i3", Status: Stopped},
+		},
+	}
+
+	breakdown := cluster.InstanceStatusBreakdown()
+	assert.Equal(t, map[InstanceStatus]int{Running: 2, Stopped: 1}, breakdown)
+
+	// Empty clusters return an empty (non-nil) map
+	assert.Empty(t, Cluster{}.InstanceStatusBreakdown())
+	assert.NotNil(t, Cluster{}.InstanceStatusBreakdown())
+}
+
+// TestUpdateInstanceCounts verifies the instances counters and the Idle flag
+func TestUpdateInstanceCounts(t *testing.T) {
+	var cluster Cluster
+	cluster.UpdateInstanceCounts([]Instance{
+		{ID: "i1", Status: Running},
+		{ID: "i2", Status: Stopped},
+		{ID: "i3", Status: Terminated},
+	})
+	assert.Equal(t, 3, cluster.TotalInstances)
+	assert.Equal(t, 1, cluster.RunningInstances)
+	assert.Equal(t, 1, cluster.StoppedInstances)
+	assert.False(t, cluster.Idle)
+
+	// Every instance Stopped
+	cluster.UpdateInstanceCounts([]Instance{{ID: "i1", Status: Stopped}, {ID: "i2", Status: Stopped}})
+	assert.Equal(t, 0, cluster.RunningInstances)
+	assert.Equal(t, 2, cluster.StoppedInstances)
+	assert.True(t, cluster.Idle)
+
+	// Clusters without instances are not Idle
+	cluster.UpdateInstanceCounts(nil)
+	assert.Equal(t, 0, cluster.TotalInstances)
+	assert.False(t, cluster.Idle)
+}
+
+// TestUpdateUptimePercent verifies the uptime calculation based on the status history
+func TestUpdateUptimePercent(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(100 * time.Hour)
+
+	var cluster Cluster
+	history := []StatusChange{
+		{Status: Running, Timestamp: start},
+		{Status: Stopped, Timestamp: start.Add(25 * time.Hour)},
+		{Status: Running, Timestamp: start.Add(50 * time.Hour)},
+		{Status: Terminated, Timestamp: start.Add(75 * time.Hour)},
+	}
+	cluster.UpdateUptimePercent(history, now)
+	assert.InDelta(t, 50.0, cluster.UptimePercent, 0.001)
+
+	// Clusters without history depend on their current status
+	cluster = Cluster{Status: Running}
+	cluster.UpdateUptimePercent(nil, now)
+	assert.Equal(t, 100.0, cluster.UptimePercent)
+
+	cluster = Cluster{Status: Stopped}
+	cluster.UpdateUptimePercent(nil, now)
+	assert.Equal(t, 0.0, cluster.UptimePercent)
+}
+
+// TestClusterMatchesSearch verifies the cluster fields used by the search
+func TestClusterMatchesSearch(t *testing.T) {
+	cluster := Cluster{ID: "cluster-a-infra-account", Name: "cluster-a", Region: "eu-west-1", Owner: "jdoe"}
+
+	assert.True(t, cluster.MatchesSearch("Cluster-A"))
+	assert.True(t, cluster.MatchesSearch("eu-west"))
+	assert.True(t, cluster.MatchesSearch("jdoe"))
+	assert.False(t, cluster.MatchesSearch("us-east"))
+}
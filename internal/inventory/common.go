@@ -2,6 +2,7 @@ package inventory
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -24,3 +25,47 @@ func calculateAge(initTimestamp time.Time, finalTimestamp time.Time) int {
 	}
 	return age
 }
+
+// isModifiedSince checks if any of the given timestamps is newer than since.
+// Zero timestamps are ignored, and if none of them is usable the resource is
+// considered modified so incremental clients never miss it
+func isModifiedSince(since time.Time, timestamps ...time.Time) bool {
+	usable := false
+	for _, ts := range timestamps {
+		if ts.IsZero() {
+			continue
+		}
+		usable = true
+		if ts.After(since) {
+			return true
+		}
+	}
+	return !usable
+}
+
+// matchesSearch checks if any of the given fields contains the query, ignoring
+// the case. Empty queries never match
+func matchesSearch(query string, fields ...string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return false
+	}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCreatedBetween checks if created is in the [after, before) range. nil
+// limits are ignored, and zero timestamps never match a limit
+func isCreatedBetween(created time.Time, after, before *time.Time) bool {
+	if (after != nil || before != nil) && created.IsZero() {
+		return false
+	}
+	if after != nil && created.Before(*after) {
+		return false
+	}
+	return before == nil || created.Before(*before)
+}
@@ -76,3 +76,80 @@ func TestCalculateAge_WithinOneDay(t *testing.T) {
 		t.Errorf("expected age 1 for <24h difference, got %d", age)
 	}
 }
+
+// TestIsModifiedSince verifies the timestamp comparison used for incremental syncs.
+func TestIsModifiedSince(t *testing.T) {
+	since := time.Now().Add(-1 * time.Hour)
+
+	tests := []struct {
+		name       string
+		timestamps []time.Time
+		expected   bool
+	}{
+		{"Newer timestamp", []time.Time{time.Now()}, true},
+		{"Older timestamp", []time.Time{since.Add(-1 * time.Hour)}, false},
+		{"One newer among several", []time.Time{since.Add(-1 * time.Hour), time.Now()}, true},
+		{"Only zero timestamps", []time.Time{{}, {}}, true},
+		{"Zero and older timestamps", []time.Time{{}, since.Add(-1 * time.Minute)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isModifiedSince(since, tt.timestamps...); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestMatchesSearch verifies the case insensitive matching used by the search endpoints.
+func TestMatchesSearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		fields   []string
+		expected bool
+	}{
+		{"Exact match", "cluster-a", []string{"cluster-a"}, true},
+		{"Partial match ignoring case", "CLUSTER", []string{"other", "my-cluster-a"}, true},
+		{"No match", "prod", []string{"cluster-a", "eu-west-1"}, false},
+		{"Empty query", " ", []string{"cluster-a"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := matchesSearch(tt.query, tt.fields...); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestIsCreatedBetween verifies the creation range used by the created_after/created_before filters.
+func TestIsCreatedBetween(t *testing.T) {
+	after := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	before := after.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name     string
+		created  time.Time
+		after    *time.Time
+		before   *time.Time
+		expected bool
+	}{
+		{"No limits", time.Time{}, nil, nil, true},
+		{"Inside range", after.Add(12 * time.Hour), &after, &before, true},
+		{"On lower limit", after, &after, &before, true},
+		{"On upper limit", before, &after, &before, false},
+		{"Before lower limit", after.Add(-1 * time.Second), &after, nil, false},
+		{"Zero timestamp with limit", time.Time{}, nil, &before, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isCreatedBetween(tt.created, tt.after, tt.before); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package inventory
+
+import (
+	"fmt"
+	"slices"
+
+	ini "gopkg.in/ini.v1"
+)
+
+// CostDimensions maps canonical cost dimensions (like "team") to the tag key
+// holding them on every cloud provider, as AWS cost allocation tags and GCP
+// labels use different keys for the same concept
+type CostDimensions map[string]map[CloudProvider]string
+
+// ReadCostDimensions reads the cost dimensions mapping file. Every section is a
+// dimension, and every key of the section is a provider (aws/gcp/azure)
+// pointing to its tag key:
+//
+//	[team]
+//	aws = CostCenterTeam
+//	gcp = team
+func ReadCostDimensions(file string) (CostDimensions, error) {
+	cfg, err := ini.Load(file)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.DeleteSection(ini.DefaultSection)
+	dimensions := make(CostDimensions)
+	for _, section := range cfg.Sections() {
+		mapping := make(map[CloudProvider]string)
+		for _, key := range section.Keys() {
+			provider := GetCloudProvider(key.Name())
+			if provider == UnknownProvider {
+				return nil, fmt.Errorf("unknown provider '%s' on cost dimension '%s'", key.Name(), section.Name())
+			}
+			mapping[provider] = key.String()
+		}
+		dimensions[section.Name()] = mapping
+	}
+
+	return dimensions, nil
+}
+
+// TagKey returns the tag key of the dimension on the provider, and false if
+// the dimension is not mapped for it
+func (d CostDimensions) TagKey(dimension string, provider CloudProvider) (string, bool) {
+	key, ok := d[dimension][provider]
+	return key, ok
+}
+
+// Names returns the configured dimensions sorted alphabetically
+func (d CostDimensions) Names() []string {
+	names := make([]string, 0, len(d))
+	for name := range d {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
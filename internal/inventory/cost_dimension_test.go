@@ -0,0 +1,65 @@
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCostDimensionsFile writes a cost dimensions file on a temp dir for testing
+func writeCostDimensionsFile(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "cost_dimensions")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// TestReadCostDimensions verifies the dimensions are mapped per provider
+func TestReadCostDimensions(t *testing.T) {
+	file := writeCostDimensionsFile(t, `
+[team]
+aws = CostCenterTeam
+gcp = team
+
+[environment]
+aws = Environment
+`)
+
+	dimensions, err := ReadCostDimensions(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"environment", "team"}, dimensions.Names())
+
+	key, ok := dimensions.TagKey("team", AWSProvider)
+	assert.True(t, ok)
+	assert.Equal(t, "CostCenterTeam", key)
+
+	key, ok = dimensions.TagKey("team", GCPProvider)
+	assert.True(t, ok)
+	assert.Equal(t, "team", key)
+
+	_, ok = dimensions.TagKey("environment", GCPProvider)
+	assert.False(t, ok)
+
+	_, ok = dimensions.TagKey("missing", AWSProvider)
+	assert.False(t, ok)
+}
+
+// TestReadCostDimensions_UnknownProvider verifies unknown providers are rejected
+func TestReadCostDimensions_UnknownProvider(t *testing.T) {
+	file := writeCostDimensionsFile(t, `
+[team]
+oracle = team
+`)
+
+	_, err := ReadCostDimensions(file)
+	assert.Error(t, err)
+}
+
+// TestReadCostDimensions_MissingFile verifies an error is returned if the file doesn't exist
+func TestReadCostDimensions_MissingFile(t *testing.T) {
+	_, err := ReadCostDimensions(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -2,6 +2,9 @@ package inventory
 
 import "time"
 
+// CostCurrency is the currency (ISO 4217) of every cost stored on the inventory
+const CostCurrency = "USD"
+
 // Expense defines the expenses applied to an instance
 type Expense struct {
 	// InstanceID references the instance of the expense
@@ -14,6 +17,13 @@ type Expense struct {
 	Date time.Time `db:"date" json:"date"`
 }
 
+// InBillingPeriodAsOf checks if the expense was accounted in the monthly
+// billing period of the asOf date, and not after it
+func (e Expense) InBillingPeriodAsOf(asOf time.Time) bool {
+	periodStart := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, asOf.Location())
+	return !e.Date.Before(periodStart) && !e.Date.After(asOf)
+}
+
 // NewExpense create a expense for an instance
 func NewExpense(instanceID string, amount float64, date time.Time) *Expense {
 	// Checking if cost is below zero, which is not possible
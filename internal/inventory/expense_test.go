@@ -26,3 +26,23 @@ func TestNewExpense(t *testing.T) {
 	wrongExpense := NewExpense(instanceID, -6.0, date)
 	assert.Nil(t, wrongExpense)
 }
+
+func TestInBillingPeriodAsOf(t *testing.T) {
+	asOf := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		date     time.Time
+		expected bool
+	}{
+		{time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2023, time.March, 10, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, test := range tests {
+		expense := Expense{InstanceID: "testInstance", Amount: 1.0, Date: test.date}
+		assert.Equal(t, test.expected, expense.InBillingPeriodAsOf(asOf), test.date.String())
+	}
+}
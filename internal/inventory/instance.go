@@ -1,8 +1,10 @@
 package inventory
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -13,6 +15,43 @@ var (
 	ERR_INSTANCE_AGE_LESS_ZERO        = errors.New("Cannot recalculate costs if instance's Age is 0")
 )
 
+// DisplayNameSource defines a source for resolving the display name of an instance
+type DisplayNameSource string
+
+const (
+	// DisplayNameFromTag uses the value of the "Name" tag
+	DisplayNameFromTag DisplayNameSource = "tag"
+	// DisplayNameFromName uses the name reported by the provider (Name field)
+	DisplayNameFromName DisplayNameSource = "name"
+	// DisplayNameFromID uses the instance ID
+	DisplayNameFromID DisplayNameSource = "id"
+)
+
+// displayNameOrder is the precedence used by Instance.DisplayName. The first non empty source is used
+var displayNameOrder = []DisplayNameSource{DisplayNameFromTag, DisplayNameFromName, DisplayNameFromID}
+
+// SetDisplayNameOrder configures the precedence of the sources used for
+// resolving the instances display name. The instance ID is always used as the
+// last fallback, even if it's not included.
+func SetDisplayNameOrder(order []string) error {
+	if len(order) == 0 {
+		return nil
+	}
+
+	newOrder := make([]DisplayNameSource, 0, len(order))
+	for _, source := range order {
+		switch s := DisplayNameSource(strings.ToLower(strings.TrimSpace(source))); s {
+		case DisplayNameFromTag, DisplayNameFromName, DisplayNameFromID:
+			newOrder = append(newOrder, s)
+		default:
+			return fmt.Errorf("unknown display name source '%s'. Expected: %s, %s or %s", source, DisplayNameFromTag, DisplayNameFromName, DisplayNameFromID)
+		}
+	}
+
+	displayNameOrder = newOrder
+	return nil
+}
+
 // Instance model a cloud provider instance
 type Instance struct {
 	// Uniq Identifier of the instance
@@ -30,12 +69,18 @@ type Instance struct {
 	// Availability Zone in which the instance is running on
 	AvailabilityZone string `db:"availability_zone" json:"availabilityZone"`
 
-	// Instance Status
+	// Instance Status (canonical value)
 	Status InstanceStatus `db:"status" json:"status"`
 
+	// Raw instance state as reported by the cloud provider
+	ProviderState ProviderState `db:"provider_state" json:"providerState"`
+
 	// ClusterID
 	ClusterID string `db:"cluster_id" json:"clusterID"`
 
+	// IAM Role (AWS Instance Profile) or Service Account attached to the instance
+	IAMRole string `db:"iam_role" json:"iamRole"`
+
 	// Last scan timestamp of the instance
 	LastScanTimestamp time.Time `db:"last_scan_timestamp" json:"lastScanTimestamp"`
 
@@ -51,11 +96,23 @@ type Instance struct {
 	// Total cost (US Dollars) accumulated since ClusterIQ is scanning
 	TotalCost float64 `db:"total_cost" json:"totalCost"`
 
-	// Instance Tags as key-value array
-	Tags []Tag `json:"tags"`
+	// Total cost divided by the hours the instance was Running, until its last scan or status change. Computed from its status history
+	CostPerHour float64 `db:"-" json:"costPerHour"`
+
+	// Tags of the instance's cluster, indexed by key. Only embedded on demand
+	ClusterLabels map[string]string `db:"-" json:"clusterLabels,omitempty"`
+
+	// Protected instances are skipped by the power actions (instant and scheduled). Kept across scans
+	Protected bool `db:"protected" json:"protected"`
+
+	// Average CPU utilization (percentage) reported by the cloud provider. nil if unknown
+	CPUUtilization *float64 `db:"cpu_utilization" json:"cpuUtilization"`
+
+	// Instance Tags as key-value array. Omitted if not loaded
+	Tags []Tag `json:"tags,omitempty"`
 
-	// Expenses list associated to the instance
-	Expenses []Expense `json:"expenses"`
+	// Expenses list associated to the instance. Omitted if not loaded
+	Expenses []Expense `json:"expenses,omitempty"`
 }
 
 // NewInstance returns a new Instance object
@@ -125,6 +182,108 @@ func (i *Instance) UpdateCosts() error {
 	return nil
 }
 
+// ModifiedSince checks if the instance was created or scanned after the given
+// time. Instances without any timestamp are always considered modified
+func (i Instance) ModifiedSince(since time.Time) bool {
+	return isModifiedSince(since, i.CreationTimestamp, i.LastScanTimestamp)
+}
+
+// CreatedBetween checks if the instance was created in the [after, before) range. nil limits are ignored
+func (i Instance) CreatedBetween(after, before *time.Time) bool {
+	return isCreatedBetween(i.CreationTimestamp, after, before)
+}
+
+// IsIdle checks if the instance is running with a CPU utilization below IdleCPUUtilizationThreshold.
+// Instances with an unknown CPU utilization are never idle
+func (i Instance) IsIdle() bool {
+	return i.Status == Running && i.CPUUtilization != nil && *i.CPUUtilization < IdleCPUUtilizationThreshold
+}
+
+// UpdateCostPerHour calculates the instance cost per running hour. The running
+// time is obtained from the status history, which must be sorted by timestamp.
+// Instances without history are considered to be on their current status since
+// their creation.
+func (i *Instance) UpdateCostPerHour(history []StatusChange, now time.Time) {
+	var running time.Duration
+	if len(history) > 0 {
+		running = runningDuration(history, now)
+	} else if i.Status == Running && !i.CreationTimestamp.IsZero() {
+		running = now.Sub(i.CreationTimestamp)
+	}
+
+	i.CostPerHour = 0.0
+	if hours := running.Hours(); hours > 0 {
+		i.CostPerHour = i.TotalCost / hours
+	}
+}
+
+// DisplayName returns the human friendly name of the instance resolving the
+// configured sources in order (by default: "Name" tag, provider name and ID).
+// The ID is used if every source is empty
+func (i Instance) DisplayName() string {
+	for _, source := range displayNameOrder {
+		var name string
+		switch source {
+		case DisplayNameFromTag:
+			name = GetInstanceNameFromTags(i.Tags)
+		case DisplayNameFromName:
+			name = i.Name
+		case DisplayNameFromID:
+			name = i.ID
+		}
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return i.ID
+}
+
+// MarshalJSON includes the resolved displayName on the instance JSON representation
+func (i Instance) MarshalJSON() ([]byte, error) {
+	type instanceAlias Instance
+	return json.Marshal(struct {
+		instanceAlias
+		DisplayName string `json:"displayName"`
+	}{
+		instanceAlias: instanceAlias(i),
+		DisplayName:   i.DisplayName(),
+	})
+}
+
+// IsUnnamed checks if the instance has no name, neither on its Name field nor on its "Name" tag
+func (i Instance) IsUnnamed() bool {
+	return strings.TrimSpace(i.Name) == "" && strings.TrimSpace(GetInstanceNameFromTags(i.Tags)) == ""
+}
+
+// Region returns the region of the instance, derived from its availability
+// zone: "us-east-1a" (AWS) and "us-central1-a" (GCP) are zones of "us-east-1"
+// and "us-central1". If the zone has no zone suffix, it's returned as is.
+func (i Instance) Region() string {
+	zone := i.AvailabilityZone
+	n := len(zone)
+	if n < 2 || zone[n-1] < 'a' || zone[n-1] > 'z' {
+		return zone
+	}
+
+	switch previous := zone[n-2]; {
+	case previous == '-':
+		return zone[:n-2]
+	case previous >= '0' && previous <= '9':
+		return zone[:n-1]
+	}
+	return zone
+}
+
+// MatchesSearch checks if the instance's ID, display name, name, type, availability zone,
+// IAM role or any tag value contains the query (case insensitive)
+func (i Instance) MatchesSearch(query string) bool {
+	fields := []string{i.ID, i.DisplayName(), i.Name, i.InstanceType, i.AvailabilityZone, i.IAMRole}
+	for _, tag := range i.Tags {
+		fields = append(fields, tag.Value)
+	}
+	return matchesSearch(query, fields...)
+}
+
 // AddTag adds a tag to an instance
 func (i *Instance) AddTag(tag Tag) {
 	i.Tags = append(i.Tags, tag)
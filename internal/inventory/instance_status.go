@@ -1,6 +1,9 @@
 package inventory
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 // InstanceStatus defines the status of the instance
 type InstanceStatus string
@@ -12,8 +15,36 @@ const (
 	Stopped InstanceStatus = "Stopped"
 	// Terminated Instance status
 	Terminated InstanceStatus = "Terminated"
+	// Unknown Instance status, used when the provider state can't be normalized
+	Unknown InstanceStatus = "Unknown"
 )
 
+// ProviderState is the raw instance state as reported by the cloud provider
+// (e.g. "running", "RUNNING", "active", "deallocated")
+type ProviderState string
+
+// providerStates maps the known (lowercase) provider states to their canonical InstanceStatus
+var providerStates = map[string]InstanceStatus{
+	// Running states
+	"running":      Running,
+	"active":       Running,
+	"pending":      Running,
+	"provisioning": Running,
+	"staging":      Running,
+	// Stopped states
+	"stop":        Stopped,
+	"stopped":     Stopped,
+	"stopping":    Stopped,
+	"suspended":   Stopped,
+	"suspending":  Stopped,
+	"deallocated": Stopped,
+	// Terminated states
+	"terminated":    Terminated,
+	"shutting-down": Terminated,
+	"deleted":       Terminated,
+	"deleting":      Terminated,
+}
+
 // AsInstanceStatus converts the incoming argument into a InstanceStatus type
 func AsInstanceStatus(status string) InstanceStatus {
 	switch strings.ToLower(status) {
@@ -29,3 +60,42 @@ func AsInstanceStatus(status string) InstanceStatus {
 		return Running
 	}
 }
+
+// Status normalizes the provider state into the canonical InstanceStatus
+// set (Running/Stopped/Terminated/Unknown), ignoring case and surrounding spaces
+func (s ProviderState) Status() InstanceStatus {
+	key := strings.ToLower(strings.TrimSpace(string(s)))
+	if status, ok := providerStates[key]; ok {
+		return status
+	}
+	return Unknown
+}
+
+// StatusChange represents a status transition of an inventory resource (cluster or instance)
+type StatusChange struct {
+	// ResourceID references the cluster or instance which changed its status
+	ResourceID string `db:"resource_id" json:"resourceID"`
+
+	// New status of the resource
+	Status InstanceStatus `db:"status" json:"status"`
+
+	// Timestamp when the status changed
+	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+}
+
+// runningDuration returns how long the resource was Running according to its
+// status history, which must be sorted by timestamp, until now
+func runningDuration(history []StatusChange, now time.Time) time.Duration {
+	var running time.Duration
+	for i, change := range history {
+		if change.Status != Running {
+			continue
+		}
+		end := now
+		if i+1 < len(history) {
+			end = history[i+1].Timestamp
+		}
+		running += end.Sub(change.Timestamp)
+	}
+	return running
+}
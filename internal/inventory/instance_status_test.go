@@ -1,6 +1,9 @@
 package inventory
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestAsInstanceStatus(t *testing.T) {
 	tests := []struct {
@@ -41,3 +44,46 @@ func TestAsInstanceStatus(t *testing.T) {
 	}
 
 }
+
+func TestRunningDuration(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Hour)
+
+	history := []StatusChange{
+		{Status: Running, Timestamp: start},
+		{Status: Stopped, Timestamp: start.Add(2 * time.Hour)},
+		{Status: Running, Timestamp: start.Add(7 * time.Hour)},
+	}
+
+	if result := runningDuration(history, now); result != 5*time.Hour {
+		t.Errorf("expected %v, got %v", 5*time.Hour, result)
+	}
+
+	if result := runningDuration(nil, now); result != 0 {
+		t.Errorf("expected 0, got %v", result)
+	}
+}
+
+func TestProviderStateStatus(t *testing.T) {
+	tests := []struct {
+		input  ProviderState
+		result InstanceStatus
+	}{
+		{input: "running", result: Running},
+		{input: "RUNNING", result: Running},
+		{input: " active ", result: Running},
+		{input: "stopping", result: Stopped},
+		{input: "deallocated", result: Stopped},
+		{input: "shutting-down", result: Terminated},
+		{input: "Terminated", result: Terminated},
+		{input: "RANDOM", result: Unknown},
+		{input: "", result: Unknown},
+	}
+
+	for _, test := range tests {
+		result := test.input.Status()
+		if test.result != result {
+			t.Errorf("Provider state normalization failed for '%s'. Have: %s ; Expected: %v", test.input, result, test.result)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package inventory
 
 import (
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
@@ -178,3 +179,156 @@ func TestPrintInstance(t *testing.T) {
 	i := Instance{ID: "i-456", Name: "node1"}
 	i.PrintInstance()
 }
+
+// TestInstanceModifiedSince verifies that creation and scan timestamps are both considered
+func TestInstanceModifiedSince(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+
+	instance := Instance{
+		CreationTimestamp: since.Add(-48 * time.Hour),
+		LastScanTimestamp: since.Add(1 * time.Hour),
+	}
+	assert.True(t, instance.ModifiedSince(since))
+
+	instance.LastScanTimestamp = since.Add(-1 * time.Hour)
+	assert.False(t, instance.ModifiedSince(since))
+
+	// Instances without timestamps are always returned
+	assert.True(t, Instance{}.ModifiedSince(since))
+}
+
+// TestIsIdle verifies that only running instances under the utilization threshold are idle
+func TestIsIdle(t *testing.T) {
+	utilization := func(u float64) *float64 { return &u }
+	tests := []struct {
+		name        string
+		status      InstanceStatus
+		utilization *float64
+		expected    bool
+	}{
+		{"Running and idle", Running, utilization(1.5), true},
+		{"Running and busy", Running, utilization(60.0), false},
+		{"Running on threshold", Running, utilization(IdleCPUUtilizationThreshold), false},
+		{"Running and unknown", Running, nil, false},
+		{"Stopped", Stopped, utilization(0.0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := Instance{Status: tt.status, CPUUtilization: tt.utilization}
+			assert.Equal(t, tt.expected, instance.IsIdle())
+		})
+	}
+}
+
+// TestIsUnnamed verifies that instances are unnamed only when both the Name field and tag are empty
+func TestIsUnnamed(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance Instance
+		expected bool
+	}{
+		{"Named", Instance{Name: "worker-1"}, false},
+		{"Named by tag", Instance{Tags: []Tag{{Key: "Name", Value: "worker-1"}}}, false},
+		{"Empty name and no tags", Instance{}, true},
+		{"Blank name and tag", Instance{Name: " ", Tags: []Tag{{Key: "Name", Value: ""}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.instance.IsUnnamed())
+		})
+	}
+}
+
+// TestInstanceMatchesSearch verifies the instance fields used by the search
+func TestInstanceMatchesSearch(t *testing.T) {
+	instance := Instance{
+		ID:               "i-0123456789",
+		Name:             "worker-1",
+		InstanceType:     "m5.xlarge",
+		AvailabilityZone: "eu-west-1a",
+		Tags:             []Tag{{Key: "team", Value: "Observability"}},
+	}
+
+	assert.True(t, instance.MatchesSearch("i-0123"))
+	assert.True(t, instance.MatchesSearch("M5"))
+	assert.True(t, instance.MatchesSearch("observ"))
+	assert.False(t, instance.MatchesSearch("master"))
+}
+
+// TestInstanceRegion verifies the region is derived from the availability zone
+func TestInstanceRegion(t *testing.T) {
+	tests := []struct {
+		zone     string
+		expected string
+	}{
+		{"us-east-1a", "us-east-1"},
+		{"eu-west-3c", "eu-west-3"},
+		{"us-central1-a", "us-central1"},
+		{"eu-west-1", "eu-west-1"},
+		{"westeurope", "westeurope"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zone, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Instance{AvailabilityZone: tt.zone}.Region())
+		})
+	}
+}
+
+// TestUpdateCostPerHour verifies the cost per running hour calculation
+func TestUpdateCostPerHour(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(20 * time.Hour)
+
+	// Running 10h out of 20h
+	instance := Instance{TotalCost: 50.0, Status: Stopped}
+	instance.UpdateCostPerHour([]StatusChange{
+		{Status: Running, Timestamp: start},
+		{Status: Stopped, Timestamp: start.Add(10 * time.Hour)},
+	}, now)
+	assert.InDelta(t, 5.0, instance.CostPerHour, 0.001)
+
+	// Without history, running instances are considered running since their creation
+	instance = Instance{TotalCost: 40.0, Status: Running, CreationTimestamp: start}
+	instance.UpdateCostPerHour(nil, now)
+	assert.InDelta(t, 2.0, instance.CostPerHour, 0.001)
+
+	// Never running
+	instance = Instance{TotalCost: 40.0, Status: Stopped, CreationTimestamp: start}
+	instance.UpdateCostPerHour(nil, now)
+	assert.Equal(t, 0.0, instance.CostPerHour)
+}
+
+// TestDisplayName verifies the display name resolution order
+func TestDisplayName(t *testing.T) {
+	defer func() {
+		displayNameOrder = []DisplayNameSource{DisplayNameFromTag, DisplayNameFromName, DisplayNameFromID}
+	}()
+
+	tagged := Instance{ID: "i-1", Name: "provider-name", Tags: []Tag{{Key: "Name", Value: "tag-name"}}}
+	named := Instance{ID: "i-2", Name: "provider-name"}
+	anonymous := Instance{ID: "i-3"}
+
+	// Default order
+	assert.Equal(t, "tag-name", tagged.DisplayName())
+	assert.Equal(t, "provider-name", named.DisplayName())
+	assert.Equal(t, "i-3", anonymous.DisplayName())
+
+	// Custom order
+	assert.NoError(t, SetDisplayNameOrder([]string{"name", "tag"}))
+	assert.Equal(t, "provider-name", tagged.DisplayName())
+	assert.Equal(t, "i-3", anonymous.DisplayName())
+
+	assert.Error(t, SetDisplayNameOrder([]string{"name", "label"}))
+}
+
+// TestInstanceMarshalJSON verifies the display name is included on the instance JSON
+func TestInstanceMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(Instance{ID: "i-1", Name: "worker-1"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"displayName":"worker-1"`)
+	assert.Contains(t, string(b), `"id":"i-1"`)
+}
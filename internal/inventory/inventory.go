@@ -1,7 +1,9 @@
 package inventory
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -34,10 +36,21 @@ func (s *Inventory) AddAccount(account *Account) error {
 	return nil
 }
 
+// SortedAccounts returns the inventory's accounts sorted by name, so every
+// walk of the accounts map follows the same order
+func (s Inventory) SortedAccounts() []*Account {
+	accounts := make([]*Account, 0, len(s.Accounts))
+	for _, account := range s.Accounts {
+		accounts = append(accounts, account)
+	}
+	slices.SortFunc(accounts, func(a, b *Account) int { return cmp.Compare(a.Name, b.Name) })
+	return accounts
+}
+
 // PrintInventory prints the entire Inventory content
 func (s Inventory) PrintInventory() {
 	fmt.Printf("Inventory created at: %s\nAccounts:\n", s.CreationTimestamp)
-	for _, account := range s.Accounts {
+	for _, account := range s.SortedAccounts() {
 		account.PrintAccount()
 	}
 }
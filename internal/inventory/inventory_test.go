@@ -88,3 +88,17 @@ func TestPrintInventory(t *testing.T) {
 
 	inv.PrintInventory()
 }
+
+// TestSortedAccounts verifies the inventory's accounts are walked by name
+func TestSortedAccounts(t *testing.T) {
+	inv := NewInventory()
+	for _, name := range []string{"staging", "dev", "prod"} {
+		assert.NoError(t, inv.AddAccount(&Account{Name: name, Clusters: make(map[string]*Cluster)}))
+	}
+
+	var names []string
+	for _, account := range inv.SortedAccounts() {
+		names = append(names, account.Name)
+	}
+	assert.Equal(t, []string{"dev", "prod", "staging"}, names)
+}
@@ -5,6 +5,11 @@ const (
 	ClusterTagKey string = "kubernetes.io/cluster/"
 )
 
+const (
+	// IdleCPUUtilizationThreshold is the CPU utilization (percentage) below which a running instance is considered idle
+	IdleCPUUtilizationThreshold = 5.0
+)
+
 const (
 	// Cluster actions
 	ClusterPowerOnAction  = "PowerOn"
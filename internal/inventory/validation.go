@@ -0,0 +1,133 @@
+package inventory
+
+import "fmt"
+
+// ValidateAccounts checks the invariants of a batch of accounts before it's
+// written into the inventory, so a malformed or partially generated batch
+// can be rejected as a whole instead of replacing the previous data.
+//
+// Returns the list of issues found. Empty if the batch is valid
+func ValidateAccounts(accounts []Account) []string {
+	var issues []string
+	names := make(map[string]struct{}, len(accounts))
+	for i, account := range accounts {
+		if account.Name == "" {
+			issues = append(issues, fmt.Sprintf("account #%d: empty name", i))
+			continue
+		}
+		if _, ok := names[account.Name]; ok {
+			issues = append(issues, fmt.Sprintf("account '%s': duplicated name", account.Name))
+		}
+		names[account.Name] = struct{}{}
+		if hasNegativeCost(account.TotalCost, account.Last15DaysCost, account.LastMonthCost, account.CurrentMonthSoFarCost) {
+			issues = append(issues, fmt.Sprintf("account '%s': negative cost", account.Name))
+		}
+	}
+	return issues
+}
+
+// ValidateClusters checks the invariants of a batch of clusters before it's
+// written into the inventory. Every cluster must have a name and an account,
+// its name must be unique on its account, and its instance count must match
+// its instances list when included.
+//
+// Returns the list of issues found. Empty if the batch is valid
+func ValidateClusters(clusters []Cluster) []string {
+	var issues []string
+	names := make(map[string]struct{}, len(clusters))
+	for i, cluster := range clusters {
+		if cluster.Name == "" {
+			issues = append(issues, fmt.Sprintf("cluster #%d: empty name", i))
+			continue
+		}
+		if cluster.AccountName == "" {
+			issues = append(issues, fmt.Sprintf("cluster '%s': empty account name", cluster.Name))
+		}
+		key := cluster.AccountName + "/" + cluster.Name
+		if _, ok := names[key]; ok {
+			issues = append(issues, fmt.Sprintf("cluster '%s': duplicated name on account '%s'", cluster.Name, cluster.AccountName))
+		}
+		names[key] = struct{}{}
+		if cluster.InstanceCount < 0 || (cluster.Instances != nil && cluster.InstanceCount != len(cluster.Instances)) {
+			issues = append(issues, fmt.Sprintf("cluster '%s': instance count (%d) doesn't match its instances (%d)", cluster.Name, cluster.InstanceCount, len(cluster.Instances)))
+		}
+		if hasNegativeCost(cluster.TotalCost, cluster.Last15DaysCost, cluster.LastMonthCost, cluster.CurrentMonthSoFarCost) {
+			issues = append(issues, fmt.Sprintf("cluster '%s': negative cost", cluster.Name))
+		}
+	}
+	return issues
+}
+
+// ValidateInstances checks the invariants of a batch of instances before it's
+// written into the inventory. Every instance must have a unique ID.
+//
+// Returns the list of issues found. Empty if the batch is valid
+func ValidateInstances(instances []Instance) []string {
+	var issues []string
+	ids := make(map[string]struct{}, len(instances))
+	for i, instance := range instances {
+		if instance.ID == "" {
+			issues = append(issues, fmt.Sprintf("instance #%d: empty ID", i))
+			continue
+		}
+		if _, ok := ids[instance.ID]; ok {
+			issues = append(issues, fmt.Sprintf("instance '%s': duplicated ID", instance.ID))
+		}
+		ids[instance.ID] = struct{}{}
+		if hasNegativeCost(instance.DailyCost, instance.TotalCost) {
+			issues = append(issues, fmt.Sprintf("instance '%s': negative cost", instance.ID))
+		}
+	}
+	return issues
+}
+
+// ValidateInventory checks a whole inventory, as posted by a scanner, without
+// writing it: every batch is checked with the same rules used when it's
+// written (see ValidateAccounts, ValidateClusters and ValidateInstances),
+// and then the references between them. Every cluster must belong to one of
+// the accounts, and every instance to one of the clusters.
+//
+// Returns the list of issues found. Empty if the inventory is valid
+func ValidateInventory(accounts []Account, clusters []Cluster, instances []Instance) []string {
+	issues := ValidateAccounts(accounts)
+	issues = append(issues, ValidateClusters(clusters)...)
+	issues = append(issues, ValidateInstances(instances)...)
+
+	accountNames := make(map[string]struct{}, len(accounts))
+	for _, account := range accounts {
+		accountNames[account.Name] = struct{}{}
+	}
+	clusterIDs := make(map[string]struct{}, len(clusters))
+	for _, cluster := range clusters {
+		clusterIDs[cluster.ID] = struct{}{}
+		if cluster.Name == "" || cluster.AccountName == "" {
+			continue
+		}
+		if _, ok := accountNames[cluster.AccountName]; !ok {
+			issues = append(issues, fmt.Sprintf("cluster '%s': unknown account '%s'", cluster.Name, cluster.AccountName))
+		}
+	}
+	for _, instance := range instances {
+		if instance.ID == "" {
+			continue
+		}
+		if instance.ClusterID == "" {
+			issues = append(issues, fmt.Sprintf("instance '%s': no cluster", instance.ID))
+			continue
+		}
+		if _, ok := clusterIDs[instance.ClusterID]; !ok {
+			issues = append(issues, fmt.Sprintf("instance '%s': unknown cluster '%s'", instance.ID, instance.ClusterID))
+		}
+	}
+	return issues
+}
+
+// hasNegativeCost checks if any of the costs is below zero
+func hasNegativeCost(costs ...float64) bool {
+	for _, cost := range costs {
+		if cost < 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateAccounts verifies the accounts batch invariants
+func TestValidateAccounts(t *testing.T) {
+	assert.Empty(t, ValidateAccounts([]Account{{Name: "acc1"}, {Name: "acc2", TotalCost: 10}}))
+
+	issues := ValidateAccounts([]Account{{Name: "acc1"}, {Name: ""}, {Name: "acc1"}, {Name: "acc2", LastMonthCost: -1}})
+	assert.Equal(t, []string{
+		"account #1: empty name",
+		"account 'acc1': duplicated name",
+		"account 'acc2': negative cost",
+	}, issues)
+}
+
+// TestValidateClusters verifies the clusters batch invariants
+func TestValidateClusters(t *testing.T) {
+	valid := []Cluster{
+		{Name: "c1", AccountName: "acc1", InstanceCount: 1, Instances: []Instance{{ID: "i1"}}},
+		// Same name on another account
+		{Name: "c1", AccountName: "acc2"},
+		// Instances not included
+		{Name: "c2", AccountName: "acc1", InstanceCount: 3},
+	}
+	assert.Empty(t, ValidateClusters(valid))
+
+	issues := ValidateClusters([]Cluster{
+		{Name: "", AccountName: "acc1"},
+		{Name: "c1"},
+		{Name: "c2", AccountName: "acc1"},
+		{Name: "c2", AccountName: "acc1"},
+		{Name: "c3", AccountName: "acc1", InstanceCount: 2, Instances: []Instance{{ID: "i1"}}},
+		{Name: "c4", AccountName: "acc1", TotalCost: -5},
+	})
+	assert.Equal(t, []string{
+		"cluster #0: empty name",
+		"cluster 'c1': empty account name",
+		"cluster 'c2': duplicated name on account 'acc1'",
+		"cluster 'c3': instance count (2) doesn't match its instances (1)",
+		"cluster 'c4': negative cost",
+	}, issues)
+}
+
+// TestValidateInstances verifies the instances batch invariants
+func TestValidateInstances(t *testing.T) {
+	assert.Empty(t, ValidateInstances([]Instance{{ID: "i1"}, {ID: "i2", TotalCost: 1}}))
+
+	issues := ValidateInstances([]Instance{{ID: "i1"}, {ID: ""}, {ID: "i1"}, {ID: "i2", DailyCost: -0.5}})
+	assert.Equal(t, []string{
+		"instance #1: empty ID",
+		"instance 'i1': duplicated ID",
+		"instance 'i2': negative cost",
+	}, issues)
+}
+
+// TestValidateInventory verifies the batches invariants and the references between them
+func TestValidateInventory(t *testing.T) {
+	accounts := []Account{{Name: "acc1"}}
+	clusters := []Cluster{{ID: "c1-acc1", Name: "c1", AccountName: "acc1"}}
+	instances := []Instance{{ID: "i1", ClusterID: "c1-acc1"}}
+	assert.Empty(t, ValidateInventory(accounts, clusters, instances))
+
+	issues := ValidateInventory(
+		[]Account{{Name: "acc1"}, {Name: ""}},
+		[]Cluster{
+			{ID: "c1-acc1", Name: "c1", AccountName: "acc1"},
+			{ID: "c1-acc1", Name: "c1", AccountName: "acc1"},
+			{ID: "c2-acc2", Name: "c2", AccountName: "acc2"},
+		},
+		[]Instance{
+			{ID: "i1", ClusterID: "c1-acc1", TotalCost: -1},
+			{ID: "i2"},
+			{ID: "i3", ClusterID: "c3-acc1"},
+		},
+	)
+	assert.Equal(t, []string{
+		"account #1: empty name",
+		"cluster 'c1': duplicated name on account 'acc1'",
+		"instance 'i1': negative cost",
+		"cluster 'c2': unknown account 'acc2'",
+		"instance 'i2': no cluster",
+		"instance 'i3': unknown cluster 'c3-acc1'",
+	}, issues)
+}
@@ -8,7 +8,17 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-// NewLogger returns a customized instance of zap.Logger for ClusterIQ components
+// levels are the supported values of CIQ_LOG_LEVEL
+var levels = map[string]zapcore.Level{
+	"debug": zap.DebugLevel,
+	"info":  zap.InfoLevel,
+	"warn":  zap.WarnLevel,
+	"error": zap.ErrorLevel,
+}
+
+// NewLogger returns a customized instance of zap.Logger for ClusterIQ
+// components. The level is read from CIQ_LOG_LEVEL (debug, info, warn or
+// error). It defaults to info if empty or not valid
 func NewLogger() *zap.Logger {
 	encoderCfg := zap.NewProductionEncoderConfig()
 	encoderCfg.TimeKey = "timestamp"
@@ -27,14 +37,21 @@ func NewLogger() *zap.Logger {
 		},
 	}
 
-	// Checking debug level config env var
-	logLevel := strings.ToLower(os.Getenv("CIQ_LOG_LEVEL"))
-	if logLevel == "debug" {
-		loggerConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	// Checking log level config env var
+	logLevel := strings.ToLower(strings.TrimSpace(os.Getenv("CIQ_LOG_LEVEL")))
+	level, valid := levels[logLevel]
+	if !valid {
+		level = zap.InfoLevel
+	}
+	loggerConfig.Level = zap.NewAtomicLevelAt(level)
+	if level == zap.DebugLevel {
 		loggerConfig.DisableStacktrace = false
 		loggerConfig.DisableCaller = false
 	}
 
 	logger := zap.Must(loggerConfig.Build())
+	if !valid && logLevel != "" {
+		logger.Warn("Invalid CIQ_LOG_LEVEL, using info", zap.String("log_level", logLevel))
+	}
 	return logger
 }
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireBearerToken aborts with 401 Unauthorized the requests that don't send
+// the expected token as "Authorization: Bearer <token>"
+func RequireBearerToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, received, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(received)), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", "Bearer")
+			abortWithError(c, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireBearerTokenExcept requires the token like RequireBearerToken on every
+// route except the ones matching the public patterns (e.g. the healthchecks).
+// Requests not matching any route require the token too
+func RequireBearerTokenExcept(token string, public []EndpointPattern) gin.HandlerFunc {
+	requireToken := RequireBearerToken(token)
+	return func(c *gin.Context) {
+		if route := c.FullPath(); route != "" && matchesAnyEndpoint(public, c.Request.Method, route) {
+			c.Next()
+			return
+		}
+		requireToken(c)
+	}
+}
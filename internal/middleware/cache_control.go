@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControlHeader is the standard header for the browsers and CDN caching directives
+const CacheControlHeader = "Cache-Control"
+
+// cacheControlWriter sets the Cache-Control header right before the response
+// headers are sent, once the status is known, so only the successful
+// responses are cached
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	maxAge  time.Duration
+	isStale func() bool
+	done    bool
+}
+
+// setHeader adds the Cache-Control header to the successful responses
+func (w *cacheControlWriter) setHeader() {
+	if w.done || w.Written() {
+		return
+	}
+	w.done = true
+
+	// 304 responses must repeat the directives of the 200 ones they revalidate
+	if w.Status() != http.StatusOK && w.Status() != http.StatusNotModified {
+		return
+	}
+	if w.isStale != nil && w.isStale() {
+		w.Header().Set(CacheControlHeader, "no-cache")
+		return
+	}
+	w.Header().Set(CacheControlHeader, fmt.Sprintf("max-age=%d", int(w.maxAge.Seconds())))
+}
+
+// WriteHeaderNow adds the Cache-Control header before sending the headers
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write adds the Cache-Control header before sending the response body
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString adds the Cache-Control header before sending the response body
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// CacheControl adds the Cache-Control header with the given max-age to the
+// successful responses. If isStale reports the data as stale, responses are
+// sent as no-cache instead, so the clients always revalidate them. A zero
+// maxAge disables the header.
+func CacheControl(maxAge time.Duration, isStale func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxAge <= 0 {
+			c.Next()
+			return
+		}
+
+		writer := &cacheControlWriter{ResponseWriter: c.Writer, maxAge: maxAge, isStale: isStale}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// AnyOrigin allows the requests from every origin
+	AnyOrigin = "*"
+
+	// corsAllowedMethods are the methods served by the API
+	corsAllowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	// corsAllowedHeaders are the request headers allowed when the preflight doesn't ask for specific ones
+	corsAllowedHeaders = "Accept, Authorization, Content-Type, If-None-Match"
+	// corsMaxAge is the time (seconds) the browsers can cache the preflight responses
+	corsMaxAge = "600"
+)
+
+// CORS handles the Cross-Origin Resource Sharing headers. If allowedOrigins
+// contains AnyOrigin (or is empty), every origin is allowed. Otherwise, the
+// requests from other origins are rejected with 403 Forbidden. Requests
+// without Origin header (non browser clients) are always served.
+// The preflight requests (OPTIONS with Access-Control-Request-Method) are
+// answered with 204 No Content for every route.
+func CORS(allowedOrigins []string) gin.HandlerFunc {
+	anyOrigin := len(allowedOrigins) == 0 || slices.Contains(allowedOrigins, AnyOrigin)
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		if anyOrigin {
+			c.Header("Access-Control-Allow-Origin", AnyOrigin)
+		} else {
+			// The allowed origin depends on the request, so the caches must key on it
+			c.Writer.Header().Add("Vary", "Origin")
+			if !slices.Contains(allowedOrigins, origin) {
+				abortWithError(c, http.StatusForbidden, "Origin not allowed: "+origin)
+				return
+			}
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			headers := c.GetHeader("Access-Control-Request-Headers")
+			if strings.TrimSpace(headers) == "" {
+				headers = corsAllowedHeaders
+			}
+			c.Header("Access-Control-Allow-Methods", corsAllowedMethods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			c.Header("Access-Control-Max-Age", corsMaxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EndpointPattern defines a route pattern for disabling API endpoints. Its
+// format is "[METHOD ]<route>", where route is matched against the registered
+// Gin route (e.g. "/api/v1/clusters/:cluster_id/power_on") following the
+// path.Match syntax. If the method is omitted, every method is matched
+type EndpointPattern struct {
+	Method string
+	Route  string
+}
+
+// ParseEndpointPatterns parses and validates a list of endpoint patterns
+func ParseEndpointPatterns(patterns []string) ([]EndpointPattern, error) {
+	parsed := make([]EndpointPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		var endpoint EndpointPattern
+		if method, route, found := strings.Cut(pattern, " "); found {
+			endpoint = EndpointPattern{Method: strings.ToUpper(method), Route: strings.TrimSpace(route)}
+		} else {
+			endpoint = EndpointPattern{Route: pattern}
+		}
+
+		if _, err := path.Match(endpoint.Route, "/"); err != nil {
+			return nil, fmt.Errorf("invalid endpoint pattern '%s': %w", pattern, err)
+		}
+		parsed = append(parsed, endpoint)
+	}
+	return parsed, nil
+}
+
+// Matches checks if the pattern matches the given method and route. GET
+// patterns match the HEAD requests too, as they serve the same resource
+func (e EndpointPattern) Matches(method string, route string) bool {
+	if method == http.MethodHead && e.Method == http.MethodGet {
+		method = http.MethodGet
+	}
+	if e.Method != "" && e.Method != method {
+		return false
+	}
+	matched, _ := path.Match(e.Route, route)
+	return matched
+}
+
+// IsEndpointDisabled checks if any of the patterns matches the given method and route
+func IsEndpointDisabled(patterns []EndpointPattern, method string, route string) bool {
+	return matchesAnyEndpoint(patterns, method, route)
+}
+
+// matchesAnyEndpoint checks if any of the patterns matches the given method and route
+func matchesAnyEndpoint(patterns []EndpointPattern, method string, route string) bool {
+	for _, pattern := range patterns {
+		if pattern.Matches(method, route) {
+			return true
+		}
+	}
+	return false
+}
+
+// DisableEndpoints aborts with 404 Not Found the requests to the routes
+// matching any of the patterns, as if they were not registered
+func DisableEndpoints(patterns []EndpointPattern) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsEndpointDisabled(patterns, c.Request.Method, c.FullPath()) {
+			abortWithError(c, http.StatusNotFound, "endpoint not available")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ETagHeader is the standard header for the validator of the response representation
+	ETagHeader = "ETag"
+	// etagKey is the Gin context key set when the ETag middleware validates the response
+	etagKey = "etag"
+)
+
+// ETag adds an ETag header to the successful responses, computed as the
+// SHA-256 of the response body. As the list responses are rendered in a
+// deterministic order, and their time based fields are computed as of the
+// last scan instead of the current time, the ETag only changes when the
+// inventory does, and it's the same on every API replica reading the same DB.
+// Requests with a matching If-None-Match header receive a 304 Not Modified
+// without body.
+// The ETag is weak, so it stays valid for the gzip compressed responses.
+// The streamed responses (see Stream) compute their ETag before being sent
+// (see StreamETag).
+func ETag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(etagKey, true)
+		writer := newBufferedWriter(c)
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		body := writer.body.Bytes()
+		// The streamed bodies are already sent
+		if IsStreamed(c) {
+			return
+		}
+		if c.Writer.Status() != http.StatusOK {
+			if len(body) > 0 {
+				_, _ = c.Writer.Write(body)
+			}
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		if validateETag(c, sum[:]) {
+			return
+		}
+
+		if len(body) > 0 {
+			_, _ = c.Writer.Write(body)
+		}
+	}
+}
+
+// StreamETag sets the ETag header of a response streamed by the handler (see
+// Stream), which the ETag middleware can't hash as it's sent as it's written.
+// The body is written first by write into the SHA-256 hash, without buffering
+// it, so the ETag is the same one of the buffered responses. It reports if the
+// request had a matching If-None-Match header, in which case a 304 Not
+// Modified is already sent and the handler must not write the body. Nothing is
+// done when the ETag middleware doesn't run on the request.
+func StreamETag(c *gin.Context, write func(w io.Writer) error) (bool, error) {
+	if !c.GetBool(etagKey) {
+		return false, nil
+	}
+	hash := sha256.New()
+	if err := write(hash); err != nil {
+		return false, err
+	}
+	return validateETag(c, hash.Sum(nil)), nil
+}
+
+// validateETag sets the ETag header from the SHA-256 sum of the body, and
+// sends a 304 Not Modified if the If-None-Match header matches it. It reports
+// if the 304 was sent
+func validateETag(c *gin.Context, sum []byte) bool {
+	etag := `W/"` + hex.EncodeToString(sum) + `"`
+	c.Header(ETagHeader, etag)
+	if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Length")
+	c.Writer.WriteHeader(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+// etagMatches checks if the If-None-Match header contains the given ETag or
+// the "*" wildcard. As defined for If-None-Match, the weak comparison is
+// used, so the opaque tags are compared regardless of the W/ prefix
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
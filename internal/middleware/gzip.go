@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipEncoding is the Content-Encoding of the compressed responses
+const gzipEncoding = "gzip"
+
+// Gzip compresses the response bodies of at least minSize bytes when the
+// client accepts gzip on its Accept-Encoding header. Smaller responses (e.g.
+// healthchecks) are sent as they are, as compressing them doesn't pay off.
+// The body is buffered until the handler finishes, so the Content-Length of
+// both compressed and uncompressed responses is the size actually sent.
+// Responses already encoded by the handler (e.g. /metrics) are not modified.
+// The streamed responses (see Stream) are compressed as they are written,
+// whatever their size, and sent without Content-Length.
+func Gzip(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The response depends on the Accept-Encoding header, so the caches must key on it
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipWriter{bufferedWriter: newBufferedWriter(c)}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if writer.gz != nil {
+			_ = writer.gz.Close()
+			return
+		}
+		body := writer.body.Bytes()
+		if len(body) > 0 && len(body) >= minSize && c.Writer.Header().Get("Content-Encoding") == "" {
+			if compressed, err := gzipBody(body); err == nil {
+				c.Writer.Header().Set("Content-Encoding", gzipEncoding)
+				body = compressed
+			}
+		}
+
+		if len(body) > 0 {
+			c.Writer.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			_, _ = c.Writer.Write(body)
+		}
+	}
+}
+
+// gzipWriter buffers the response body as bufferedWriter does, except for
+// the streamed responses, which are compressed as they are written
+type gzipWriter struct {
+	*bufferedWriter
+	gz *gzip.Writer
+}
+
+// Write compresses the streamed responses and buffers the rest
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.compressing() {
+		return w.gz.Write(data)
+	}
+	return w.bufferedWriter.Write(data)
+}
+
+// WriteString compresses the streamed responses and buffers the rest
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	if w.compressing() {
+		return w.gz.Write([]byte(s))
+	}
+	return w.bufferedWriter.WriteString(s)
+}
+
+// Flush sends the data compressed so far to the client
+func (w *gzipWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// compressing checks if the response is compressed as it's written, starting
+// the compression on the first write of a streamed response. The responses
+// already encoded by the handler are not compressed
+func (w *gzipWriter) compressing() bool {
+	if w.gz != nil {
+		return true
+	}
+	if !IsStreamed(w.ctx) || w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	w.Header().Set("Content-Encoding", gzipEncoding)
+	w.Header().Del("Content-Length")
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	return true
+}
+
+// acceptsGzip checks if the Accept-Encoding header allows gzip with a non
+// zero quality value. An explicit gzip coding takes precedence over the "*"
+// wildcard
+func acceptsGzip(header string) bool {
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case gzipEncoding:
+			return codingQuality(params) > 0
+		case "*":
+			wildcard = codingQuality(params) > 0
+		}
+	}
+	return wildcard
+}
+
+// codingQuality returns the "q" parameter of an Accept-Encoding coding. It
+// defaults to 1 if missing or malformed
+func codingQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(strings.TrimSpace(key), "q") {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				return q
+			}
+		}
+	}
+	return 1
+}
+
+// gzipBody compresses the body with the default compression level
+func gzipBody(body []byte) ([]byte, error) {
+	var out bytes.Buffer
+	gz := gzip.NewWriter(&out)
+	if _, err := gz.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
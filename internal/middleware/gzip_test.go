@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipTestPayload is a PureJSON body with characters escaped by the default JSON render
+type gzipTestPayload struct {
+	Items []string `json:"items"`
+}
+
+// newGzipTestEngine serves a large PureJSON list and a small healthcheck
+func newGzipTestEngine(minSize int) (*gin.Engine, gzipTestPayload) {
+	gin.SetMode(gin.TestMode)
+	payload := gzipTestPayload{}
+	for i := 0; i < 200; i++ {
+		payload.Items = append(payload.Items, "<cluster-"+strconv.Itoa(i)+"> & co")
+	}
+
+	engine := gin.New()
+	engine.Use(Gzip(minSize))
+	engine.GET("/instances", func(c *gin.Context) { c.PureJSON(http.StatusOK, payload) })
+	engine.GET("/healthz", func(c *gin.Context) { c.PureJSON(http.StatusOK, gin.H{"status": "ok"}) })
+	return engine, payload
+}
+
+// TestGzipPureJSONRoundTrip verifies the compressed PureJSON body decodes to the original payload
+func TestGzipPureJSONRoundTrip(t *testing.T) {
+	engine, payload := newGzipTestEngine(1024)
+
+	req := httptest.NewRequest(http.MethodGet, "/instances", nil)
+	req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected gzip Content-Encoding, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(rec.Body.Len()) {
+		t.Errorf("expected Content-Length %d, got %q", rec.Body.Len(), got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("expected Vary Accept-Encoding, got %q", got)
+	}
+
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("invalid gzip body: %v", err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("can't decompress body: %v", err)
+	}
+	// PureJSON doesn't escape the HTML characters, and the compression must keep them as they are
+	if !strings.Contains(string(body), "<cluster-0> & co") {
+		t.Errorf("expected unescaped HTML characters on the body, got %s", body[:64])
+	}
+
+	var decoded gzipTestPayload
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("can't decode body: %v", err)
+	}
+	if len(decoded.Items) != len(payload.Items) || decoded.Items[199] != payload.Items[199] {
+		t.Errorf("decoded payload doesn't match the original one")
+	}
+}
+
+// TestGzipSkipped verifies the responses are not compressed below the threshold or when not accepted
+func TestGzipSkipped(t *testing.T) {
+	engine, _ := newGzipTestEngine(1024)
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+	}{
+		{name: "Below threshold", path: "/healthz", acceptEncoding: "gzip"},
+		{name: "Not accepted", path: "/instances", acceptEncoding: ""},
+		{name: "Refused", path: "/instances", acceptEncoding: "gzip;q=0, *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("expected no Content-Encoding, got %q", got)
+			}
+			if !json.Valid(rec.Body.Bytes()) {
+				t.Errorf("expected a plain JSON body, got %q", rec.Body.String())
+			}
+		})
+	}
+}
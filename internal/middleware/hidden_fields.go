@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter buffers the response body so it can be transformed before
+// being sent to the client. Server-Sent Events streams are never buffered,
+// as they don't end until the client disconnects, and neither are the
+// responses streamed by the handler (see Stream)
+type bufferedWriter struct {
+	gin.ResponseWriter
+	ctx  *gin.Context
+	body bytes.Buffer
+}
+
+// newBufferedWriter returns a bufferedWriter wrapping the response writer of c
+func newBufferedWriter(c *gin.Context) *bufferedWriter {
+	return &bufferedWriter{ResponseWriter: c.Writer, ctx: c}
+}
+
+// Write buffers the response body instead of sending it to the client
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	if w.streaming() {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+// WriteString buffers the response body instead of sending it to the client
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	if w.streaming() {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+// streaming checks if the response is a Server-Sent Events stream or it's
+// streamed by the handler
+func (w *bufferedWriter) streaming() bool {
+	return strings.HasPrefix(w.Header().Get("Content-Type"), MIMEEventStream) || IsStreamed(w.ctx)
+}
+
+// HiddenFields removes the given fields from every JSON response. Fields are
+// matched against the JSON keys at any depth of the document (e.g. "totalCost"
+// removes the total cost of accounts, clusters and instances). As it runs after
+// the handler, any field selection requested by the client is applied first,
+// so the hidden fields can't be requested back. The filtered document keeps
+// the order of the keys and the HTML escaping of the response (see PureJSON).
+// Non JSON responses are not modified.
+func HiddenFields(fields []string) gin.HandlerFunc {
+	hidden := hiddenFieldSet(fields)
+
+	return func(c *gin.Context) {
+		if len(hidden) > 0 {
+			requireBufferedJSON(c)
+		}
+		writer := newBufferedWriter(c)
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		body := writer.body.Bytes()
+		if len(hidden) > 0 && strings.HasPrefix(c.Writer.Header().Get("Content-Type"), MIMEJSON) {
+			if filtered, err := removeJSONFields(body, hidden, !IsPureJSON(c)); err == nil {
+				body = filtered
+			}
+		}
+
+		if len(body) > 0 {
+			_, _ = c.Writer.Write(body)
+		}
+	}
+}
+
+// RemoveHiddenFields removes the given fields from a JSON document, as
+// HiddenFields does on the responses. It's meant for the documents not sent
+// as a whole response (e.g. Server-Sent Events), which HiddenFields can't
+// filter. The HTML characters are escaped, as on the default JSON render.
+func RemoveHiddenFields(body []byte, fields []string) ([]byte, error) {
+	hidden := hiddenFieldSet(fields)
+	if len(hidden) == 0 {
+		return body, nil
+	}
+	return removeJSONFields(body, hidden, true)
+}
+
+// hiddenFieldSet returns the set of the non empty fields
+func hiddenFieldSet(fields []string) map[string]struct{} {
+	hidden := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			hidden[field] = struct{}{}
+		}
+	}
+	return hidden
+}
+
+// removeJSONFields decodes the JSON document, removes the hidden keys from
+// every object and encodes it again. Numbers and the order of the keys are
+// kept as they were received
+func removeJSONFields(body []byte, hidden map[string]struct{}, escapeHTML bool) ([]byte, error) {
+	document, err := decodeOrderedJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	stripFields(document, hidden)
+	return encodeOrderedJSON(document, escapeHTML)
+}
+
+// stripFields removes the hidden keys from every object nested on value
+func stripFields(value any, hidden map[string]struct{}) {
+	switch v := value.(type) {
+	case *jsonObject:
+		v.members = slices.DeleteFunc(v.members, func(member jsonMember) bool {
+			_, ok := hidden[member.key]
+			return ok
+		})
+		for _, member := range v.members {
+			stripFields(member.value, hidden)
+		}
+	case []any:
+		for _, nested := range v {
+			stripFields(nested, hidden)
+		}
+	}
+}
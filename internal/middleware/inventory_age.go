@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InventoryAgeHeader is the header with the seconds elapsed since the last inventory scan
+const InventoryAgeHeader = "X-Inventory-Age"
+
+// InventoryAge adds the X-Inventory-Age header to the responses, with the
+// seconds elapsed since the last scan returned by lastScan, so the clients can
+// detect a stalled scanner. The header is omitted if the inventory was never
+// scanned or the last scan can't be retrieved.
+func InventoryAge(lastScan func() (*time.Time, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scannedAt, err := lastScan(); err == nil && scannedAt != nil {
+			age := max(time.Since(*scannedAt), 0)
+			c.Header(InventoryAgeHeader, strconv.FormatInt(int64(age.Seconds()), 10))
+		}
+		c.Next()
+	}
+}
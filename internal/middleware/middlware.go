@@ -1,14 +1,82 @@
 package middleware
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 )
 
-func SetCommonHeaders() gin.HandlerFunc {
+const (
+	// MIMEJSON is the default content type served by the API
+	MIMEJSON = "application/json"
+	// MIMECSV is the content type for CSV exports
+	MIMECSV = "text/csv"
+	// MIMENDJSON is the content type for newline delimited JSON streams
+	MIMENDJSON = "application/x-ndjson"
+	// MIMEYAML is the content type for YAML documents
+	MIMEYAML = "application/yaml"
+	// MIMEEventStream is the content type for Server-Sent Events streams
+	MIMEEventStream = "text/event-stream"
+
+	// MIMEJSONUTF8 is the Content-Type header of the JSON responses
+	MIMEJSONUTF8 = MIMEJSON + "; charset=utf-8"
+	// MIMEYAMLUTF8 is the Content-Type header of the YAML responses
+	MIMEYAMLUTF8 = MIMEYAML + "; charset=utf-8"
+
+	// NegotiatedFormatKey is the Gin context key where the negotiated content type is stored
+	NegotiatedFormatKey = "negotiated_format"
+	// PureJSONKey is the Gin context key set when the JSON responses are not HTML escaped
+	PureJSONKey = "pure_json"
+)
+
+// abortWithError aborts the request with the same error body used by the API
+// handlers: the message, the status code and the request ID
+func abortWithError(c *gin.Context, code int, message string) {
+	body := gin.H{"message": message, "code": code}
+	if id := GetRequestID(c); id != "" {
+		body["requestID"] = id
+	}
+	c.AbortWithStatusJSON(code, body)
+}
+
+// NegotiateContentType inspects the Accept header of the request and aborts
+// with 406 Not Acceptable if none of the offered content types is accepted by
+// the client. The first offered type is used when no Accept header is sent.
+// The negotiated type is stored on the context under NegotiatedFormatKey
+func NegotiateContentType(offered ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.NegotiateFormat(offered...)
+		if format == "" {
+			abortWithError(c, http.StatusNotAcceptable, "Not acceptable content type. Supported types: "+strings.Join(offered, ", "))
+			return
+		}
+		c.Set(NegotiatedFormatKey, format)
+		c.Next()
+	}
+}
+
+// GetNegotiatedFormat returns the content type negotiated for the request, or
+// MIMEJSON if the negotiation middleware didn't run
+func GetNegotiatedFormat(c *gin.Context) string {
+	if format := c.GetString(NegotiatedFormatKey); format != "" {
+		return format
+	}
+	return MIMEJSON
+}
+
+// PureJSON disables the HTML escaping (<, > and &) of the JSON responses, so
+// they are rendered as they are stored. It should only be used when the API
+// responses are never rendered by a browser
+func PureJSON() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// CORS
-		c.Header("Access-Control-Allow-Origin", "*")
-		// Future headers if needed
+		c.Set(PureJSONKey, true)
 		c.Next()
 	}
 }
+
+// IsPureJSON reports if the JSON responses of the request must not be HTML
+// escaped. They are escaped unless the PureJSON middleware ran
+func IsPureJSON(c *gin.Context) bool {
+	return c.GetBool(PureJSONKey)
+}
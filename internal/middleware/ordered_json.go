@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonObject is a decoded JSON object keeping the order of its keys, so the
+// documents transformed by the middlewares keep the key order they were
+// rendered with, instead of being sorted as the maps are on encoding
+type jsonObject struct {
+	members []jsonMember
+}
+
+// jsonMember is a key of a jsonObject and its decoded value
+type jsonMember struct {
+	key   string
+	value any
+}
+
+// decodeOrderedJSON decodes a JSON document into *jsonObject, []any and
+// scalar values. Numbers are kept as they were received (json.Number)
+func decodeOrderedJSON(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	return decodeOrderedValue(decoder)
+}
+
+// decodeOrderedValue decodes the next JSON value of the decoder
+func decodeOrderedValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := &jsonObject{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.members = append(object.members, jsonMember{key: key.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	case '[':
+		// Empty lists are still encoded as [] instead of null
+		list := make([]any, 0)
+		for decoder.More() {
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = decoder.Token()
+		return list, err
+	}
+	return nil, fmt.Errorf("unexpected JSON delimiter %q", delim)
+}
+
+// encodeOrderedJSON encodes a document decoded by decodeOrderedJSON, with its
+// keys in the decoded order
+func encodeOrderedJSON(document any, escapeHTML bool) ([]byte, error) {
+	var out, scalar bytes.Buffer
+	encoder := json.NewEncoder(&scalar)
+	encoder.SetEscapeHTML(escapeHTML)
+
+	var encode func(value any) error
+	encode = func(value any) error {
+		switch v := value.(type) {
+		case *jsonObject:
+			out.WriteByte('{')
+			for i, member := range v.members {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				if err := encode(member.key); err != nil {
+					return err
+				}
+				out.WriteByte(':')
+				if err := encode(member.value); err != nil {
+					return err
+				}
+			}
+			out.WriteByte('}')
+		case []any:
+			out.WriteByte('[')
+			for i, nested := range v {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				if err := encode(nested); err != nil {
+					return err
+				}
+			}
+			out.WriteByte(']')
+		default:
+			scalar.Reset()
+			if err := encoder.Encode(v); err != nil {
+				return err
+			}
+			out.Write(bytes.TrimSuffix(scalar.Bytes(), []byte("\n")))
+		}
+		return nil
+	}
+
+	if err := encode(document); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
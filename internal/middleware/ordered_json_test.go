@@ -0,0 +1,55 @@
+package middleware
+
+import "testing"
+
+// TestOrderedJSONRoundTrip verifies the decoded documents are encoded again with the same keys order, numbers and escaping
+func TestOrderedJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		escapeHTML bool
+		want       string
+	}{
+		{name: "Order", body: `{"id":"i-1","name":"a","cost":1.50,"tags":[],"meta":{}}`, want: `{"id":"i-1","name":"a","cost":1.50,"tags":[],"meta":{}}`},
+		{name: "Nested", body: `{"z":[{"b":1,"a":null}],"y":true}`, want: `{"z":[{"b":1,"a":null}],"y":true}`},
+		{name: "Pure", body: `{"name":"<a> & b"}`, want: `{"name":"<a> & b"}`},
+		{name: "Escaped", body: `{"name":"<a> & b"}`, escapeHTML: true, want: `{"name":"\u003ca\u003e \u0026 b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := decodeOrderedJSON([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("can't decode the document: %v", err)
+			}
+			got, err := encodeOrderedJSON(document, tt.escapeHTML)
+			if err != nil {
+				t.Fatalf("can't encode the document: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestTransformedJSONKeepsOrder verifies the hidden and projected fields keep the order of the remaining keys
+func TestTransformedJSONKeepsOrder(t *testing.T) {
+	body := []byte(`{"count":2,"instances":[{"id":"i-1","name":"a","totalCost":1},{"id":"i-2","name":"b","totalCost":2}],"total":2}`)
+
+	hidden, err := removeJSONFields(body, map[string]struct{}{"totalCost": {}}, true)
+	if err != nil {
+		t.Fatalf("can't remove the hidden fields: %v", err)
+	}
+	if want := `{"count":2,"instances":[{"id":"i-1","name":"a"},{"id":"i-2","name":"b"}],"total":2}`; string(hidden) != want {
+		t.Errorf("expected %s, got %s", want, hidden)
+	}
+
+	projected, _, err := projectJSONFields(body, map[string]struct{}{"totalcost": {}, "id": {}}, map[string]struct{}{}, true)
+	if err != nil {
+		t.Fatalf("can't project the fields: %v", err)
+	}
+	if want := `{"count":2,"instances":[{"id":"i-1","totalCost":1},{"id":"i-2","totalCost":2}],"total":2}`; string(projected) != want {
+		t.Errorf("expected %s, got %s", want, projected)
+	}
+}
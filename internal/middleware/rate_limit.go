@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitSweepInterval is how often the idle clients are removed from the rate limiter
+const rateLimitSweepInterval = time.Minute
+
+// tokenBucket is the requests allowance of a single client on a single route
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket for every client and route
+type rateLimiter struct {
+	rate      float64
+	burst     float64
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRateLimiter creates a rate limiter refilling rate tokens per second, up to burst
+func newRateLimiter(rate float64, burst int, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		now:       now,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: now(),
+	}
+}
+
+// allow takes a token from the bucket of the key. If it's empty, it returns
+// false and the time until the next token is available
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// sweep removes the buckets that would be full by now, as they are the same
+// as a new one. Must be called with the lock held
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimit aborts with 429 Too Many Requests the requests of a client over
+// rate requests per second on the same route, allowing bursts of up to burst
+// requests. Clients are identified by their IP (see gin.Engine.TrustedPlatform
+// and SetTrustedProxies when served behind a proxy). The Retry-After header
+// tells when the next request will be accepted. Routes matching any of the
+// exempt patterns (e.g. the Kubernetes probes) are never limited. It's a no-op
+// if rate is not positive. burst defaults to the rate (at least 1) if not positive.
+func RateLimit(rate float64, burst int, exempt []EndpointPattern) gin.HandlerFunc {
+	if rate <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if burst <= 0 {
+		burst = max(1, int(math.Ceil(rate)))
+	}
+	limiter := newRateLimiter(rate, burst, time.Now)
+	return limiter.middleware(exempt)
+}
+
+// middleware returns the Gin middleware applying the limiter
+func (l *rateLimiter) middleware(exempt []EndpointPattern) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route != "" && matchesAnyEndpoint(exempt, c.Request.Method, route) {
+			c.Next()
+			return
+		}
+
+		if ok, wait := l.allow(c.ClientIP() + " " + route); !ok {
+			c.Header("Retry-After", strconv.Itoa(max(1, int(math.Ceil(wait.Seconds())))))
+			abortWithError(c, http.StatusTooManyRequests, "Too many requests")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRateLimit verifies the clients are limited per route once their burst is exhausted, except on the exempt routes
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(1, 2, func() time.Time { return now })
+
+	engine := gin.New()
+	engine.Use(limiter.middleware([]EndpointPattern{{Method: http.MethodGet, Route: "/healthz"}}))
+	engine.GET("/instances", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/clusters", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(path string, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":12345"
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Burst
+	for i := 0; i < 2; i++ {
+		if rec := request("/instances", "10.0.0.1"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status %d, got %d", i, http.StatusOK, rec.Code)
+		}
+	}
+	rec := request("/instances", "10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d once the burst is exhausted, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("expected Retry-After 1, got %q", got)
+	}
+
+	// Other routes, other clients and exempt routes have their own allowance
+	if rec := request("/clusters", "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Errorf("expected another route not to be limited, got %d", rec.Code)
+	}
+	if rec := request("/instances", "10.0.0.2"); rec.Code != http.StatusOK {
+		t.Errorf("expected another client not to be limited, got %d", rec.Code)
+	}
+	for i := 0; i < 5; i++ {
+		if rec := request("/healthz", "10.0.0.1"); rec.Code != http.StatusOK {
+			t.Fatalf("expected the exempt route not to be limited, got %d", rec.Code)
+		}
+	}
+
+	// A token is refilled every second
+	now = now.Add(time.Second)
+	if rec := request("/instances", "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Errorf("expected a refilled token after 1s, got %d", rec.Code)
+	}
+	if rec := request("/instances", "10.0.0.1"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected a single refilled token, got %d", rec.Code)
+	}
+
+	// Idle clients are removed once their bucket is full
+	now = now.Add(rateLimitSweepInterval)
+	request("/instances", "10.0.0.3")
+	if _, ok := limiter.buckets["10.0.0.1 /instances"]; ok {
+		t.Errorf("expected the idle client to be removed")
+	}
+}
+
+// TestRateLimitDisabled verifies the limiter is a no-op without rate
+func TestRateLimitDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RateLimit(0, 0, nil))
+	engine.GET("/instances", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 100; i++ {
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/instances", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status %d, got %d", i, http.StatusOK, rec.Code)
+		}
+	}
+}
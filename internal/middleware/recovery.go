@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Recovery recovers the panics of the handlers and of the middlewares
+// registered after it, so a single bad request can't take down the server nor
+// leave the client without response. The panic is logged with its stack and
+// the request ID, and the request is aborted with a 500 Internal Server Error
+// using the same error body as the API handlers.
+// http.ErrAbortHandler panics are re-raised, as they are the standard way to
+// abort a response, and no body is written if the client already closed the
+// connection or the response was already sent.
+func Recovery(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := c.Writer
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// The buffered writers of the middlewares unwound by the panic are
+			// discarded, as they are never flushed
+			c.Writer = writer
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			err, ok := recovered.(error)
+			if !ok {
+				err = fmt.Errorf("%v", recovered)
+			}
+			logger.Error("Recovered from panic",
+				zap.String("request_id", GetRequestID(c)),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.Error(err),
+				zap.Stack("stack"))
+
+			if isConnectionClosed(err) || c.Writer.Written() {
+				_ = c.Error(err)
+				c.Abort()
+				return
+			}
+			abortWithError(c, http.StatusInternalServerError, "Internal server error")
+		}()
+		c.Next()
+	}
+}
+
+// isConnectionClosed checks if the error comes from writing to a connection
+// closed by the client, so there is no point on writing a response
+func isConnectionClosed(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestRecovery verifies a panicking handler is answered with a JSON 500 carrying the request ID, and the panic is logged
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.ErrorLevel)
+
+	engine := gin.New()
+	engine.Use(RequestID())
+	engine.Use(Recovery(zap.New(core)))
+	engine.GET("/clusters", func(c *gin.Context) {
+		var clusters map[string]any
+		_ = clusters["c1"].(string)
+	})
+	engine.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/clusters", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	var body struct {
+		Message   string `json:"message"`
+		Code      int    `json:"code"`
+		RequestID string `json:"requestID"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("can't decode body %q: %v", rec.Body.String(), err)
+	}
+	if body.Code != http.StatusInternalServerError || body.RequestID != "req-1" {
+		t.Errorf("unexpected error body: %+v", body)
+	}
+
+	entries := logs.FilterMessage("Recovered from panic").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected the panic to be logged once, got %d entries", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["request_id"] != "req-1" || fields["stack"] == "" {
+		t.Errorf("expected the request ID and the stack on the log, got %+v", fields)
+	}
+
+	// The server keeps serving after the panic
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d after the panic, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+// TestRecoveryMiddlewarePanic verifies the panics of the middlewares registered after Recovery are answered, even behind a buffered writer
+func TestRecoveryMiddlewarePanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.ErrorLevel)
+
+	engine := gin.New()
+	engine.Use(RequestID())
+	engine.Use(Recovery(zap.New(core)))
+	engine.Use(HiddenFields([]string{"totalCost"}))
+	engine.Use(func(c *gin.Context) {
+		panic("middleware failure")
+	})
+	handled := false
+	engine.GET("/clusters", func(c *gin.Context) { handled = true })
+
+	req := httptest.NewRequest(http.MethodGet, "/clusters", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if handled {
+		t.Error("expected the handler to be skipped after the middleware panic")
+	}
+	var body struct {
+		RequestID string `json:"requestID"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("can't decode body %q: %v", rec.Body.String(), err)
+	}
+	if body.RequestID != "req-1" {
+		t.Errorf("expected the request ID on the error body, got %q", body.RequestID)
+	}
+	if entries := logs.FilterMessage("Recovered from panic").All(); len(entries) != 1 || entries[0].ContextMap()["request_id"] != "req-1" {
+		t.Errorf("expected the panic to be logged once with the request ID, got %+v", entries)
+	}
+}
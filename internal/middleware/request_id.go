@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader is the header carrying the ID of the request, on both the request and the response
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the Gin context key where the ID of the request is stored
+	RequestIDKey = "request_id"
+
+	// maxRequestIDLength is the maximum length of the request IDs accepted from the clients
+	maxRequestIDLength = 128
+)
+
+// RequestID identifies every request with the X-Request-ID header sent by the
+// client (e.g. by a proxy), or a new random ID if missing or invalid. The ID
+// is stored on the context under RequestIDKey and echoed back on the response
+// header, so a failing request can be correlated with its log lines.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID of the request, or an empty string if the
+// RequestID middleware didn't run
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// isValidRequestID checks if a client request ID can be logged and echoed as
+// it is: not empty, not too long and only made of letters, digits and "-_.:"
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128 bits ID, hex encoded. The current time is
+// used if the random source fails, so the request is still identified
+func newRequestID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRequestID verifies the client request IDs are kept when valid and replaced otherwise
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RequestID())
+	engine.GET("/clusters", func(c *gin.Context) { c.String(http.StatusOK, GetRequestID(c)) })
+
+	tests := []struct {
+		name     string
+		header   string
+		wantKept bool
+	}{
+		{name: "Missing"},
+		{name: "Valid", header: "req-42_a.b:c", wantKept: true},
+		{name: "Invalid characters", header: "req 42\nforged-log-line"},
+		{name: "Too long", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/clusters", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			if id == "" || id != rec.Body.String() {
+				t.Fatalf("expected the response header to match the context ID, got %q and %q", id, rec.Body.String())
+			}
+			if kept := id == tt.header; kept != tt.wantKept {
+				t.Errorf("expected kept=%v, got ID %q", tt.wantKept, id)
+			}
+			if !tt.wantKept && len(id) != 32 {
+				t.Errorf("expected a new 128 bits hex ID, got %q", id)
+			}
+		})
+	}
+}
+
+// TestRequestIDUnique verifies every request without ID receives a different one
+func TestRequestIDUnique(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(RequestID())
+	engine.GET("/clusters", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/clusters", nil))
+		id := rec.Header().Get(RequestIDHeader)
+		if _, ok := seen[id]; ok {
+			t.Fatalf("duplicated request ID %q", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
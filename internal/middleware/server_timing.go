@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServerTimingHeader is the standard header for exposing the server side timing metrics to the browsers
+const ServerTimingHeader = "Server-Timing"
+
+// serverTimingWriter buffers the response body as bufferedWriter does, for
+// being able to include the Server-Timing header once the whole response was
+// rendered. It also records when the rendering started (status written) and
+// finished (last body write). The header is set right before the response
+// headers are sent, so it's also included on the streamed responses and on
+// the responses without body (e.g. 304 Not Modified)
+type serverTimingWriter struct {
+	*bufferedWriter
+	start       time.Time
+	renderStart time.Time
+	renderEnd   time.Time
+}
+
+// markRender records the rendering step
+func (w *serverTimingWriter) markRender() {
+	now := time.Now()
+	if w.renderStart.IsZero() {
+		w.renderStart = now
+	}
+	w.renderEnd = now
+}
+
+// setHeader adds the Server-Timing header with the timing measured so far,
+// unless the headers were already sent
+func (w *serverTimingWriter) setHeader() {
+	if w.ResponseWriter.Written() {
+		return
+	}
+	end := time.Now()
+	handlerEnd, render := end, time.Duration(0)
+	if !w.renderStart.IsZero() {
+		handlerEnd = w.renderStart
+		render = w.renderEnd.Sub(w.renderStart)
+	}
+
+	w.Header().Set(ServerTimingHeader, fmt.Sprintf(
+		`handler;desc="Data fetch and processing";dur=%.3f, render;desc="Serialization";dur=%.3f, total;dur=%.3f`,
+		toMilliseconds(handlerEnd.Sub(w.start)),
+		toMilliseconds(render),
+		toMilliseconds(end.Sub(w.start)),
+	))
+}
+
+// WriteHeader marks the beginning of the rendering step
+func (w *serverTimingWriter) WriteHeader(code int) {
+	if w.renderStart.IsZero() {
+		w.renderStart = time.Now()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// WriteHeaderNow adds the Server-Timing header before sending the headers
+func (w *serverTimingWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write buffers the response body, except for the streamed responses, which
+// are sent with the Server-Timing header measured until their first write
+func (w *serverTimingWriter) Write(data []byte) (int, error) {
+	w.markRender()
+	if w.streaming() {
+		w.setHeader()
+	}
+	return w.bufferedWriter.Write(data)
+}
+
+// WriteString buffers the response body as Write does
+func (w *serverTimingWriter) WriteString(s string) (int, error) {
+	w.markRender()
+	if w.streaming() {
+		w.setHeader()
+	}
+	return w.bufferedWriter.WriteString(s)
+}
+
+// ServerTiming adds the Server-Timing header to the responses, containing the
+// time spent by the handler retrieving and processing the data, the time spent
+// serializing the response, and the total time. As the response body is
+// buffered until the handler finishes, it should be enabled only for debugging.
+// The Server-Sent Events and the streamed responses (see Stream) are not
+// buffered, and their header only measures the time until their first write.
+func ServerTiming() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &serverTimingWriter{bufferedWriter: newBufferedWriter(c), start: time.Now()}
+		c.Writer = writer
+
+		c.Next()
+
+		writer.setHeader()
+		c.Writer = writer.ResponseWriter
+		if writer.body.Len() > 0 {
+			_, _ = c.Writer.Write(writer.body.Bytes())
+		}
+	}
+}
+
+// toMilliseconds converts a duration into milliseconds as expected by the Server-Timing header
+func toMilliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestServerTiming verifies the Server-Timing header is set on the buffered, streamed and 304 responses, and the streamed bodies are not buffered
+func TestServerTiming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		handler     func(c *gin.Context)
+		ifNoneMatch string
+		wantCode    int
+		wantBody    string
+		wantSent    bool
+	}{
+		{
+			name:     "Buffered",
+			handler:  func(c *gin.Context) { c.String(http.StatusOK, "clusters") },
+			wantCode: http.StatusOK,
+			wantBody: "clusters",
+		},
+		{
+			name: "Streamed",
+			handler: func(c *gin.Context) {
+				Stream(c)
+				c.Status(http.StatusOK)
+				_, _ = c.Writer.WriteString("clus")
+				c.Writer.Flush()
+				_, _ = c.Writer.WriteString("ters")
+			},
+			wantCode: http.StatusOK,
+			wantBody: "clusters",
+			wantSent: true,
+		},
+		{
+			name: "Event stream",
+			handler: func(c *gin.Context) {
+				c.Header("Content-Type", MIMEEventStream)
+				c.Status(http.StatusOK)
+				_, _ = c.Writer.WriteString("clus")
+				c.Writer.Flush()
+				_, _ = c.Writer.WriteString("ters")
+			},
+			wantCode: http.StatusOK,
+			wantBody: "clusters",
+			wantSent: true,
+		},
+		{
+			name:        "Not modified",
+			handler:     func(c *gin.Context) { c.String(http.StatusOK, "clusters") },
+			ifNoneMatch: "*",
+			wantCode:    http.StatusNotModified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			sentBeforeEnd := false
+
+			engine := gin.New()
+			engine.Use(ServerTiming())
+			engine.GET("/clusters", ETag(), func(c *gin.Context) {
+				tt.handler(c)
+				sentBeforeEnd = rec.Body.Len() > 0
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/clusters", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			engine.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+			// The headers as they were sent, as the recorder keeps the later changes
+			if got := rec.Result().Header.Get(ServerTimingHeader); !strings.Contains(got, "total;dur=") {
+				t.Errorf("expected the Server-Timing header, got %q", got)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, got)
+			}
+			if sentBeforeEnd != tt.wantSent {
+				t.Errorf("expected sent before the handler ends=%v, got %v", tt.wantSent, sentBeforeEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WarningHeader is the standard header for the warnings about the response
+const WarningHeader = "Warning"
+
+// SparseFields projects the items of the JSON responses to the fields
+// requested by the client. The fieldsParam query param is the comma
+// separated list of fields kept on every item (e.g. "name,region"), and the
+// excludeParam one the fields removed from them (e.g. "Instances" to drop
+// the nested instances of the clusters). Items are the objects on the lists
+// of the response, at any depth, so the envelope of the lists (e.g. count or
+// total) is always kept, and field names are case insensitive. Requested
+// fields not found on any item are ignored, and reported on a Warning
+// header. The remaining keys keep their order. Non JSON and non successful
+// responses are not modified.
+func SparseFields(fieldsParam string, excludeParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := parseFieldList(c.QueryArray(fieldsParam))
+		exclude := parseFieldList(c.QueryArray(excludeParam))
+		if len(fields) == 0 && len(exclude) == 0 {
+			c.Next()
+			return
+		}
+
+		requireBufferedJSON(c)
+		writer := newBufferedWriter(c)
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		body := writer.body.Bytes()
+		if len(body) > 0 && c.Writer.Status() == http.StatusOK && strings.HasPrefix(c.Writer.Header().Get("Content-Type"), MIMEJSON) {
+			if projected, unknown, err := projectJSONFields(body, fields, exclude, !IsPureJSON(c)); err == nil {
+				body = projected
+				if len(unknown) > 0 {
+					c.Header(WarningHeader, `299 - "Unknown fields ignored: `+strings.Join(unknown, ", ")+`"`)
+				}
+			}
+		}
+
+		if len(body) > 0 {
+			_, _ = c.Writer.Write(body)
+		}
+	}
+}
+
+// parseFieldList reads the lowercase set of fields of the comma separated values
+func parseFieldList(values []string) map[string]struct{} {
+	fields := make(map[string]struct{})
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields[strings.ToLower(field)] = struct{}{}
+			}
+		}
+	}
+	return fields
+}
+
+// projectJSONFields decodes the JSON document, projects its items and
+// encodes it again. Numbers and the order of the keys are kept as they were
+// received. It returns the requested fields (kept or excluded) not found on
+// any item, sorted.
+func projectJSONFields(body []byte, fields map[string]struct{}, exclude map[string]struct{}, escapeHTML bool) ([]byte, []string, error) {
+	document, err := decodeOrderedJSON(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	found := make(map[string]struct{})
+	projectItems(document, fields, exclude, found)
+
+	out, err := encodeOrderedJSON(document, escapeHTML)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unknown []string
+	// Without items, there is no way to tell the unknown fields
+	if len(found) > 0 {
+		for field := range fields {
+			if _, ok := found[field]; !ok {
+				unknown = append(unknown, field)
+			}
+		}
+		for field := range exclude {
+			if _, ok := found[field]; !ok && !slices.Contains(unknown, field) {
+				unknown = append(unknown, field)
+			}
+		}
+		sort.Strings(unknown)
+	}
+	return out, unknown, nil
+}
+
+// projectItems projects the objects of every list nested on value, and
+// collects on found the lowercase fields of the items
+func projectItems(value any, fields map[string]struct{}, exclude map[string]struct{}, found map[string]struct{}) {
+	switch v := value.(type) {
+	case *jsonObject:
+		for _, member := range v.members {
+			projectItems(member.value, fields, exclude, found)
+		}
+	case []any:
+		for _, nested := range v {
+			item, ok := nested.(*jsonObject)
+			if !ok {
+				projectItems(nested, fields, exclude, found)
+				continue
+			}
+			item.members = slices.DeleteFunc(item.members, func(member jsonMember) bool {
+				lower := strings.ToLower(member.key)
+				found[lower] = struct{}{}
+				_, keep := fields[lower]
+				_, excluded := exclude[lower]
+				return excluded || (len(fields) > 0 && !keep)
+			})
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestSparseFields verifies the listed items are projected to the requested fields, keeping the envelope of the lists
+func TestSparseFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(SparseFields("fields", "exclude"))
+	engine.GET("/clusters", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"count": 2,
+			"clusters": []gin.H{
+				{"name": "c1", "region": "us-east-1", "instanceCount": 1, "Instances": []gin.H{{"id": "i1"}}},
+				{"name": "c2", "region": "eu-west-1", "instanceCount": 0, "Instances": []gin.H{}},
+			},
+		})
+	})
+	engine.GET("/empty", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"clusters": []gin.H{}})
+	})
+	engine.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "cluster 'c3' not found", "code": http.StatusNotFound})
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		wantCode    int
+		wantBody    string
+		wantWarning string
+	}{
+		{
+			name:     "Fields",
+			path:     "/clusters?fields=name,instanceCount",
+			wantCode: http.StatusOK,
+			wantBody: `{"clusters":[{"instanceCount":1,"name":"c1"},{"instanceCount":0,"name":"c2"}],"count":2}`,
+		},
+		{
+			name:     "Repeated and case insensitive fields",
+			path:     "/clusters?fields=NAME&fields=region",
+			wantCode: http.StatusOK,
+			wantBody: `{"clusters":[{"name":"c1","region":"us-east-1"},{"name":"c2","region":"eu-west-1"}],"count":2}`,
+		},
+		{
+			name:     "Exclude",
+			path:     "/clusters?exclude=instances",
+			wantCode: http.StatusOK,
+			wantBody: `{"clusters":[{"instanceCount":1,"name":"c1","region":"us-east-1"},{"instanceCount":0,"name":"c2","region":"eu-west-1"}],"count":2}`,
+		},
+		{
+			name:        "Unknown fields",
+			path:        "/clusters?fields=name,owner&exclude=tags",
+			wantCode:    http.StatusOK,
+			wantBody:    `{"clusters":[{"name":"c1"},{"name":"c2"}],"count":2}`,
+			wantWarning: `299 - "Unknown fields ignored: owner, tags"`,
+		},
+		{
+			name:     "No items",
+			path:     "/empty?fields=owner",
+			wantCode: http.StatusOK,
+			wantBody: `{"clusters":[]}`,
+		},
+		{
+			name:     "Error",
+			path:     "/missing?fields=name",
+			wantCode: http.StatusNotFound,
+			wantBody: `{"code":404,"message":"cluster 'c3' not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, got)
+			}
+			if got := rec.Header().Get(WarningHeader); got != tt.wantWarning {
+				t.Errorf("expected Warning %q, got %q", tt.wantWarning, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+const (
+	// StreamedKey is the Gin context key set when the handler streams the response body
+	StreamedKey = "streamed_response"
+	// bufferedJSONKey is the Gin context key set when a middleware transforms the whole JSON body
+	bufferedJSONKey = "buffered_json"
+)
+
+// Stream flags the response of the request as streamed, so its body is sent
+// to the client as it's written instead of being buffered until the handler
+// finishes. Gzip compresses it on the fly, and ETag doesn't validate it, as
+// the hash of the body is only known once it's already sent, so the handler
+// must compute it beforehand (see StreamETag). It must be called before
+// writing the body.
+func Stream(c *gin.Context) {
+	c.Set(StreamedKey, true)
+}
+
+// IsStreamed reports if the response of the request is streamed (see Stream)
+func IsStreamed(c *gin.Context) bool {
+	return c.GetBool(StreamedKey)
+}
+
+// CanStreamJSON reports if the JSON response of the request can be streamed.
+// It can't when a middleware transforms the whole JSON document (HiddenFields,
+// SparseFields and YAML), so those responses must be rendered at once.
+func CanStreamJSON(c *gin.Context) bool {
+	return !c.GetBool(bufferedJSONKey)
+}
+
+// requireBufferedJSON flags the JSON response of the request as transformed
+// as a whole by a middleware, so it's not streamed (see CanStreamJSON)
+func requireBufferedJSON(c *gin.Context) {
+	c.Set(bufferedJSONKey, true)
+}
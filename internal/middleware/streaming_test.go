@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestStreamedResponse verifies the streamed bodies are sent as they are written, compressed on the fly and without ETag
+func TestStreamedResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	sentBeforeEnd := 0
+
+	engine := gin.New()
+	engine.Use(Gzip(1024), HiddenFields(nil))
+	engine.GET("/instances", ETag(), func(c *gin.Context) {
+		Stream(c)
+		c.Header("Content-Type", MIMEJSONUTF8)
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString(`{"instances":[`)
+		c.Writer.Flush()
+		sentBeforeEnd = rec.Body.Len()
+		_, _ = c.Writer.WriteString(`]}`)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/instances", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	engine.ServeHTTP(rec, req)
+
+	if sentBeforeEnd == 0 {
+		t.Errorf("expected the streamed body to be sent before the handler ends")
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected gzip Content-Encoding, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Length"); got != "" {
+		t.Errorf("expected no Content-Length, got %q", got)
+	}
+	if got := rec.Header().Get(ETagHeader); got != "" {
+		t.Errorf("expected no ETag, got %q", got)
+	}
+
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("invalid gzip body: %v", err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("can't decompress body: %v", err)
+	}
+	if string(body) != `{"instances":[]}` {
+		t.Errorf("unexpected body %s", body)
+	}
+}
+
+// TestCanStreamJSON verifies the JSON responses are not streamed when a middleware transforms the whole document
+func TestCanStreamJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		hidden []string
+		query  string
+		want   bool
+	}{
+		{name: "Plain", want: true},
+		{name: "NoHiddenFields", hidden: []string{" "}, want: true},
+		{name: "HiddenFields", hidden: []string{"totalCost"}},
+		{name: "SparseFields", query: "?fields=id"},
+		{name: "ExcludedFields", query: "?exclude=tags"},
+		{name: "YAML", query: "?format=yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			engine := gin.New()
+			engine.Use(YAML("format"), HiddenFields(tt.hidden), SparseFields("fields", "exclude"))
+			engine.GET("/instances", func(c *gin.Context) {
+				got = CanStreamJSON(c)
+				c.Status(http.StatusOK)
+			})
+			engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/instances"+tt.query, nil))
+
+			if got != tt.want {
+				t.Errorf("expected CanStreamJSON %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
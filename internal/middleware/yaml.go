@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlFormat is the value of the format query param requesting YAML responses
+const yamlFormat = "yaml"
+
+// YAML converts the JSON responses to YAML for the clients requesting it,
+// either with the formatParam query param set to "yaml" (which takes
+// precedence) or with an Accept header negotiated to MIMEYAML. The YAML
+// document has the same keys, in the same order, as the JSON one, so empty
+// lists are still rendered as [] instead of null. Non JSON responses (e.g.
+// CSV) are not modified. It must run after NegotiateContentType and before
+// any middleware transforming the JSON responses (e.g. HiddenFields).
+func YAML(formatParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The same URL is served as JSON or YAML depending on the Accept header
+		c.Writer.Header().Add("Vary", "Accept")
+		if !wantsYAML(c, formatParam) {
+			c.Next()
+			return
+		}
+
+		requireBufferedJSON(c)
+		writer := newBufferedWriter(c)
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		body := writer.body.Bytes()
+		if len(body) > 0 && strings.HasPrefix(c.Writer.Header().Get("Content-Type"), MIMEJSON) {
+			if document, err := jsonToYAML(body); err == nil {
+				body = document
+				c.Writer.Header().Set("Content-Type", MIMEYAMLUTF8)
+				c.Writer.Header().Del("Content-Length")
+			}
+		}
+
+		if len(body) > 0 {
+			_, _ = c.Writer.Write(body)
+		}
+	}
+}
+
+// wantsYAML checks if the client requested a YAML response
+func wantsYAML(c *gin.Context, formatParam string) bool {
+	if format := c.Query(formatParam); format != "" {
+		return format == yamlFormat
+	}
+	return GetNegotiatedFormat(c) == MIMEYAML
+}
+
+// jsonToYAML converts a JSON document to YAML. As JSON is valid YAML, the
+// document is parsed as a YAML node tree, which keeps the order of the keys,
+// and encoded again in block style
+func jsonToYAML(body []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(body, &document); err != nil {
+		return nil, err
+	}
+	setBlockStyle(&document)
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// setBlockStyle removes the JSON (flow and quoted) style of every node, so
+// they are encoded as plain YAML. Strings that would be read back as another
+// type (e.g. "true" or "1") are still quoted by the encoder
+func setBlockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		setBlockStyle(child)
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// yamlTestPayload has the kind of values the API responses are made of
+type yamlTestPayload struct {
+	Count     int      `json:"count,omitempty"`
+	Instances []string `json:"instances"`
+	Name      string   `json:"name"`
+	Status    string   `json:"status"`
+	TotalCost float64  `json:"totalCost"`
+}
+
+// TestYAML verifies the JSON responses are converted to YAML when requested, keeping the keys, their order and the empty lists
+func TestYAML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(NegotiateContentType(MIMEJSON, MIMECSV, MIMEYAML))
+	engine.Use(YAML("format"))
+	engine.GET("/clusters", func(c *gin.Context) {
+		c.JSON(http.StatusOK, yamlTestPayload{Instances: []string{}, Name: "<c1> & co", Status: "true", TotalCost: 12.5})
+	})
+	engine.GET("/export", func(c *gin.Context) {
+		c.Data(http.StatusOK, MIMECSV, []byte("id,name\n"))
+	})
+
+	const wantYAML = "instances: []\nname: <c1> & co\nstatus: \"true\"\ntotalCost: 12.5\n"
+
+	tests := []struct {
+		name            string
+		path            string
+		accept          string
+		wantContentType string
+		wantBody        string
+	}{
+		{name: "Accept header", path: "/clusters", accept: MIMEYAML, wantContentType: MIMEYAMLUTF8, wantBody: wantYAML},
+		{name: "Format param", path: "/clusters?format=yaml", wantContentType: MIMEYAMLUTF8, wantBody: wantYAML},
+		{name: "Format param over Accept header", path: "/clusters?format=json", accept: MIMEYAML, wantContentType: MIMEJSONUTF8},
+		{name: "Default", path: "/clusters", wantContentType: MIMEJSONUTF8},
+		{name: "Non JSON response", path: "/export?format=yaml", wantContentType: MIMECSV, wantBody: "id,name\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.wantContentType, got)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("expected Vary Accept, got %q", got)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("expected body\n%s\ngot\n%s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -31,9 +31,18 @@ type InstanceDB struct {
 	// Status is the current operational status of the instance (e.g., running, stopped).
 	Status inventory.InstanceStatus `db:"status"`
 
+	// ProviderState is the raw instance state reported by the cloud provider.
+	ProviderState inventory.ProviderState `db:"provider_state"`
+
 	// ClusterID is the identifier of the cluster to which the instance belongs.
 	ClusterID string `db:"cluster_id"`
 
+	// Protected excludes the instance from the power actions.
+	Protected bool `db:"protected"`
+
+	// IAMRole is the IAM role or service account attached to the instance.
+	IAMRole string `db:"iam_role"`
+
 	// TagKey is the key of a tag associated with the instance.
 	TagKey string `db:"key"`
 
@@ -58,6 +67,9 @@ type InstanceDB struct {
 
 	// TotalCost represents the total cost of the instance in US dollars since its creation.
 	TotalCost float64 `db:"total_cost"`
+
+	// CPUUtilization is the average CPU utilization (percentage) of the instance. nil if unknown.
+	CPUUtilization *float64 `db:"cpu_utilization"`
 }
 
 // AuditLog represents an immutable record of an action taken within the system.
@@ -95,6 +107,7 @@ type SystemAuditLogs struct {
 }
 
 type OverviewSummary struct {
+	Accounts  AccountsSummary  `json:"accounts"`
 	Clusters  ClustersSummary  `json:"clusters"`
 	Instances InstancesSummary `json:"instances"`
 	Providers ProvidersSummary `json:"providers"`
@@ -105,14 +118,24 @@ type Scanner struct {
 	LastScanTimestamp *time.Time `json:"last_scan_timestamp"`
 }
 
+// AccountsSummary is the number of accounts, and the number of active (non
+// Terminated) clusters on every account
+type AccountsSummary struct {
+	Count              int            `json:"count"`
+	ClustersPerAccount map[string]int `json:"clusters_per_account"`
+}
+
 type ClustersSummary struct {
+	Count    int `json:"count"`
 	Running  int `json:"running"`
 	Stopped  int `json:"stopped"`
 	Archived int `json:"archived"`
 }
 
 type InstancesSummary struct {
-	Count int `json:"count"`
+	Count      int                              `json:"count"`
+	ByStatus   map[inventory.InstanceStatus]int `db:"-" json:"by_status"`
+	ByProvider map[inventory.CloudProvider]int  `db:"-" json:"by_provider"`
 }
 
 type ProvidersSummary struct {
@@ -126,6 +149,28 @@ type ProviderDetail struct {
 	ClusterCount int `json:"cluster_count"`
 }
 
+// InstanceCost is the cost of an instance on a billing window
+type InstanceCost struct {
+	InstanceID string  `db:"id" json:"instanceID"`
+	Name       string  `db:"name" json:"name"`
+	Cost       float64 `db:"cost" json:"cost"`
+}
+
+// ProviderCounts is the number of resources of a cloud provider in the inventory
+type ProviderCounts struct {
+	Provider      inventory.CloudProvider `db:"provider" json:"provider"`
+	AccountCount  int                     `db:"account_count" json:"account_count"`
+	ClusterCount  int                     `db:"cluster_count" json:"cluster_count"`
+	InstanceCount int                     `db:"instance_count" json:"instance_count"`
+}
+
+// InventoryCounts is the number of resources of every kind in the inventory
+type InventoryCounts struct {
+	Accounts  int `db:"accounts"`
+	Clusters  int `db:"clusters"`
+	Instances int `db:"instances"`
+}
+
 // DBScheduledAction is an intermediate struct used to map Scheduled Actions and their target's data into actions.ScheduledActions
 // It provides a detailed representation of when, what action, and which target the action has
 type DBScheduledAction struct {
@@ -156,6 +201,9 @@ type DBScheduledAction struct {
 	// Instances is the list of instances of the cluster that will be impacted by the aciton
 	Instances pq.StringArray `db:"instances"`
 
+	// SkippedInstances is the list of protected instances of the cluster that won't be impacted by the action
+	SkippedInstances pq.StringArray `db:"skipped_instances"`
+
 	// Status represents the status of the current action. Check action_status table for more info
 	Status string `db:"status"`
 
@@ -193,6 +241,7 @@ func FromDBScheduledActionToScheduledAction(action DBScheduledAction) *actions.S
 		action.ClusterID,
 		action.Instances,
 	)
+	target.SkippedInstances = action.SkippedInstances
 
 	scheduledAction := actions.NewScheduledAction(action.Operation, target, action.Status, action.Enable, action.Timestamp.Time)
 	scheduledAction.ID = action.ID
@@ -213,6 +262,7 @@ func FromDBScheduledActionToCronAction(action DBScheduledAction) *actions.CronAc
 		action.ClusterID,
 		action.Instances,
 	)
+	target.SkippedInstances = action.SkippedInstances
 
 	cronAction := actions.NewCronAction(action.Operation, target, action.Status, action.Enable, action.CronExpression.String)
 	cronAction.ID = action.ID
@@ -5,8 +5,16 @@
 package sqlclient
 
 import (
+	"context"
+	"crypto/x509"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,6 +23,7 @@ import (
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/models"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -50,6 +59,36 @@ func (a SQLClient) GetClusterEvents(clusterID string) ([]models.AuditLog, error)
 	return auditLogs, nil
 }
 
+// AddRequestAuditEntry appends an entry to the requests audit trail.
+//
+// Parameters:
+// - entry: The audit entry to append.
+//
+// Returns:
+// - An error if the insert fails.
+func (a SQLClient) AddRequestAuditEntry(entry events.RequestAuditEntry) error {
+	if _, err := a.db.NamedExec(InsertRequestAuditEntryQuery, entry); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetRequestAuditEntries retrieves the most recent entries of the requests audit trail.
+//
+// Parameters:
+// - limit: Maximum number of entries to retrieve.
+//
+// Returns:
+// - A slice of events.RequestAuditEntry, newest first.
+// - An error if the query fails.
+func (a SQLClient) GetRequestAuditEntries(limit int) ([]events.RequestAuditEntry, error) {
+	var entries []events.RequestAuditEntry
+	if err := a.db.Select(&entries, SelectRequestAuditEntriesQuery, limit); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
 // AddEvent inserts a new audit event into the database and returns the event ID.
 func (a SQLClient) AddEvent(event models.AuditLog) (int64, error) {
 	tx, err := a.db.Beginx()
@@ -119,6 +158,31 @@ func NewSQLClient(dbURL string, logger *zap.Logger) (*SQLClient, error) {
 	}, nil
 }
 
+// NewSQLClientFromDB initializes a new SQLClient on an already opened database
+// connection (e.g. a test double of the PostgreSQL driver).
+//
+// Parameters:
+// - db: The database connection, whose queries use the PostgreSQL syntax.
+// - logger: Logger instance for logging.
+//
+// Returns:
+// - A pointer to an SQLClient instance.
+func NewSQLClientFromDB(db *sql.DB, logger *zap.Logger) *SQLClient {
+	return &SQLClient{
+		db:     sqlx.NewDb(db, "postgres"),
+		logger: logger,
+	}
+}
+
+// SetPoolSize sets the maximum number of open connections to the DB. The
+// requests exceeding it wait for a free connection.
+//
+// Parameters:
+//   - size: Maximum number of open connections. Unlimited if zero
+func (a SQLClient) SetPoolSize(size int) {
+	a.db.SetMaxOpenConns(size)
+}
+
 // Ping performs a ping operation to check if the DB is alive
 //
 // Parameters:
@@ -129,6 +193,202 @@ func (a SQLClient) Ping() error {
 	return a.db.Ping()
 }
 
+// PingContext performs a ping operation bounded by the context deadline
+//
+// Parameters:
+//   - ctx: Context of the ping
+//
+// Returns:
+//   - An error if the ping fails or times out
+func (a SQLClient) PingContext(ctx context.Context) error {
+	return a.db.PingContext(ctx)
+}
+
+// Close closes the DB connection pool. It waits for the running queries to finish.
+//
+// Returns:
+//   - An error if the connections can't be closed
+func (a SQLClient) Close() error {
+	return a.db.Close()
+}
+
+// IsUnavailableError checks if a query failed because the DB can't be reached,
+// instead of because of the query itself
+//
+// Parameters:
+//   - err: Error returned by a query
+//
+// Returns:
+//   - True if the error is a connection error
+func IsUnavailableError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Connection exceptions (08) and operator interventions (57P, e.g. the DB is shutting down)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		code := string(pqErr.Code)
+		return strings.HasPrefix(code, "08") || strings.HasPrefix(code, "57P")
+	}
+
+	return false
+}
+
+// IsTimeoutError checks if a query failed because it exceeded its deadline:
+// the statement timeout of the DB, the connect timeout or the context deadline
+//
+// Parameters:
+//   - err: Error returned by a query
+//
+// Returns:
+//   - True if the error is a timeout error
+func IsTimeoutError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// query_canceled, raised when the statement_timeout is exceeded
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "57014"
+}
+
+// WithTimeouts sets the statement and connect timeouts on a DB connection
+// string, so no query waits for the DB indefinitely. Both the URL
+// ("postgresql://...") and key/value ("host=... port=...") forms are supported,
+// and timeouts already present on the connection string are kept.
+//
+// Parameters:
+//   - dbURL: DB connection string
+//   - timeout: Maximum duration of every statement and connection attempt. Disabled if zero
+//
+// Returns:
+//   - The connection string including the timeouts
+//   - An error if the connection URL can't be parsed
+func WithTimeouts(dbURL string, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		return dbURL, nil
+	}
+
+	params := map[string]string{
+		// Milliseconds
+		"statement_timeout": fmt.Sprint(timeout.Milliseconds()),
+		// Seconds, rounded up as zero would disable it
+		"connect_timeout": fmt.Sprint(int((timeout + time.Second - 1) / time.Second)),
+	}
+	return withConnParams(dbURL, []string{"statement_timeout", "connect_timeout"}, params, false)
+}
+
+// WithTLS enables TLS on a DB connection string. The server certificate is
+// verified against the CA bundle (or the system roots if no bundle is given),
+// unless skipVerify is set. The TLS settings already present on the
+// connection string are replaced.
+//
+// Parameters:
+//   - dbURL: DB connection string
+//   - caFile: Path of the PEM CA bundle of the DB server certificate. Optional
+//   - skipVerify: Encrypts the connection without verifying the server certificate
+//
+// Returns:
+//   - The connection string including the TLS settings
+//   - An error if the CA bundle can't be read or the connection URL can't be parsed
+func WithTLS(dbURL string, caFile string, skipVerify bool) (string, error) {
+	params := map[string]string{"sslmode": "verify-full"}
+	keys := []string{"sslmode"}
+	if skipVerify {
+		params["sslmode"] = "require"
+	} else if caFile != "" {
+		// Checked here, as the driver only reads it on the first connection attempt
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return "", fmt.Errorf("can't read DB CA file: %w", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+			return "", fmt.Errorf("no PEM certificates found on DB CA file '%s'", caFile)
+		}
+		params["sslrootcert"] = caFile
+		keys = append(keys, "sslrootcert")
+	}
+	return withConnParams(dbURL, keys, params, true)
+}
+
+// WithUser sets the user of a DB connection string, replacing the one on it
+// (if any). The password on the connection string is kept.
+//
+// Parameters:
+//   - dbURL: DB connection string
+//   - user: DB user. The connection string is not modified if empty
+//
+// Returns:
+//   - The connection string including the user
+//   - An error if the connection URL can't be parsed
+func WithUser(dbURL string, user string) (string, error) {
+	if user == "" {
+		return dbURL, nil
+	}
+	if !strings.Contains(dbURL, "://") {
+		return withConnParams(dbURL, []string{"user"}, map[string]string{"user": user}, true)
+	}
+
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return "", errors.New("malformed DB URL")
+	}
+	if password, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(user, password)
+	} else {
+		u.User = url.User(user)
+	}
+	return u.String(), nil
+}
+
+// withConnParams sets the given parameters on a DB connection string, on both
+// the URL and key/value forms. The parameters already present on it are only
+// replaced if override is set
+func withConnParams(dbURL string, keys []string, params map[string]string, override bool) (string, error) {
+	if !strings.Contains(dbURL, "://") {
+		for _, key := range keys {
+			if !strings.Contains(dbURL, key+"=") {
+				dbURL += fmt.Sprintf(" %s=%s", key, params[key])
+			} else if override {
+				// The separator before the parameter is kept
+				param := strings.ReplaceAll(fmt.Sprintf("%s=%s", key, params[key]), "$", "$$")
+				dbURL = connParamRegexp(key).ReplaceAllString(dbURL, "${1}"+param)
+			}
+		}
+		return strings.TrimSpace(dbURL), nil
+	}
+
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return "", errors.New("malformed DB URL")
+	}
+	query := u.Query()
+	for _, key := range keys {
+		if override || !query.Has(key) {
+			query.Set(key, params[key])
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// connParamRegexp matches a parameter of a key/value connection string,
+// including its quoted values
+func connParamRegexp(key string) *regexp.Regexp {
+	return regexp.MustCompile(`(^|\s)` + regexp.QuoteMeta(key) + `\s*=\s*('(\\.|[^'])*'|\S*)`)
+}
+
 // GetScheduledActions runs the db select query for retrieving the scheduled actions on the DB
 //
 // Parameters:
@@ -467,6 +727,34 @@ func (a SQLClient) GetExpensesByInstance(instanceID string) ([]inventory.Expense
 	return dbexpenses, nil
 }
 
+// GetClusterInstancesCost retrieves the cost of every instance of a cluster,
+// optionally restricted to a billing window. As the expenses are daily, the
+// window is applied to their dates on the limits' locations.
+//
+// Parameters:
+// - clusterID: The ID of the cluster.
+// - from: First day of the window (included). Unlimited if nil.
+// - to: Last day of the window (excluded). Unlimited if nil.
+//
+// Returns:
+// - A slice of models.InstanceCost sorted by instance name.
+// - An error if the query fails.
+func (a SQLClient) GetClusterInstancesCost(clusterID string, from, to *time.Time) ([]models.InstanceCost, error) {
+	var costs []models.InstanceCost
+	if err := a.db.Select(&costs, SelectClusterInstancesCostQuery, clusterID, dateOrNull(from), dateOrNull(to)); err != nil {
+		return nil, err
+	}
+	return costs, nil
+}
+
+// dateOrNull returns the date (YYYY-MM-DD) of a time on its location, or NULL if nil
+func dateOrNull(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.Format(time.DateOnly), Valid: true}
+}
+
 // WriteExpenses writes a batch of expenses to the database in a transaction.
 //
 // Parameters:
@@ -517,13 +805,52 @@ func (a SQLClient) GetInstances() ([]inventory.Instance, error) {
 	return instances, nil
 }
 
+// GetInstancesWithoutTags retrieves all instances from the database without their tags.
+// It's a lighter alternative to GetInstances when the tags are not needed.
+//
+// Returns:
+// - A slice of inventory.Instance objects.
+// - An error if the query fails.
+func (a SQLClient) GetInstancesWithoutTags() ([]inventory.Instance, error) {
+	var instances []inventory.Instance
+	if err := a.db.Select(&instances, SelectInstancesWithoutTagsQuery); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
 // GetInstancesOverview returns a summary of instances grouped by their status.
 // It provides the total count along with counts of running and stopped instances.
-func (a SQLClient) GetInstancesOverview() (models.InstancesSummary, error) {
+// The instances with the excludeTag tag key are not counted, unless it's empty.
+func (a SQLClient) GetInstancesOverview(excludeTag string) (models.InstancesSummary, error) {
+	return getInstancesOverview(a.db, excludeTag)
+}
+
+// getInstancesOverview runs the instances summary queries on the given DB or
+// transaction, skipping the instances with the excludeTag tag key
+func getInstancesOverview(q sqlx.Queryer, excludeTag string) (models.InstancesSummary, error) {
 	var instances models.InstancesSummary
-	if err := a.db.Get(&instances, SelectInstancesOverview); err != nil {
+	if err := sqlx.Get(q, &instances, SelectInstancesOverview, excludeTag); err != nil {
+		return models.InstancesSummary{}, err
+	}
+
+	byStatus, err := selectCounts(q, SelectInstancesCountByStatusQuery, excludeTag)
+	if err != nil {
+		return models.InstancesSummary{}, err
+	}
+	instances.ByStatus = make(map[inventory.InstanceStatus]int, len(byStatus))
+	for _, row := range byStatus {
+		instances.ByStatus[inventory.InstanceStatus(row.Key)] = row.Count
+	}
+
+	byProvider, err := selectCounts(q, SelectInstancesCountByProviderQuery, excludeTag)
+	if err != nil {
 		return models.InstancesSummary{}, err
 	}
+	instances.ByProvider = make(map[inventory.CloudProvider]int, len(byProvider))
+	for _, row := range byProvider {
+		instances.ByProvider[inventory.CloudProvider(row.Key)] = row.Count
+	}
 
 	return instances, nil
 }
@@ -547,6 +874,33 @@ func (a SQLClient) GetInstanceByID(instanceID string) ([]inventory.Instance, err
 	return instances, nil
 }
 
+// UpdateInstanceProtection sets the protected flag of an instance. Protected
+// instances are skipped by the power actions.
+//
+// Parameters:
+// - instanceID: The ID of the instance.
+// - protected: New value of the flag.
+//
+// Returns:
+// - An error if the query fails or the instance doesn't exist.
+func (a SQLClient) UpdateInstanceProtection(instanceID string, protected bool) error {
+	result, err := a.db.Exec(UpdateInstanceProtectionQuery, protected, instanceID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("instance %s not found", instanceID)
+	}
+
+	a.logger.Debug("Instance protection updated", zap.String("instance_id", instanceID), zap.Bool("protected", protected))
+	return nil
+}
+
 // WriteInstances writes a batch of instances and their tags to the database in a transaction.
 //
 // Parameters:
@@ -632,16 +986,111 @@ func (a SQLClient) GetClusters() ([]inventory.Cluster, error) {
 	return clusters, nil
 }
 
+// GetClustersStatusHistory retrieves the status history of the given
+// clusters, so the whole history table isn't read on every request.
+//
+// Parameters:
+// - clusterIDs: IDs of the clusters.
+//
+// Returns:
+// - A map of status changes sorted by time, indexed by ClusterID.
+// - An error if the query fails.
+func (a SQLClient) GetClustersStatusHistory(clusterIDs []string) (map[string][]inventory.StatusChange, error) {
+	if len(clusterIDs) == 0 {
+		return map[string][]inventory.StatusChange{}, nil
+	}
+	return a.getStatusHistory(SelectClustersStatusHistoryQuery, pq.Array(clusterIDs))
+}
+
+// GetInstancesStatusHistory retrieves the status history of the given
+// instances, so the whole history table isn't read on every request.
+//
+// Parameters:
+// - instanceIDs: IDs of the instances.
+//
+// Returns:
+// - A map of status changes sorted by time, indexed by InstanceID.
+// - An error if the query fails.
+func (a SQLClient) GetInstancesStatusHistory(instanceIDs []string) (map[string][]inventory.StatusChange, error) {
+	if len(instanceIDs) == 0 {
+		return map[string][]inventory.StatusChange{}, nil
+	}
+	return a.getStatusHistory(SelectInstancesStatusHistoryQuery, pq.Array(instanceIDs))
+}
+
+// getStatusHistory runs a status history query and groups its results by resource.
+//
+// Parameters:
+// - query: The status history query to run.
+// - args: Arguments of the query.
+//
+// Returns:
+// - A map of status changes sorted by time, indexed by ResourceID.
+// - An error if the query fails.
+func (a SQLClient) getStatusHistory(query string, args ...any) (map[string][]inventory.StatusChange, error) {
+	var changes []inventory.StatusChange
+	if err := a.db.Select(&changes, query, args...); err != nil {
+		return nil, err
+	}
+
+	history := make(map[string][]inventory.StatusChange)
+	for _, change := range changes {
+		history[change.ResourceID] = append(history[change.ResourceID], change)
+	}
+	return history, nil
+}
+
 // GetClustersOverview returns a summary of cluster statuses
 // It counts the number of clusters that are running, stopped or terminated.
 func (a SQLClient) GetClustersOverview() (models.ClustersSummary, error) {
+	return getClustersOverview(a.db)
+}
+
+// getClustersOverview runs the clusters summary query on the given DB or transaction
+func getClustersOverview(q sqlx.Queryer) (models.ClustersSummary, error) {
 	var clustersOverview models.ClustersSummary
-	if err := a.db.Get(&clustersOverview, SelectClustersOverview); err != nil {
+	if err := sqlx.Get(q, &clustersOverview, SelectClustersOverview); err != nil {
 		return models.ClustersSummary{}, err
 	}
 	return clustersOverview, nil
 }
 
+// GetAccountsOverview returns the number of accounts and the number of active
+// clusters on every account.
+func (a SQLClient) GetAccountsOverview() (models.AccountsSummary, error) {
+	return getAccountsOverview(a.db)
+}
+
+// getAccountsOverview runs the accounts summary query on the given DB or transaction
+func getAccountsOverview(q sqlx.Queryer) (models.AccountsSummary, error) {
+	rows, err := selectCounts(q, SelectClustersCountByAccountQuery)
+	if err != nil {
+		return models.AccountsSummary{}, err
+	}
+
+	summary := models.AccountsSummary{
+		Count:              len(rows),
+		ClustersPerAccount: make(map[string]int, len(rows)),
+	}
+	for _, row := range rows {
+		summary.ClustersPerAccount[row.Key] = row.Count
+	}
+	return summary, nil
+}
+
+// GetInventoryCounts returns the number of accounts, clusters and instances in the inventory.
+//
+// Returns:
+// - A models.InventoryCounts object.
+// - An error if the query fails.
+func (a SQLClient) GetInventoryCounts() (models.InventoryCounts, error) {
+	var counts models.InventoryCounts
+	if err := a.db.Get(&counts, SelectInventoryCountsQuery); err != nil {
+		return models.InventoryCounts{}, err
+	}
+	return counts, nil
+}
+
 // GetClusterAccountName retrieves the account name associated with a specific cluster.
 //
 // Parameters:
@@ -706,6 +1155,51 @@ func (a SQLClient) GetClusterTags(clusterID string) ([]inventory.Tag, error) {
 	return tags, nil
 }
 
+// GetTagKeys retrieves the distinct keys of the instance tags.
+//
+// Returns:
+// - A slice of tag keys sorted by name.
+// - An error if the query fails.
+func (a SQLClient) GetTagKeys() ([]string, error) {
+	var keys []string
+	if err := a.db.Select(&keys, SelectTagKeysQuery); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetTagsByKeys retrieves the instance tags with any of the given keys.
+//
+// Parameters:
+// - keys: The tag keys to retrieve.
+//
+// Returns:
+// - A slice of inventory.Tag objects.
+// - An error if the query fails.
+func (a SQLClient) GetTagsByKeys(keys []string) ([]inventory.Tag, error) {
+	var tags []inventory.Tag
+	if err := a.db.Select(&tags, SelectTagsByKeysQuery, pq.Array(keys)); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetInstanceIDsByTagKey retrieves the ID of every instance tagged with the given key.
+//
+// Parameters:
+// - key: Tag key.
+//
+// Returns:
+// - A slice of instance IDs.
+// - An error if the query fails.
+func (a SQLClient) GetInstanceIDsByTagKey(key string) ([]string, error) {
+	var ids []string
+	if err := a.db.Select(&ids, SelectInstanceIDsByTagKeyQuery, key); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 // GetInstancesOnCluster retrieves all instances belonging to a specific cluster.
 //
 // Parameters:
@@ -722,6 +1216,24 @@ func (a SQLClient) GetInstancesOnCluster(clusterID string) ([]inventory.Instance
 	return instances, nil
 }
 
+// GetInstancesOnClusterByIDs retrieves the instances of a cluster whose ID is on the given list, including their tags.
+//
+// Parameters:
+// - clusterID: The unique identifier of the cluster.
+// - instanceIDs: A slice of instance IDs.
+//
+// Returns:
+// - A slice of inventory.Instance objects belonging to the cluster.
+// - An error if the query fails.
+func (a SQLClient) GetInstancesOnClusterByIDs(clusterID string, instanceIDs []string) ([]inventory.Instance, error) {
+	var dbinstances []models.InstanceDB
+	if err := a.db.Select(&dbinstances, SelectInstancesOnClusterByIDsQuery, clusterID, pq.Array(instanceIDs)); err != nil {
+		return nil, err
+	}
+
+	return joinInstancesTags(dbinstances), nil
+}
+
 // WriteClusters inserts a list of clusters into the database in a transaction.
 //
 // Parameters:
@@ -803,13 +1315,18 @@ func (a SQLClient) GetAccounts() ([]inventory.Account, error) {
 // GetProvidersOverview returns a summary of cloud providers (AWS, GCP, Azure) with
 // their respective account and cluster counts.
 func (a SQLClient) GetProvidersOverview() (models.ProvidersSummary, error) {
+	return getProvidersOverview(a.db)
+}
+
+// getProvidersOverview runs the providers summary query on the given DB or transaction
+func getProvidersOverview(q sqlx.Queryer) (models.ProvidersSummary, error) {
 	var providerRows []struct {
 		Provider     string `db:"provider"`
 		AccountCount int    `db:"account_count"`
 		ClusterCount int    `db:"cluster_count"`
 	}
 
-	if err := a.db.Select(&providerRows, SelectProvidersOverviewQuery); err != nil {
+	if err := sqlx.Select(q, &providerRows, SelectProvidersOverviewQuery); err != nil {
 		return models.ProvidersSummary{}, err
 	}
 
@@ -823,12 +1340,12 @@ func (a SQLClient) GetProvidersOverview() (models.ProvidersSummary, error) {
 			ClusterCount: row.ClusterCount,
 		}
 
-		switch strings.ToLower(row.Provider) {
-		case "aws":
+		switch inventory.GetCloudProvider(row.Provider) {
+		case inventory.AWSProvider:
 			summary.AWS = detail
-		case "gcp":
+		case inventory.GCPProvider:
 			summary.GCP = detail
-		case "azure":
+		case inventory.AzureProvider:
 			summary.Azure = detail
 		}
 	}
@@ -836,7 +1353,22 @@ func (a SQLClient) GetProvidersOverview() (models.ProvidersSummary, error) {
 	return summary, nil
 }
 
-// GetAccountByName retrieves an account by its name from the database.
+// GetProviders retrieves the cloud providers found on the inventory with
+// their account, cluster and instance counts.
+//
+// Returns:
+// - A slice of models.ProviderCounts sorted by provider.
+// - An error if the query fails.
+func (a SQLClient) GetProviders() ([]models.ProviderCounts, error) {
+	var providers []models.ProviderCounts
+	if err := a.db.Select(&providers, SelectProvidersQuery); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// GetAccountByName retrieves an account by its name from the database. If no
+// account has that name, it's resolved as an alias of an account.
 //
 // Parameters:
 // - accountName: The name of the account to retrieve.
@@ -868,6 +1400,22 @@ func (a SQLClient) GetClustersOnAccount(accountName string) ([]inventory.Cluster
 	return clusters, nil
 }
 
+// GetInstancesOnAccount retrieves all instances belonging to the clusters of a specific account.
+//
+// Parameters:
+// - accountName: The name of the account whose instances will be retrieved.
+//
+// Returns:
+// - A slice of inventory.Instance objects.
+// - An error if the query fails.
+func (a SQLClient) GetInstancesOnAccount(accountName string) ([]inventory.Instance, error) {
+	var instances []inventory.Instance
+	if err := a.db.Select(&instances, SelectInstancesOnAccountQuery, accountName); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
 // WriteAccounts inserts multiple accounts into the database in a transaction.
 //
 // Parameters:
@@ -959,24 +1507,30 @@ func (a SQLClient) RefreshInventory() error {
 	return nil
 }
 
-// UpdateClusterStatusByClusterID updates the status of a cluster and all its instances in the database.
+// UpdateClusterStatusByClusterID updates the status of a cluster and its non protected instances in the database.
 //
-// This function first verifies if the requested status exists in the database. If the status is valid, it updates:
+// This function first verifies if the requested statuses exist in the database. If they are valid, it updates:
 // 1. The status of the cluster identified by the given `clusterID`.
-// 2. The status of all instances associated with the cluster.
+// 2. The status of all non protected instances associated with the cluster.
+//
+// The cluster status is given apart from the instances one because protected instances keep their status, and
+// they might keep the cluster on a different status than the rest of its instances.
 //
 // Parameters:
-// - status: The new status to be applied to the cluster and its instances.
+// - clusterStatus: The new status to be applied to the cluster.
+// - instancesStatus: The new status to be applied to the non protected instances of the cluster.
 // - clusterID: The unique identifier of the cluster whose status will be updated.
 //
 // Returns:
-// - An error if the status is invalid, the update operation fails, or no rows are affected.
-func (a SQLClient) UpdateClusterStatusByClusterID(status string, clusterID string) error {
-	// Checking if the requested status is available on the DB
-	if exists, err := a.CheckStatusValue(status); err != nil {
-		return err
-	} else if !exists {
-		return fmt.Errorf("the requested status (%s) doesn't exist on the DB", status)
+// - An error if any status is invalid, the update operation fails, or no rows are affected.
+func (a SQLClient) UpdateClusterStatusByClusterID(clusterStatus string, instancesStatus string, clusterID string) error {
+	// Checking if the requested statuses are available on the DB
+	for _, status := range []string{clusterStatus, instancesStatus} {
+		if exists, err := a.CheckStatusValue(status); err != nil {
+			return err
+		} else if !exists {
+			return fmt.Errorf("the requested status (%s) doesn't exist on the DB", status)
+		}
 	}
 
 	// Updating cluster status
@@ -984,7 +1538,7 @@ func (a SQLClient) UpdateClusterStatusByClusterID(status string, clusterID strin
 		var result sql.Result
 		var err error
 		var rows int64
-		if result, err = a.db.Exec(UpdateStatusClusterByClusterIDQuery, status, clusterID); err != nil {
+		if result, err = a.db.Exec(UpdateStatusClusterByClusterIDQuery, clusterStatus, clusterID); err != nil {
 			return err
 		}
 		if rows, err = result.RowsAffected(); err != nil {
@@ -1001,7 +1555,7 @@ func (a SQLClient) UpdateClusterStatusByClusterID(status string, clusterID strin
 		var result sql.Result
 		var err error
 		var rows int64
-		if result, err = a.db.Exec(UpdateStatusInstancesByClusterIDQuery, status, clusterID); err != nil {
+		if result, err = a.db.Exec(UpdateStatusInstancesByClusterIDQuery, instancesStatus, clusterID); err != nil {
 			return err
 		}
 		if rows, err = result.RowsAffected(); err != nil {
@@ -1032,10 +1586,31 @@ func (a SQLClient) CheckStatusValue(status string) (bool, error) {
 	return exists, nil
 }
 
+// CheckInstanceExists checks if a given instance exists in the database.
+//
+// Parameters:
+// - instanceID: The ID of the instance to check in the database.
+//
+// Returns:
+// - A boolean indicating whether the instance exists (true) or not (false).
+// - An error if the query fails.
+func (a SQLClient) CheckInstanceExists(instanceID string) (bool, error) {
+	var exists bool
+	if err := a.db.QueryRow(CheckInstanceQuery, instanceID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
 // GetScannerLastScanTimestamp returns the latest scan timestamp across all accounts
 func (a SQLClient) GetScannerLastScanTimestamp() (*time.Time, error) {
+	return getScannerLastScanTimestamp(a.db)
+}
+
+// getScannerLastScanTimestamp runs the last scan query on the given DB or transaction
+func getScannerLastScanTimestamp(q sqlx.Queryer) (*time.Time, error) {
 	var lastScanTimestamp sql.NullTime
-	if err := a.db.Get(&lastScanTimestamp, SelectScannerLastScanTimestamp); err != nil {
+	if err := sqlx.Get(q, &lastScanTimestamp, SelectScannerLastScanTimestamp); err != nil {
 		return nil, err
 	}
 	if lastScanTimestamp.Valid {
@@ -1044,15 +1619,77 @@ func (a SQLClient) GetScannerLastScanTimestamp() (*time.Time, error) {
 	return nil, nil
 }
 
+// GetInventoryOverview returns the inventory overview (accounts, clusters,
+// instances, providers and last scan). Every summary is read on the same
+// read-only REPEATABLE READ transaction, so they're consistent with each other
+// even if the scanner is writing the inventory meanwhile.
+//
+// Parameters:
+// - excludeTag: The tag key of the instances not counted (e.g. CIQ_EXCLUDE_TAG). Every instance is counted if empty.
+//
+// Returns:
+// - A models.OverviewSummary object.
+// - An error if any query fails.
+func (a SQLClient) GetInventoryOverview(excludeTag string) (models.OverviewSummary, error) {
+	tx, err := a.db.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return models.OverviewSummary{}, err
+	}
+	// Read-only transaction, so it's always rolled back
+	defer func() {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			a.logger.Error("Failed to rollback GetInventoryOverview transaction", zap.Error(rbErr))
+		}
+	}()
+
+	var overview models.OverviewSummary
+	if overview.Accounts, err = getAccountsOverview(tx); err != nil {
+		return models.OverviewSummary{}, fmt.Errorf("failed to get accounts overview: %w", err)
+	}
+	if overview.Clusters, err = getClustersOverview(tx); err != nil {
+		return models.OverviewSummary{}, fmt.Errorf("failed to get clusters overview: %w", err)
+	}
+	if overview.Instances, err = getInstancesOverview(tx, excludeTag); err != nil {
+		return models.OverviewSummary{}, fmt.Errorf("failed to get instances overview: %w", err)
+	}
+	if overview.Providers, err = getProvidersOverview(tx); err != nil {
+		return models.OverviewSummary{}, fmt.Errorf("failed to get providers overview: %w", err)
+	}
+	lastScan, err := getScannerLastScanTimestamp(tx)
+	if err != nil {
+		return models.OverviewSummary{}, fmt.Errorf("failed to get scanner last scan timestamp: %w", err)
+	}
+	overview.Scanner = models.Scanner{LastScanTimestamp: lastScan}
+
+	return overview, nil
+}
+
+// countRow is a (key, count) row of the counting queries grouped by a column
+type countRow struct {
+	Key   string `db:"key"`
+	Count int    `db:"count"`
+}
+
+// selectCounts runs a counting query grouped by a column
+func selectCounts(q sqlx.Queryer, query string, args ...any) ([]countRow, error) {
+	var rows []countRow
+	if err := sqlx.Select(q, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // joinInstancesTags maps an array of InstanceDB objects into a slice of inventory.Instance objects.
 //
 // Parameters:
 // - dbinstances: A slice of InstanceDB objects.
 //
 // Returns:
-// - A slice of inventory.Instance objects.
+// - A slice of inventory.Instance objects, in the order of their first row.
 func joinInstancesTags(dbinstances []models.InstanceDB) []inventory.Instance {
 	instanceMap := make(map[string]*inventory.Instance)
+	// IDs in query order, as the map iteration order is random
+	var ids []string
 	for _, dbinstance := range dbinstances {
 		if _, ok := instanceMap[dbinstance.ID]; ok {
 			// Adding tag to an already read instance
@@ -1062,6 +1699,7 @@ func joinInstancesTags(dbinstances []models.InstanceDB) []inventory.Instance {
 			)
 		} else {
 			// Adding a new instance to the response
+			ids = append(ids, dbinstance.ID)
 			instanceMap[dbinstance.ID] = inventory.NewInstance(
 				dbinstance.ID,
 				dbinstance.Name,
@@ -1076,13 +1714,18 @@ func joinInstancesTags(dbinstances []models.InstanceDB) []inventory.Instance {
 			// TODO: Implement a method for setting this values OR include them on the builder method
 			instanceMap[dbinstance.ID].TotalCost = dbinstance.TotalCost
 			instanceMap[dbinstance.ID].DailyCost = dbinstance.DailyCost
+			instanceMap[dbinstance.ID].LastScanTimestamp = dbinstance.LastScanTimestamp
+			instanceMap[dbinstance.ID].IAMRole = dbinstance.IAMRole
+			instanceMap[dbinstance.ID].ProviderState = dbinstance.ProviderState
+			instanceMap[dbinstance.ID].Protected = dbinstance.Protected
+			instanceMap[dbinstance.ID].CPUUtilization = dbinstance.CPUUtilization
 		}
 	}
 
-	// Converting map into list
+	// Converting map into list, keeping the query order
 	var instances []inventory.Instance
-	for _, instance := range instanceMap {
-		instances = append(instances, *instance)
+	for _, id := range ids {
+		instances = append(instances, *instanceMap[id])
 	}
 
 	return instances
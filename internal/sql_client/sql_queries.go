@@ -4,7 +4,7 @@ const (
 	SelectScheduledActionsQueryConditionsPlaceholder = "<CONDITIONS>"
 
 	// SelectScheduledActionsQuery returns the list of scheduled actions on the inventory with all the parameters needed for action execution
-	// ARRAY_AGG is used for joining every instance on the same row
+	// ARRAY_AGG is used for joining every instance on the same row. Protected instances are returned apart as skipped
 	SelectScheduledActionsQuery = `
 		SELECT
 			schedule.id,
@@ -17,7 +17,8 @@ const (
 			clusters.id AS cluster_id,
 			clusters.region,
 			clusters.account_name,
-		ARRAY_AGG(instances.id::TEXT) FILTER (WHERE instances IS NOT NULL) AS instances
+		ARRAY_AGG(instances.id::TEXT) FILTER (WHERE instances IS NOT NULL AND NOT instances.protected) AS instances,
+		ARRAY_AGG(instances.id::TEXT) FILTER (WHERE instances IS NOT NULL AND instances.protected) AS skipped_instances
 		FROM schedule
 		JOIN clusters ON schedule.target = clusters.id
 		JOIN instances ON clusters.id = instances.cluster_id
@@ -62,7 +63,8 @@ const (
 			clusters.id AS cluster_id,
 			clusters.region,
 			clusters.account_name,
-		ARRAY_AGG(instances.id::TEXT) FILTER (WHERE instances IS NOT NULL) AS instances
+		ARRAY_AGG(instances.id::TEXT) FILTER (WHERE instances IS NOT NULL AND NOT instances.protected) AS instances,
+		ARRAY_AGG(instances.id::TEXT) FILTER (WHERE instances IS NOT NULL AND instances.protected) AS skipped_instances
 		FROM schedule
 		JOIN clusters ON schedule.target = clusters.id
 		JOIN instances ON clusters.id = instances.cluster_id
@@ -210,6 +212,28 @@ const (
 		ORDER BY date
 	`
 
+	// SelectClusterInstancesCostQuery returns the cost of every instance of a
+	// cluster, summing its expenses dated on the [$2, $3) range. NULL limits
+	// are ignored
+	SelectClusterInstancesCostQuery = `
+		SELECT
+			i.id,
+			i.name,
+			COALESCE(SUM(e.amount), 0) AS cost
+		FROM
+			instances i
+		LEFT JOIN
+			expenses e ON e.instance_id = i.id
+				AND ($2::date IS NULL OR e.date >= $2::date)
+				AND ($3::date IS NULL OR e.date < $3::date)
+		WHERE
+			i.cluster_id = $1
+		GROUP BY
+			i.id, i.name
+		ORDER BY
+			i.name, i.id
+	`
+
 	// InsertExpensesQuery inserts into a new expense for an instance
 	InsertExpensesQuery = `
 		INSERT INTO expenses (
@@ -224,40 +248,104 @@ const (
 			amount = EXCLUDED.amount
 	`
 
-	// SelectInstancesQuery returns every instance in the inventory ordered by ID
+	// SelectInstancesQuery returns every instance in the inventory and its tags ordered by name and ID
 	SelectInstancesQuery = `
 		SELECT * FROM instances
 		JOIN tags ON
 			instances.id = tags.instance_id
-		ORDER BY name
+		ORDER BY instances.name, instances.id, tags.key
 	`
-	// SelectInstancesOverview returns the total count of all instances
+	// SelectClustersStatusHistoryQuery returns the status changes of the $1 clusters ordered by time
+	SelectClustersStatusHistoryQuery = `
+		SELECT
+			cluster_id AS resource_id,
+			status,
+			timestamp
+		FROM cluster_status_history
+		WHERE cluster_id = ANY($1)
+		ORDER BY cluster_id, timestamp
+	`
+
+	// SelectInstancesStatusHistoryQuery returns the status changes of the $1 instances ordered by time
+	SelectInstancesStatusHistoryQuery = `
+		SELECT
+			instance_id AS resource_id,
+			status,
+			timestamp
+		FROM instance_status_history
+		WHERE instance_id = ANY($1)
+		ORDER BY instance_id, timestamp
+	`
+
+	// SelectInstancesWithoutTagsQuery returns every instance in the inventory ordered by ID, without joining their tags
+	SelectInstancesWithoutTagsQuery = `
+		SELECT * FROM instances
+		ORDER BY id
+	`
+
+	// SelectInstancesOverview returns the total count of all instances, but
+	// the ones with the $1 tag key. No instance is skipped if $1 is empty
 	SelectInstancesOverview = `
 		SELECT COUNT(*) as count FROM instances
+		WHERE ` + notExcludedInstanceCondition + `
 	`
 
+	// SelectInstancesCountByStatusQuery returns the number of instances of
+	// every status, but the ones with the $1 tag key
+	SelectInstancesCountByStatusQuery = `
+		SELECT status AS key, COUNT(*) AS count FROM instances
+		WHERE ` + notExcludedInstanceCondition + `
+		GROUP BY status
+	`
+
+	// SelectInstancesCountByProviderQuery returns the number of instances of
+	// every cloud provider, but the ones with the $1 tag key
+	SelectInstancesCountByProviderQuery = `
+		SELECT provider AS key, COUNT(*) AS count FROM instances
+		WHERE ` + notExcludedInstanceCondition + `
+		GROUP BY provider
+	`
+
+	// notExcludedInstanceCondition matches the instances without the $1 tag
+	// key (e.g. CIQ_EXCLUDE_TAG), or every instance if $1 is empty
+	notExcludedInstanceCondition = `(
+			$1 = '' OR NOT EXISTS (
+				SELECT 1 FROM tags
+				WHERE tags.instance_id = instances.id AND tags.key = $1
+			)
+		)`
+
 	// SelectInstancesByIDQuery returns an instance by its ID
 	SelectInstancesByIDQuery = `
 		SELECT * FROM instances
 		JOIN tags ON
 			instances.id = tags.instance_id
 		WHERE id = $1
-		ORDER BY name
+		ORDER BY name, tags.key
 	`
 
 	// SelectClustersQuery returns every cluster in the inventory ordered by Name
+	// and ID, as clusters of different accounts can share the name
 	SelectClustersQuery = `
 		SELECT * FROM clusters
-		ORDER BY name
+		ORDER BY name, id
 	`
 	// SelectClustersOverview returns the number of clusters grouped by status
 	SelectClustersOverview = `
 		SELECT 
+			COUNT(*) AS count,
 			COUNT(CASE WHEN status = 'Running' THEN 1 END) AS running,
 			COUNT(CASE WHEN status = 'Stopped' THEN 1 END) AS stopped,
 			COUNT(CASE WHEN status = 'Terminated' THEN 1 END) AS archived
 		FROM clusters;
 	`
+	// SelectInventoryCountsQuery returns the number of accounts, clusters and instances in the inventory
+	SelectInventoryCountsQuery = `
+		SELECT
+			(SELECT COUNT(*) FROM accounts) AS accounts,
+			(SELECT COUNT(*) FROM clusters) AS clusters,
+			(SELECT COUNT(*) FROM instances) AS instances
+	`
 	// InsertEventQuery insert a new audit event
 	InsertEventQuery = `
 		INSERT INTO audit_logs(
@@ -322,6 +410,32 @@ const (
 		)
 		ORDER BY al.event_timestamp DESC;
 	`
+	// InsertRequestAuditEntryQuery appends a new entry to the requests audit trail
+	InsertRequestAuditEntryQuery = `
+		INSERT INTO request_audit_log(
+			timestamp,
+			actor,
+			method,
+			route,
+			path,
+			resources,
+			status
+		) VALUES (
+			:timestamp,
+			:actor,
+			:method,
+			:route,
+			:path,
+			:resources,
+			:status
+		)
+	`
+	// SelectRequestAuditEntriesQuery returns the most recent entries of the requests audit trail
+	SelectRequestAuditEntriesQuery = `
+		SELECT * FROM request_audit_log
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $1
+	`
 	// UpdateEventStatusQuery updates the result status of an audit log entry based on its ID.
 	UpdateEventStatusQuery = `UPDATE audit_logs SET result=$1 WHERE id=$2`
 	// SelectClusterAccountNameQuery returns an cluster by its Name
@@ -351,6 +465,25 @@ const (
 		WHERE cluster_id = $1
 	`
 
+	// SelectTagsByKeysQuery returns every instance tag whose key is on the given list
+	SelectTagsByKeysQuery = `
+		SELECT key,value,instance_id FROM tags
+		WHERE key = ANY($1)
+		ORDER BY instance_id, key
+	`
+
+	// SelectTagKeysQuery returns the distinct instance tag keys sorted by name
+	SelectTagKeysQuery = `
+		SELECT DISTINCT key FROM tags
+		ORDER BY key
+	`
+
+	// SelectInstanceIDsByTagKeyQuery returns the ID of every instance with a tag key
+	SelectInstanceIDsByTagKeyQuery = `
+		SELECT DISTINCT instance_id FROM tags
+		WHERE key = $1
+	`
+
 	// SelectInstancesOnClusterQuery returns every instance belonging to a cluster
 	SelectInstancesOnClusterQuery = `
 		SELECT * FROM instances
@@ -358,6 +491,15 @@ const (
 		ORDER BY id
 	`
 
+	// SelectInstancesOnClusterByIDsQuery returns the instances of a cluster whose ID is on the given list
+	SelectInstancesOnClusterByIDsQuery = `
+		SELECT * FROM instances
+		JOIN tags ON
+			instances.id = tags.instance_id
+		WHERE cluster_id = $1 AND id = ANY($2)
+		ORDER BY id
+	`
+
 	// SelectAccountsQuery returns every instance in the inventory ordered by Name
 	SelectAccountsQuery = `
 		SELECT * FROM accounts
@@ -382,18 +524,65 @@ const (
 			a.provider;
 	`
 
-	// SelectAccountsByNameQuery returns an instance by its Name
+	// SelectProvidersQuery returns every cloud provider found on the inventory
+	// with its account, cluster and instance counts, excluding "UNKNOWN" and not
+	// counting Terminated clusters and instances
+	SelectProvidersQuery = `
+		SELECT
+			p.provider,
+			(SELECT COUNT(*) FROM accounts a WHERE a.provider = p.provider) AS account_count,
+			(SELECT COUNT(*) FROM clusters c WHERE c.provider = p.provider AND c.status != 'Terminated') AS cluster_count,
+			(SELECT COUNT(*) FROM instances i WHERE i.provider = p.provider AND i.status != 'Terminated') AS instance_count
+		FROM (
+			SELECT provider FROM accounts
+			UNION SELECT provider FROM clusters
+			UNION SELECT provider FROM instances
+		) p
+		WHERE
+			p.provider != 'UNKNOWN'
+		ORDER BY
+			p.provider;
+	`
+
+	// SelectClustersCountByAccountQuery returns the number of active (non
+	// Terminated) clusters of every account, including the ones without clusters
+	SelectClustersCountByAccountQuery = `
+		SELECT
+			a.name AS key,
+			COUNT(CASE WHEN c.status != 'Terminated' THEN c.id END) AS count
+		FROM
+			accounts a
+		LEFT JOIN
+			clusters c ON c.account_name = a.name
+		GROUP BY
+			a.name
+		ORDER BY
+			a.name;
+	`
+
+	// SelectAccountsByNameQuery returns an account by its Name or, if no account
+	// has that Name, by one of its aliases
 	SelectAccountsByNameQuery = `
 		SELECT * FROM accounts
-		WHERE name = $1
-		ORDER BY name
+		WHERE name = $1 OR $1 = ANY(aliases)
+		ORDER BY name = $1 DESC, name
+		LIMIT 1
 	`
 
 	// SelectClustersOnAccountQuery returns an cluster by its Name
 	SelectClustersOnAccountQuery = `
 		SELECT * FROM clusters
 		WHERE account_name = $1
-		ORDER BY name
+		ORDER BY name, id
+	`
+
+	// SelectInstancesOnAccountQuery returns every instance belonging to any cluster of an account
+	SelectInstancesOnAccountQuery = `
+		SELECT instances.* FROM instances
+		JOIN clusters ON
+			instances.cluster_id = clusters.id
+		WHERE clusters.account_name = $1
+		ORDER BY instances.id
 	`
 
 	// InsertInstancesQuery inserts into a new instance in its table
@@ -405,12 +594,15 @@ const (
 			instance_type,
 			availability_zone,
 			status,
+			provider_state,
 			cluster_id,
+			iam_role,
 			last_scan_timestamp,
 			creation_timestamp,
 			age,
 			daily_cost,
-			total_cost
+			total_cost,
+			cpu_utilization
 		) VALUES (
 			:id,
 			:name,
@@ -418,22 +610,28 @@ const (
 			:instance_type,
 			:availability_zone,
 			:status,
+			:provider_state,
 			:cluster_id,
+			:iam_role,
 			:last_scan_timestamp,
 			:creation_timestamp,
 			:age,
 			:daily_cost,
-			:total_cost
+			:total_cost,
+			:cpu_utilization
 		) ON CONFLICT (id) DO UPDATE SET
 			name = EXCLUDED.name,
 			provider = EXCLUDED.provider,
 			instance_type = EXCLUDED.instance_type,
 			availability_zone = EXCLUDED.availability_zone,
 			status = EXCLUDED.status,
+			provider_state = EXCLUDED.provider_state,
 			cluster_id = EXCLUDED.cluster_id,
+			iam_role = EXCLUDED.iam_role,
 			last_scan_timestamp = EXCLUDED.last_scan_timestamp,
 			creation_timestamp = EXCLUDED.creation_timestamp,
-			age = EXCLUDED.age
+			age = EXCLUDED.age,
+			cpu_utilization = EXCLUDED.cpu_utilization
 	`
 
 	// InsertClustersQuery inserts into a new instance in its table
@@ -488,19 +686,31 @@ const (
 			provider,
 			total_cost,
 			cluster_count,
-			last_scan_timestamp
+			last_scan_timestamp,
+			scan_start_timestamp,
+			scan_end_timestamp,
+			throttled_requests,
+			aliases
 		) VALUES (
 			:id,
 			:name,
 			:provider,
 			:total_cost,
 			:cluster_count,
-			:last_scan_timestamp
+			:last_scan_timestamp,
+			:scan_start_timestamp,
+			:scan_end_timestamp,
+			:throttled_requests,
+			COALESCE(:aliases, CAST('{}' AS TEXT[]))
 		) ON CONFLICT (name) DO UPDATE SET
 			id = EXCLUDED.id,
 			provider = EXCLUDED.provider,
 			cluster_count = EXCLUDED.cluster_count,
-			last_scan_timestamp = EXCLUDED.last_scan_timestamp
+			last_scan_timestamp = EXCLUDED.last_scan_timestamp,
+			scan_start_timestamp = EXCLUDED.scan_start_timestamp,
+			scan_end_timestamp = EXCLUDED.scan_end_timestamp,
+			throttled_requests = EXCLUDED.throttled_requests,
+			aliases = EXCLUDED.aliases
 	`
 
 	// InsertTagsQuery inserts into a new tag for an instance
@@ -538,10 +748,15 @@ const (
 	UpdateStatusClusterByClusterIDQuery = `UPDATE clusters SET status=$1 WHERE id=$2`
 
 	// UpdateInstanceStatus updates the status of a  set of instances based on their clusterID
-	UpdateStatusInstancesByClusterIDQuery = `UPDATE instances SET status=$1 WHERE cluster_id=$2`
+	UpdateStatusInstancesByClusterIDQuery = `UPDATE instances SET status=$1 WHERE cluster_id=$2 AND NOT protected`
+
+	// UpdateInstanceProtectionQuery sets the protection flag of an instance. The scanner never writes this column, so it's kept across scans
+	UpdateInstanceProtectionQuery = `UPDATE instances SET protected=$1 WHERE id=$2`
 
 	// CheckStatusQuery checks if the requested status exists on the DB
 	CheckStatusQuery = `SELECT EXISTS (SELECT 1 FROM status WHERE value=$1)`
+	// CheckInstanceQuery checks if the requested instance exists on the DB, with or without tags
+	CheckInstanceQuery = `SELECT EXISTS (SELECT 1 FROM instances WHERE id=$1)`
 	// SelectScannerLastScanTimestamp returns the latest scan timestamp across all accounts
 	SelectScannerLastScanTimestamp = `SELECT MAX(last_scan_timestamp) as last_scan_timestamp FROM accounts;`
 )
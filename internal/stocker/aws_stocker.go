@@ -2,6 +2,7 @@ package stocker
 
 import (
 	"fmt"
+	"time"
 
 	cp "github.com/RHEcosystemAppEng/cluster-iq/internal/cloud_providers/aws"
 	"github.com/RHEcosystemAppEng/cluster-iq/internal/inventory"
@@ -49,6 +50,12 @@ func (s *AWSStocker) Connect() error {
 
 // MakeStock Implements the interface Stocker for triggering the entire process of making stock about a AWS account
 func (s *AWSStocker) MakeStock() error {
+	s.Account.ScanStartTimestamp = time.Now()
+	defer func() {
+		s.Account.ScanEndTimestamp = time.Now()
+		s.Account.ThrottledRequests = s.conn.GetThrottledRequests()
+	}()
+
 	regions, err := s.conn.EC2.GetRegionsList()
 	if err != nil {
 		return err